@@ -75,11 +75,14 @@ CREATE INDEX IF NOT EXISTS idx_trades_account ON trades(account_id);
 CREATE INDEX IF NOT EXISTS idx_trades_symbol ON trades(symbol);
 CREATE INDEX IF NOT EXISTS idx_trades_closed_at ON trades(closed_at);
 
--- Insert initial account (run once)
--- This will create account with ID 1
-INSERT INTO accounts (balance, equity, updated_at, created_at)
-VALUES (10000.0, 10000.0, NOW(), NOW())
-ON CONFLICT DO NOTHING;
+-- Insert initial account
+-- Uses an explicit ID so concurrent or repeated runs do not create duplicates
+INSERT INTO accounts (id, balance, equity, updated_at, created_at)
+VALUES (1, 10000.0, 10000.0, NOW(), NOW())
+ON CONFLICT (id) DO NOTHING;
+
+-- Keep the serial sequence ahead of the explicitly inserted ID
+SELECT setval(pg_get_serial_sequence('accounts', 'id'), GREATEST((SELECT MAX(id) FROM accounts), 1));
 
 -- View for account summary
 CREATE OR REPLACE VIEW account_summary AS
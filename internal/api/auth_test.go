@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authRouter builds bot routes with the given auth environment
+func authRouter(t *testing.T, secret, reads string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CANDLECORE_API_SECRET", secret)
+	t.Setenv("CANDLECORE_API_AUTH_READS", reads)
+
+	router := gin.New()
+	NewBotController(t.TempDir(), websocket.NewHub()).SetupRoutes(router)
+	return router
+}
+
+// signedRequest builds a request carrying an HMAC signature made at signedAt
+func signedRequest(secret, method, path, body string, signedAt time.Time) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, hex.EncodeToString(signRequest(secret, timestamp, method, path, []byte(body))))
+	return req
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	const secret = "s3cret"
+	const path = "/api/v1/bot/configure"
+	const body = `{"symbol":"bitcoin","timeframe":"bogus","strategy":"rsi"}`
+
+	bearer := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+	tampered := signedRequest(secret, http.MethodPost, path, body, time.Now())
+	tampered.Body = http.NoBody
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"missing credentials", httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)), http.StatusUnauthorized},
+		{"valid bearer token", bearer(secret), http.StatusBadRequest},
+		{"invalid bearer token", bearer("guess"), http.StatusUnauthorized},
+		{"valid signature", signedRequest(secret, http.MethodPost, path, body, time.Now()), http.StatusBadRequest},
+		{"wrong secret", signedRequest("guess", http.MethodPost, path, body, time.Now()), http.StatusUnauthorized},
+		{"tampered body", tampered, http.StatusUnauthorized},
+		{"expired signature", signedRequest(secret, http.MethodPost, path, body, time.Now().Add(-time.Hour)), http.StatusUnauthorized},
+	}
+
+	router := authRouter(t, secret, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, tt.req)
+
+			// Authenticated requests reach the handler, which rejects the timeframe
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthReadEndpoints(t *testing.T) {
+	status := func(router *gin.Engine, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/bot/status", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if got := status(authRouter(t, "s3cret", ""), ""); got != http.StatusOK {
+		t.Errorf("open reads: status = %d, want 200", got)
+	}
+
+	gated := authRouter(t, "s3cret", "true")
+	if got := status(gated, ""); got != http.StatusUnauthorized {
+		t.Errorf("gated reads without token: status = %d, want 401", got)
+	}
+	if got := status(gated, "s3cret"); got != http.StatusOK {
+		t.Errorf("gated reads with token: status = %d, want 200", got)
+	}
+
+	// Without a secret the bot API stays open
+	open := authRouter(t, "", "true")
+	rec := httptest.NewRecorder()
+	open.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/bot/stop", nil))
+	if rec.Code == http.StatusUnauthorized {
+		t.Error("stop without a secret configured: got 401")
+	}
+}
+
+func TestCORSAllowsSignatureHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(corsMiddleware())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/api/v1/bot/configure", nil))
+
+	allowed := w.Header().Get("Access-Control-Allow-Headers")
+	for _, header := range []string{"Authorization", timestampHeader, signatureHeader} {
+		if !strings.Contains(allowed, header) {
+			t.Errorf("Access-Control-Allow-Headers = %q, missing %s", allowed, header)
+		}
+	}
+}
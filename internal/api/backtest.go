@@ -0,0 +1,102 @@
+package api
+
+import (
+	"candlecore/internal/backtest"
+	"candlecore/internal/exchange"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// backtestQueueSize bounds how many backtests may wait for a worker
+const backtestQueueSize = 100
+
+// submitBacktest validates a backtest request and enqueues it. The body is
+// a backtest.ReplayRequest; symbol, timeframe and strategy are required.
+func (s *Server) submitBacktest(c *gin.Context) {
+	req := backtest.DefaultReplayRequest()
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Symbol == "" || req.Timeframe == "" || req.Strategy == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol, timeframe and strategy are required"})
+		return
+	}
+
+	timeframe := exchange.Timeframe(req.Timeframe)
+	if !timeframe.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timeframe"})
+		return
+	}
+
+	if _, err := req.BuildStrategy(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.InitialBalance < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "initial_balance must be positive"})
+		return
+	}
+	if req.InitialBalance == 0 {
+		req.InitialBalance = backtest.DefaultInitialBalance
+	}
+
+	if req.TakerFee < 0 || req.TakerFee > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "taker_fee must be between 0 and 1"})
+		return
+	}
+	if req.SlippageBps < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slippage_bps must be non-negative"})
+		return
+	}
+
+	provider, err := exchange.NewProvider(exchange.DataSource(req.DataSource), s.dataDir)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := s.jobs.Submit(func(ctx context.Context) (interface{}, error) {
+		return backtest.RunReplay(ctx, provider, req)
+	})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"id":     id,
+		"status": JobStatusPending,
+	})
+}
+
+// getBacktestResults reports the status and result of a backtest job
+func (s *Server) getBacktestResults(c *gin.Context) {
+	job, ok := s.jobs.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "backtest not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// cancelBacktest cancels a queued or running backtest job
+func (s *Server) cancelBacktest(c *gin.Context) {
+	id := c.Param("id")
+	if _, ok := s.jobs.Get(id); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "backtest not found"})
+		return
+	}
+
+	if err := s.jobs.Cancel(id); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "status": "cancelling"})
+}
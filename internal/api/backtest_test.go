@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeSineCSV writes an hourly oscillating price series so crossovers occur
+func writeSineCSV(t *testing.T, dir, symbol string, n int) {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("timestamp,open,high,low,close,volume\n")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		price := 100 + 10*math.Sin(float64(i)/8)
+		fmt.Fprintf(&b, "%s,%.4f,%.4f,%.4f,%.4f,1000\n",
+			start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339),
+			price, price+1, price-1, price)
+	}
+
+	path := filepath.Join(dir, symbol+"_1h.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+}
+
+func TestSubmitBacktestValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{router: gin.New(), jobs: NewJobManager(1, 10), dataDir: t.TempDir()}
+	defer s.jobs.Shutdown()
+	s.setupRoutes()
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"valid", `{"symbol":"bitcoin","timeframe":"1h","strategy":"rsi"}`, http.StatusAccepted},
+		{"missing strategy", `{"symbol":"bitcoin","timeframe":"1h"}`, http.StatusBadRequest},
+		{"invalid timeframe", `{"symbol":"bitcoin","timeframe":"2h","strategy":"rsi"}`, http.StatusBadRequest},
+		{"unknown strategy", `{"symbol":"bitcoin","timeframe":"1h","strategy":"macd"}`, http.StatusBadRequest},
+		{"negative fee", `{"symbol":"bitcoin","timeframe":"1h","strategy":"rsi","taker_fee":-1}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/backtest", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			s.router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
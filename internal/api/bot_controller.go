@@ -1,10 +1,12 @@
 package api
 
 import (
+	"candlecore/internal/backtest"
 	"candlecore/internal/bot"
+	"candlecore/internal/config"
 	"candlecore/internal/exchange"
-	"candlecore/internal/strategies"
 	"candlecore/internal/websocket"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -20,26 +22,59 @@ type BotController struct {
 	bot          *bot.Bot
 	hub          *websocket.Hub
 	provider     exchange.DataProvider
+	dataDir      string
+	dataSource   exchange.DataSource
 	isRunning    bool
 	replayMode   bool
 	symbol       string
 	timeframe    exchange.Timeframe
 	strategyName string
+	warmup       int // Candles to skip before deciding; 0 derives it from the strategy
+	account      AccountSettings
 	mu           sync.RWMutex
 	stopChan     chan struct{}
+
+	// Secret gating bot control, and whether it also gates reads
+	authSecret string
+	authReads  bool
+
+	// Account used when a configure request omits its fields
+	defaultAccount AccountSettings
 }
 
-// NewBotController creates a new bot controller
-func NewBotController(provider exchange.DataProvider, hub *websocket.Hub) *BotController {
+// AccountSettings describes the simulated account a started bot trades
+type AccountSettings struct {
+	InitialBalance float64
+	TakerFee       float64 // e.g. 0.001 for 0.1%
+	SlippageBps    float64 // e.g. 5 for 0.05%
+}
+
+// NewBotController creates a new bot controller that replays local data
+// from dataDir until another data source is configured
+func NewBotController(dataDir string, hub *websocket.Hub) *BotController {
+	cfg := config.FromEnv()
+	account := AccountSettings{
+		InitialBalance: cfg.InitialBalance,
+		TakerFee:       cfg.TakerFee,
+		SlippageBps:    cfg.SlippageBps,
+	}
+
 	return &BotController{
-		provider:     provider,
+		provider:     exchange.NewLocalFileProvider(dataDir),
+		dataDir:      dataDir,
+		dataSource:   exchange.DataSourceLocal,
 		hub:          hub,
 		isRunning:    false,
 		replayMode:   false,
 		symbol:       "bitcoin",
 		timeframe:    exchange.Timeframe1h,
 		strategyName: "ma_crossover",
+		account:      account,
 		stopChan:     make(chan struct{}),
+		authSecret:   cfg.APISecret,
+		authReads:    cfg.APIAuthReads,
+
+		defaultAccount: account,
 	}
 }
 
@@ -53,32 +88,41 @@ func (bc *BotController) Start() error {
 	}
 
 	// Create strategy
-	var strategy bot.Strategy
-	switch bc.strategyName {
-	case "ma_crossover":
-		strategy = strategies.NewSimpleMAStrategy(10, 30)
-	case "rsi":
-		strategy = strategies.NewRSIStrategy(14, 30, 70)
-	default:
-		return fmt.Errorf("unknown strategy: %s", bc.strategyName)
+	strategy, err := backtest.NewStrategy(bc.strategyName, bc.symbol)
+	if err != nil {
+		return err
 	}
 
-	// Create bot
-	bc.bot = bot.NewBot(strategy, bc.provider, bot.Config{
+	candles, err := bc.openStream()
+	if err != nil {
+		return fmt.Errorf("failed to load candles: %w", err)
+	}
+
+	// The bot reads history from a view that only holds candles already
+	// processed, so decisions never see later data
+	view := backtest.NewReplayProvider(bc.symbol, bc.timeframe, nil)
+	bc.bot = bot.NewBot(strategy, view, bot.Config{
 		Symbol:         bc.symbol,
 		Timeframe:      bc.timeframe,
-		InitialBalance: 10000,
+		InitialBalance: bc.account.InitialBalance,
 		PositionSize:   10,
+		TakerFee:       bc.account.TakerFee,
+		SlippageBps:    bc.account.SlippageBps,
 	})
 
+	warmup := bc.warmup
+	if warmup == 0 {
+		warmup = backtest.WarmupPeriod(strategy)
+	}
+
 	bc.isRunning = true
 	bc.stopChan = make(chan struct{})
 
 	// Start processing
-	go bc.run()
+	go bc.run(candles, view, warmup, bc.stopChan)
 
 	bc.hub.BroadcastStatus("started")
-	log.Printf("Bot started: symbol=%s, timeframe=%s, strategy=%s", bc.symbol, bc.timeframe, bc.strategyName)
+	log.Printf("Bot started: symbol=%s, timeframe=%s, strategy=%s, source=%s", bc.symbol, bc.timeframe, bc.strategyName, bc.dataSource)
 
 	return nil
 }
@@ -94,37 +138,70 @@ func (bc *BotController) Stop() error {
 
 	close(bc.stopChan)
 	bc.isRunning = false
+
+	// A live stream polls until its provider is closed, so end it and
+	// start the next run on a fresh provider
+	if p, ok := bc.provider.(closer); ok {
+		p.Close()
+		if provider, err := exchange.NewProvider(bc.dataSource, bc.dataDir); err == nil {
+			bc.provider = provider
+		}
+	}
 	bc.hub.BroadcastStatus("stopped")
 
 	log.Println("Bot stopped")
 	return nil
 }
 
-// run processes candles and executes strategy
-func (bc *BotController) run() {
-	// Get candles
+// openStream returns the candles to process: a closed snapshot of the
+// stored series for local replay, or the provider's stream for live sources,
+// which stays open until the provider is closed
+func (bc *BotController) openStream() (<-chan exchange.Candle, error) {
+	if bc.dataSource != exchange.DataSourceLocal {
+		return bc.provider.StreamCandles(bc.symbol, bc.timeframe)
+	}
+
 	candles, err := bc.provider.GetCandles(bc.symbol, bc.timeframe, 0)
 	if err != nil {
-		log.Printf("Error loading candles: %v", err)
-		bc.Stop()
-		return
+		return nil, err
+	}
+
+	ch := make(chan exchange.Candle, len(candles))
+	for _, candle := range candles {
+		ch <- candle
 	}
+	close(ch)
 
-	log.Printf("Processing %d candles for %s (%s)", len(candles), bc.symbol, bc.timeframe)
+	return ch, nil
+}
+
+// run processes candles as they arrive and executes strategy once warmup
+// candles have been seen. It returns when stop is closed or the stream ends.
+func (bc *BotController) run(candles <-chan exchange.Candle, view *backtest.ReplayProvider, warmup int, stop chan struct{}) {
+	log.Printf("Processing candles for %s (%s)", bc.symbol, bc.timeframe)
 
-	// Process each candle
-	for i, candle := range candles {
+	seen := 0
+	for {
+		var candle exchange.Candle
 		select {
-		case <-bc.stopChan:
+		case <-stop:
 			return
-		default:
+		case next, ok := <-candles:
+			if !ok {
+				log.Println("Finished processing all candles")
+				bc.Stop()
+				return
+			}
+			candle = next
 		}
 
 		// Broadcast candle
 		bc.hub.BroadcastCandle(candle, bc.symbol, string(bc.timeframe))
 
-		// Process candle (skip first 30 for MA warm-up)
-		if i >= 30 {
+		// Decisions before the strategy has enough history are meaningless
+		view.Push(candle)
+		seen++
+		if seen >= warmup {
 			decision, err := bc.bot.ProcessCandle(candle)
 			if err != nil {
 				log.Printf("Error processing candle: %v", err)
@@ -141,8 +218,9 @@ func (bc *BotController) run() {
 
 			// Broadcast PnL
 			bc.hub.BroadcastPnL(websocket.PnLData{
-				Balance:  bc.bot.GetBalance(),
-				TotalPnL: bc.bot.GetTotalPnL(),
+				Balance:       bc.bot.GetBalance(),
+				TotalPnL:      bc.bot.GetTotalPnL(),
+				UnrealizedPnL: bc.bot.GetUnrealizedPnL(),
 			})
 		}
 
@@ -151,9 +229,6 @@ func (bc *BotController) run() {
 			time.Sleep(100 * time.Millisecond)
 		}
 	}
-
-	log.Println("Finished processing all candles")
-	bc.Stop()
 }
 
 // GetStatus returns bot status
@@ -167,20 +242,27 @@ func (bc *BotController) GetStatus() map[string]interface{} {
 		"timeframe":    bc.timeframe,
 		"strategy":     bc.strategyName,
 		"replay_mode":  bc.replayMode,
+		"data_source":  bc.dataSource,
 	}
 
 	if bc.bot != nil {
 		status["balance"] = bc.bot.GetBalance()
+		status["realized_pnl"] = bc.bot.GetRealizedPnL()
+		status["unrealized_pnl"] = bc.bot.GetUnrealizedPnL()
 		status["total_pnl"] = bc.bot.GetTotalPnL()
+		status["win_rate"] = bc.bot.GetWinRate()
 		status["position"] = bc.bot.GetPosition()
-		status["trades_count"] = len(bc.bot.GetTrades())
+		status["trade_count"] = len(bc.bot.GetTrades())
+		// Deprecated spelling kept for existing clients
+		status["trades_count"] = status["trade_count"]
 	}
 
 	return status
 }
 
-// Configure updates bot configuration
-func (bc *BotController) Configure(symbol string, timeframe exchange.Timeframe, strategy string, replayMode bool) error {
+// Configure updates bot configuration. An empty dataSource selects local
+// files and a zero warmup uses the strategy's own warm-up period.
+func (bc *BotController) Configure(symbol string, timeframe exchange.Timeframe, strategy string, replayMode bool, dataSource exchange.DataSource, warmup int, account AccountSettings) error {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
@@ -188,14 +270,51 @@ func (bc *BotController) Configure(symbol string, timeframe exchange.Timeframe,
 		return fmt.Errorf("cannot configure while bot is running")
 	}
 
+	if warmup < 0 {
+		return fmt.Errorf("warmup_period must not be negative")
+	}
+
+	if account.InitialBalance <= 0 {
+		return fmt.Errorf("initial_balance must be positive")
+	}
+	if account.TakerFee < 0 || account.TakerFee > 1 {
+		return fmt.Errorf("taker_fee must be between 0 and 1")
+	}
+	if account.SlippageBps < 0 {
+		return fmt.Errorf("slippage_bps must be non-negative")
+	}
+
+	if dataSource == "" {
+		dataSource = exchange.DataSourceLocal
+	}
+
+	provider, err := exchange.NewProvider(dataSource, bc.dataDir)
+	if err != nil {
+		return err
+	}
+
+	// The previous provider may still hold streams open
+	if p, ok := bc.provider.(closer); ok {
+		p.Close()
+	}
+
+	bc.provider = provider
+	bc.dataSource = dataSource
 	bc.symbol = symbol
 	bc.timeframe = timeframe
 	bc.strategyName = strategy
 	bc.replayMode = replayMode
+	bc.warmup = warmup
+	bc.account = account
 
 	return nil
 }
 
+// closer is implemented by providers that hold live streams open
+type closer interface {
+	Close()
+}
+
 var upgrader = gorillaws.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins for development
@@ -211,22 +330,76 @@ func (bc *BotController) HandleWebSocket(c *gin.Context) {
 	}
 
 	client := websocket.NewClient(bc.hub, conn)
-	bc.hub.Register <- client
+	if !bc.hub.Register(c.Request.Context(), client) {
+		conn.WriteMessage(gorillaws.CloseMessage,
+			gorillaws.FormatCloseMessage(gorillaws.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return
+	}
 
 	// Start client pumps
 	go client.WritePump()
 	go client.ReadPump()
 }
 
-// SetupRoutes adds bot control routes to the API
+// HandleStream streams hub events as Server-Sent Events for read-only
+// clients. Each event is one data frame holding the same JSON the WebSocket
+// sends; the stream ends when the request is cancelled.
+func (bc *BotController) HandleStream(c *gin.Context) {
+	client := websocket.NewClient(bc.hub, nil)
+	if !bc.hub.Register(c.Request.Context(), client) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "server shutting down"})
+		return
+	}
+	defer bc.hub.Unregister(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-client.Events():
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Error marshaling event: %v", err)
+				continue
+			}
+
+			if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// SetupRoutes adds bot control routes to the API. Start, stop and configure
+// require credentials when CANDLECORE_API_SECRET is set; status, trades and
+// the event streams do too when CANDLECORE_API_AUTH_READS is true.
 func (bc *BotController) SetupRoutes(router *gin.Engine) {
+	requireAuth := authMiddleware(bc.authSecret)
+	readAuth := authMiddleware("")
+	if bc.authReads {
+		readAuth = requireAuth
+	}
+
 	// WebSocket endpoint
-	router.GET("/ws", bc.HandleWebSocket)
+	router.GET("/ws", readAuth, bc.HandleWebSocket)
 
 	// Bot control endpoints
 	api := router.Group("/api/v1/bot")
 	{
-		api.POST("/start", func(c *gin.Context) {
+		api.POST("/start", requireAuth, func(c *gin.Context) {
 			if err := bc.Start(); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
@@ -234,7 +407,7 @@ func (bc *BotController) SetupRoutes(router *gin.Engine) {
 			c.JSON(http.StatusOK, gin.H{"status": "started"})
 		})
 
-		api.POST("/stop", func(c *gin.Context) {
+		api.POST("/stop", requireAuth, func(c *gin.Context) {
 			if err := bc.Stop(); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
@@ -242,18 +415,30 @@ func (bc *BotController) SetupRoutes(router *gin.Engine) {
 			c.JSON(http.StatusOK, gin.H{"status": "stopped"})
 		})
 
-		api.GET("/status", func(c *gin.Context) {
+		api.GET("/stream", readAuth, bc.HandleStream)
+
+		api.GET("/status", readAuth, func(c *gin.Context) {
 			c.JSON(http.StatusOK, bc.GetStatus())
 		})
 
-		api.POST("/configure", func(c *gin.Context) {
+		api.POST("/configure", requireAuth, func(c *gin.Context) {
 			var req struct {
-				Symbol     string `json:"symbol" binding:"required"`
-				Timeframe  string `json:"timeframe" binding:"required"`
-				Strategy   string `json:"strategy" binding:"required"`
-				ReplayMode bool   `json:"replay_mode"`
+				Symbol         string  `json:"symbol" binding:"required"`
+				Timeframe      string  `json:"timeframe" binding:"required"`
+				Strategy       string  `json:"strategy" binding:"required"`
+				ReplayMode     bool    `json:"replay_mode"`
+				DataSource     string  `json:"data_source"`
+				Warmup         int     `json:"warmup_period"`
+				InitialBalance float64 `json:"initial_balance"`
+				TakerFee       float64 `json:"taker_fee"`
+				SlippageBps    float64 `json:"slippage_bps"`
 			}
 
+			// Fields the body omits keep the configured defaults
+			req.InitialBalance = bc.defaultAccount.InitialBalance
+			req.TakerFee = bc.defaultAccount.TakerFee
+			req.SlippageBps = bc.defaultAccount.SlippageBps
+
 			if err := c.ShouldBindJSON(&req); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
@@ -265,7 +450,11 @@ func (bc *BotController) SetupRoutes(router *gin.Engine) {
 				return
 			}
 
-			if err := bc.Configure(req.Symbol, timeframe, req.Strategy, req.ReplayMode); err != nil {
+			if err := bc.Configure(req.Symbol, timeframe, req.Strategy, req.ReplayMode, exchange.DataSource(req.DataSource), req.Warmup, AccountSettings{
+				InitialBalance: req.InitialBalance,
+				TakerFee:       req.TakerFee,
+				SlippageBps:    req.SlippageBps,
+			}); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
@@ -273,7 +462,7 @@ func (bc *BotController) SetupRoutes(router *gin.Engine) {
 			c.JSON(http.StatusOK, gin.H{"status": "configured"})
 		})
 
-		api.GET("/trades", func(c *gin.Context) {
+		api.GET("/trades", readAuth, func(c *gin.Context) {
 			bc.mu.RLock()
 			defer bc.mu.RUnlock()
 
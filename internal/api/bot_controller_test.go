@@ -0,0 +1,388 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"candlecore/internal/config"
+	"candlecore/internal/exchange"
+	"candlecore/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
+)
+
+// readSSEFrame reads one "data:" frame and decodes its event
+func readSSEFrame(t *testing.T, reader *bufio.Reader) websocket.Event {
+	t.Helper()
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			t.Fatalf("frame line = %q, want data prefix", line)
+		}
+
+		var event websocket.Event
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			t.Fatalf("decode frame: %v", err)
+		}
+		return event
+	}
+}
+
+func TestHandleStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := websocket.NewHub()
+	go hub.Run()
+
+	router := gin.New()
+	NewBotController(t.TempDir(), hub).SetupRoutes(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// An event broadcast before connecting arrives through the hub's history
+	hub.BroadcastStatus("started")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(hub.History()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("status event was not buffered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/bot/stream", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/v1/bot/stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if event := readSSEFrame(t, reader); event.Type != websocket.EventTypeStatus {
+		t.Errorf("first event type = %s, want status", event.Type)
+	}
+
+	hub.BroadcastPnL(websocket.PnLData{Balance: 10500, TotalPnL: 500})
+	event := readSSEFrame(t, reader)
+	if event.Type != websocket.EventTypePnL {
+		t.Fatalf("second event type = %s, want pnl", event.Type)
+	}
+	if data, ok := event.Data.(map[string]interface{}); !ok || data["total_pnl"] != 500.0 {
+		t.Errorf("pnl data = %v, want total_pnl 500", event.Data)
+	}
+
+	// Cancelling the request ends the handler; server.Close waits for it
+	cancel()
+}
+
+// decisionCount waits for the bot to finish and counts decision events
+func decisionCount(t *testing.T, bc *BotController, hub *websocket.Hub) int {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		bc.mu.RLock()
+		running := bc.isRunning
+		bc.mu.RUnlock()
+		if !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("bot did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Events are buffered asynchronously; the final stopped status comes last
+	for {
+		history := hub.History()
+		if len(history) > 0 && history[len(history)-1].Type == websocket.EventTypeStatus {
+			count := 0
+			for _, event := range history {
+				if event.Type == websocket.EventTypeDecision {
+					count++
+				}
+			}
+			return count
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stopped status was not buffered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBotControllerWarmup(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		warmup   int
+		want     int
+	}{
+		// 40 candles: MA crossover 10/30 needs 31, RSI 14 needs 15
+		{"ma_crossover", "ma_crossover", 0, 10},
+		{"rsi", "rsi", 0, 26},
+		{"override", "rsi", 35, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeSineCSV(t, dir, "bitcoin", 40)
+
+			hub := websocket.NewHub()
+			go hub.Run()
+			defer hub.Stop()
+
+			bc := NewBotController(dir, hub)
+			if err := bc.Configure("bitcoin", "1h", tt.strategy, false, "", tt.warmup, bc.defaultAccount); err != nil {
+				t.Fatalf("Configure() error: %v", err)
+			}
+			if err := bc.Start(); err != nil {
+				t.Fatalf("Start() error: %v", err)
+			}
+
+			if got := decisionCount(t, bc, hub); got != tt.want {
+				t.Errorf("decisions = %d, want %d", got, tt.want)
+			}
+
+			status := bc.GetStatus()
+			if _, ok := status["trade_count"].(int); !ok || status["trades_count"] != status["trade_count"] {
+				t.Errorf("trade_count = %v, trades_count = %v, want the same count", status["trade_count"], status["trades_count"])
+			}
+		})
+	}
+
+	bc := NewBotController(t.TempDir(), websocket.NewHub())
+	if err := bc.Configure("bitcoin", "1h", "rsi", false, "", -1, bc.defaultAccount); err == nil {
+		t.Error("Configure() with negative warmup expected error")
+	}
+}
+
+// streamProvider serves candles sent on a channel and records how it is used
+type streamProvider struct {
+	candles chan exchange.Candle
+
+	mu      sync.Mutex
+	fetches int
+	closed  bool
+}
+
+func (p *streamProvider) GetCandles(symbol string, timeframe exchange.Timeframe, limit int) ([]exchange.Candle, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetches++
+	return nil, fmt.Errorf("history is not served")
+}
+
+func (p *streamProvider) StreamCandles(symbol string, timeframe exchange.Timeframe) (<-chan exchange.Candle, error) {
+	return p.candles, nil
+}
+
+func (p *streamProvider) GetSupportedTimeframes() []exchange.Timeframe {
+	return []exchange.Timeframe{exchange.Timeframe1h}
+}
+
+func (p *streamProvider) GetSupportedSymbols() []string {
+	return []string{"bitcoin"}
+}
+
+func (p *streamProvider) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+}
+
+func (p *streamProvider) state() (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fetches, p.closed
+}
+
+func TestBotControllerStreamsLiveCandles(t *testing.T) {
+	hub := websocket.NewHub()
+	go hub.Run()
+	defer hub.Stop()
+
+	bc := NewBotController(t.TempDir(), hub)
+	if err := bc.Configure("bitcoin", "1h", "rsi", false, exchange.DataSourceBinance, 0, bc.defaultAccount); err != nil {
+		t.Fatalf("Configure() error: %v", err)
+	}
+	live := &streamProvider{candles: make(chan exchange.Candle)}
+	bc.provider = live
+
+	if err := bc.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	// Every send blocks until the bot takes the candle, so all 40 have
+	// been handed over once the loop ends
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 40; i++ {
+		price := 100 + 10*math.Sin(float64(i)/8)
+		live.candles <- exchange.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price + 1,
+			Low:       price - 1,
+			Close:     price,
+			Volume:    1000,
+		}
+	}
+
+	// An idle live stream keeps the bot running
+	time.Sleep(50 * time.Millisecond)
+	if running := bc.GetStatus()["running"]; running != true {
+		t.Fatalf("running = %v after the stream went idle, want true", running)
+	}
+	if err := bc.Stop(); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+
+	// RSI 14 warms up on 15 candles, leaving 26 decisions
+	if got := decisionCount(t, bc, hub); got != 26 {
+		t.Errorf("decisions = %d, want 26", got)
+	}
+
+	fetches, closed := live.state()
+	if fetches != 0 {
+		t.Errorf("bot fetched history from the live provider %d times, want 0", fetches)
+	}
+	if !closed {
+		t.Error("Stop() did not close the live provider")
+	}
+}
+
+func TestConfigureClosesPreviousProvider(t *testing.T) {
+	bc := NewBotController(t.TempDir(), websocket.NewHub())
+	live := &streamProvider{candles: make(chan exchange.Candle)}
+	bc.provider = live
+
+	if err := bc.Configure("bitcoin", "1h", "rsi", false, exchange.DataSourceLocal, 0, bc.defaultAccount); err != nil {
+		t.Fatalf("Configure() error: %v", err)
+	}
+	if _, closed := live.state(); !closed {
+		t.Error("Configure() did not close the previous provider")
+	}
+}
+
+func TestConfigureAccountSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	defaults := config.Default()
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		want       AccountSettings
+	}{
+		{
+			name:       "defaults",
+			body:       `{"symbol":"bitcoin","timeframe":"1h","strategy":"rsi"}`,
+			wantStatus: http.StatusOK,
+			want:       AccountSettings{InitialBalance: defaults.InitialBalance, TakerFee: defaults.TakerFee, SlippageBps: defaults.SlippageBps},
+		},
+		{
+			name:       "explicit",
+			body:       `{"symbol":"bitcoin","timeframe":"1h","strategy":"rsi","initial_balance":500,"taker_fee":0,"slippage_bps":0}`,
+			wantStatus: http.StatusOK,
+			want:       AccountSettings{InitialBalance: 500},
+		},
+		{
+			name:       "negative fee",
+			body:       `{"symbol":"bitcoin","timeframe":"1h","strategy":"rsi","taker_fee":-0.1}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "zero balance",
+			body:       `{"symbol":"bitcoin","timeframe":"1h","strategy":"rsi","initial_balance":0}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := NewBotController(t.TempDir(), websocket.NewHub())
+			router := gin.New()
+			bc.SetupRoutes(router)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/bot/configure", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && bc.account != tt.want {
+				t.Errorf("account = %+v, want %+v", bc.account, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlersReturnAfterHubStop(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := websocket.NewHub()
+	stopped := make(chan struct{})
+	go func() {
+		hub.Run()
+		close(stopped)
+	}()
+	hub.Stop()
+	<-stopped
+
+	router := gin.New()
+	NewBotController(t.TempDir(), hub).SetupRoutes(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(server.URL + "/api/v1/bot/stream")
+	if err != nil {
+		t.Fatalf("GET /stream after Stop: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GET /stream status = %d, want 503", resp.StatusCode)
+	}
+
+	// The WebSocket handler closes the connection instead of hanging
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("dial /ws after Stop: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); !gorillaws.IsCloseError(err, gorillaws.CloseGoingAway) {
+		t.Errorf("read /ws after Stop: err = %v, want going-away close", err)
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"candlecore/internal/backtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxEquityPoints caps how many equity points one request returns
+const maxEquityPoints = 1000
+
+// getBacktestEquity returns a page of a finished backtest's equity curve.
+// offset and limit select the page; limit defaults to and is capped at
+// maxEquityPoints.
+func (s *Server) getBacktestEquity(c *gin.Context) {
+	id := c.Param("id")
+	job, ok := s.jobs.Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "backtest not found"})
+		return
+	}
+
+	result, ok := job.Result.(*backtest.ReplayResult)
+	if job.Status != JobStatusCompleted || !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "backtest has not completed", "status": job.Status})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(maxEquityPoints)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+	if limit > maxEquityPoints {
+		limit = maxEquityPoints
+	}
+
+	points := result.Equity
+	total := len(points)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":     id,
+		"total":  total,
+		"offset": offset,
+		"limit":  limit,
+		"points": points[offset:end],
+	})
+}
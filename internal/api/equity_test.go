@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"candlecore/internal/backtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetBacktestEquityPaginates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{router: gin.New(), jobs: NewJobManager(1, 10)}
+	defer s.jobs.Shutdown()
+	s.setupRoutes()
+
+	points := make([]backtest.EquityPoint, maxEquityPoints+5)
+	for i := range points {
+		points[i].Equity = float64(i)
+	}
+	id, err := s.jobs.Submit(func(ctx context.Context) (interface{}, error) {
+		return &backtest.ReplayResult{Equity: points}, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() error: %v", err)
+	}
+	waitForStatus(t, s.jobs, id, JobStatusCompleted)
+
+	get := func(query string) (int, map[string]json.RawMessage) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/backtest/results/"+id+"/equity"+query, nil)
+		s.router.ServeHTTP(rec, req)
+
+		var body map[string]json.RawMessage
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, body
+	}
+
+	code, body := get("")
+	var page []backtest.EquityPoint
+	json.Unmarshal(body["points"], &page)
+	if code != http.StatusOK || len(page) != maxEquityPoints {
+		t.Fatalf("default page: status %d with %d points, want 200 with %d", code, len(page), maxEquityPoints)
+	}
+	if string(body["total"]) != "1005" {
+		t.Errorf("total = %s, want 1005", body["total"])
+	}
+
+	_, body = get("?offset=1000&limit=10")
+	json.Unmarshal(body["points"], &page)
+	if len(page) != 5 || page[0].Equity != 1000 {
+		t.Errorf("last page = %d points starting at %v, want 5 starting at 1000", len(page), page)
+	}
+
+	if code, _ := get("?limit=0"); code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want 400", code)
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/backtest/results/missing/equity", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown id status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"candlecore/internal/exchange"
+	"candlecore/internal/indicators"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRSIPeriod matches the rsi strategy's default period
+const defaultRSIPeriod = 14
+
+// IndicatorPoint holds an indicator's values at one candle. Single-line
+// indicators use the indicator type as key; macd has macd, signal and
+// histogram and bbands has upper, middle and lower.
+type IndicatorPoint struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Values    map[string]float64 `json:"values"`
+}
+
+// getIndicators computes an indicator over a coin's stored candles. The
+// series starts at the first candle with enough history, so each point's
+// timestamp is the candle it was computed at.
+func (s *Server) getIndicators(c *gin.Context) {
+	coin := c.Param("coin")
+	timeframe := exchange.Timeframe(c.Param("interval"))
+	if !timeframe.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interval"})
+		return
+	}
+
+	kind := c.Query("type")
+	series, err := indicatorSeries(kind, c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	candles, err := exchange.NewLocalFileProvider(s.dataDir).GetCandles(coin, timeframe, 0)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+
+	lines, err := series(closes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Every line ends at the last candle; the shortest sets the start
+	length := len(candles)
+	for _, values := range lines {
+		if len(values) < length {
+			length = len(values)
+		}
+	}
+
+	offset := len(candles) - length
+	points := make([]IndicatorPoint, length)
+	for i := range points {
+		point := IndicatorPoint{
+			Timestamp: candles[offset+i].Timestamp,
+			Values:    make(map[string]float64, len(lines)),
+		}
+		for name, values := range lines {
+			point.Values[name] = values[len(values)-length+i]
+		}
+		points[i] = point
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"coin":     coin,
+		"interval": timeframe,
+		"type":     kind,
+		"points":   points,
+	})
+}
+
+// indicatorSeries validates the query parameters for an indicator type and
+// returns a function computing its named lines from closes
+func indicatorSeries(kind string, c *gin.Context) (func([]float64) (map[string][]float64, error), error) {
+	switch kind {
+	case "sma", "ema", "rsi":
+		defaultPeriod := 20
+		if kind == "rsi" {
+			defaultPeriod = defaultRSIPeriod
+		}
+		period, err := positiveIntQuery(c, "period", defaultPeriod)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(closes []float64) (map[string][]float64, error) {
+			var values []float64
+			var err error
+			switch kind {
+			case "sma":
+				values, err = indicators.SMA(closes, period)
+			case "ema":
+				values, err = indicators.EMA(closes, period)
+			default:
+				values, err = indicators.RSI(closes, period)
+			}
+			return map[string][]float64{kind: values}, err
+		}, nil
+
+	case "macd":
+		fast, err := positiveIntQuery(c, "fast", 12)
+		if err != nil {
+			return nil, err
+		}
+		slow, err := positiveIntQuery(c, "slow", 26)
+		if err != nil {
+			return nil, err
+		}
+		signal, err := positiveIntQuery(c, "signal", 9)
+		if err != nil {
+			return nil, err
+		}
+		if fast >= slow {
+			return nil, fmt.Errorf("fast must be less than slow")
+		}
+
+		return func(closes []float64) (map[string][]float64, error) {
+			result, err := indicators.MACD(closes, fast, slow, signal)
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]float64{"macd": result.MACD, "signal": result.Signal, "histogram": result.Histogram}, nil
+		}, nil
+
+	case "bbands":
+		period, err := positiveIntQuery(c, "period", 20)
+		if err != nil {
+			return nil, err
+		}
+		stdDev, err := strconv.ParseFloat(c.DefaultQuery("stddev", "2"), 64)
+		if err != nil || stdDev <= 0 {
+			return nil, fmt.Errorf("stddev must be a positive number")
+		}
+
+		return func(closes []float64) (map[string][]float64, error) {
+			result, err := indicators.BollingerBands(closes, period, stdDev)
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]float64{"upper": result.Upper, "middle": result.Middle, "lower": result.Lower}, nil
+		}, nil
+
+	case "":
+		return nil, fmt.Errorf("type is required (sma, ema, rsi, macd, bbands)")
+	default:
+		return nil, fmt.Errorf("unknown indicator type: %s", kind)
+	}
+}
+
+// positiveIntQuery parses an optional positive integer query parameter
+func positiveIntQuery(c *gin.Context, name string, fallback int) (int, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return value, nil
+}
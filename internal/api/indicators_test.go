@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"candlecore/internal/indicators"
+
+	"github.com/gin-gonic/gin"
+)
+
+// indicatorResponse is the decoded indicators endpoint body
+type indicatorResponse struct {
+	Points []IndicatorPoint `json:"points"`
+}
+
+// getIndicatorsFrom serves one indicators request against bitcoin_1h data
+func getIndicatorsFrom(t *testing.T, dir, query string) (*httptest.ResponseRecorder, indicatorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	s := &Server{router: gin.New(), dataDir: dir}
+	s.setupRoutes()
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/data/bitcoin/1h/indicators?"+query, nil))
+
+	var body indicatorResponse
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return rec, body
+}
+
+// sineCloses matches the closes writeSineCSV writes
+func sineCloses(n int) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = math.Round((100+10*math.Sin(float64(i)/8))*1e4) / 1e4
+	}
+	return closes
+}
+
+func TestGetIndicatorsRSI(t *testing.T) {
+	dir := t.TempDir()
+	writeSineCSV(t, dir, "bitcoin", 50)
+
+	rec, body := getIndicatorsFrom(t, dir, "type=rsi&period=14")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	want, _ := indicators.RSI(sineCloses(50), 14)
+	if len(body.Points) != len(want) {
+		t.Fatalf("points = %d, want %d", len(body.Points), len(want))
+	}
+	// The first RSI needs 15 candles, so it lands on hour 14
+	if hour := body.Points[0].Timestamp.Hour(); hour != 14 {
+		t.Errorf("first point at hour %d, want 14", hour)
+	}
+	for i, point := range body.Points {
+		if math.Abs(point.Values["rsi"]-want[i]) > 1e-9 {
+			t.Fatalf("point %d rsi = %.6f, want %.6f", i, point.Values["rsi"], want[i])
+		}
+	}
+}
+
+func TestGetIndicatorsMACD(t *testing.T) {
+	dir := t.TempDir()
+	writeSineCSV(t, dir, "bitcoin", 60)
+
+	rec, body := getIndicatorsFrom(t, dir, "type=macd&fast=5&slow=10&signal=4")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	want, _ := indicators.MACD(sineCloses(60), 5, 10, 4)
+	if len(body.Points) != len(want.Signal) {
+		t.Fatalf("points = %d, want %d", len(body.Points), len(want.Signal))
+	}
+	if first := body.Points[0].Timestamp.Hour(); first != 60-len(want.Signal) {
+		t.Errorf("first point at hour %d, want %d", first, 60-len(want.Signal))
+	}
+	last := body.Points[len(body.Points)-1].Values
+	n := len(want.Signal) - 1
+	if math.Abs(last["macd"]-want.MACD[n]) > 1e-9 || math.Abs(last["signal"]-want.Signal[n]) > 1e-9 ||
+		math.Abs(last["histogram"]-want.Histogram[n]) > 1e-9 {
+		t.Errorf("last point = %v, want macd %.6f signal %.6f histogram %.6f", last, want.MACD[n], want.Signal[n], want.Histogram[n])
+	}
+}
+
+func TestGetIndicatorsValidation(t *testing.T) {
+	dir := t.TempDir()
+	writeSineCSV(t, dir, "bitcoin", 30)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusBadRequest},
+		{"type=stoch", http.StatusBadRequest},
+		{"type=rsi&period=0", http.StatusBadRequest},
+		{"type=sma&period=abc", http.StatusBadRequest},
+		{"type=macd&fast=26&slow=12", http.StatusBadRequest},
+		{"type=bbands&stddev=-1", http.StatusBadRequest},
+		{"type=sma&period=100", http.StatusBadRequest},
+		{"type=bbands", http.StatusOK},
+		{"type=ema&period=5", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if rec, _ := getIndicatorsFrom(t, dir, tt.query); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	if rec, _ := getIndicatorsFrom(t, t.TempDir(), "type=rsi"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing data status = %d, want 404", rec.Code)
+	}
+}
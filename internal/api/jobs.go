@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus represents the lifecycle state of a background job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// defaultMaxFinishedJobs is how many finished jobs are kept for lookup
+// before the oldest are evicted
+const defaultMaxFinishedJobs = 100
+
+// JobFunc performs the work of a job and returns its result
+// Implementations must return promptly once ctx is cancelled
+type JobFunc func(ctx context.Context) (interface{}, error)
+
+// Job is a snapshot of a submitted job
+type Job struct {
+	ID         string      `json:"id"`
+	Status     JobStatus   `json:"status"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+// jobEntry is the internal record of a job
+type jobEntry struct {
+	job    Job
+	fn     JobFunc
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// JobManager runs jobs on a bounded pool of workers. Finished jobs stay
+// available to Get until more than maxFinished jobs have finished after
+// them.
+type JobManager struct {
+	mu     sync.RWMutex
+	jobs   map[string]*jobEntry
+	queue  chan *jobEntry
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// finished holds finished job IDs, oldest first, for eviction
+	finished    []string
+	maxFinished int
+}
+
+// NewJobManager creates a job manager with the given number of workers
+// queueSize bounds how many jobs may wait for a free worker
+func NewJobManager(workers, queueSize int) *JobManager {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize <= 0 {
+		queueSize = workers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &JobManager{
+		jobs:        make(map[string]*jobEntry),
+		queue:       make(chan *jobEntry, queueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+		maxFinished: defaultMaxFinishedJobs,
+	}
+
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+
+	return m
+}
+
+// SetMaxFinished sets how many finished jobs are kept before the oldest are
+// evicted. Values below one keep a single finished job.
+func (m *JobManager) SetMaxFinished(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxFinished = n
+	m.evictFinished()
+}
+
+// Submit enqueues a job and returns its ID without waiting for it to run
+func (m *JobManager) Submit(fn JobFunc) (string, error) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	entry := &jobEntry{
+		job: Job{
+			ID:        uuid.New().String(),
+			Status:    JobStatusPending,
+			CreatedAt: time.Now(),
+		},
+		fn:     fn,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx.Err() != nil {
+		cancel()
+		return "", fmt.Errorf("job manager is shut down")
+	}
+
+	select {
+	case m.queue <- entry:
+	default:
+		cancel()
+		return "", fmt.Errorf("job queue is full")
+	}
+
+	m.jobs[entry.job.ID] = entry
+	return entry.job.ID, nil
+}
+
+// Get returns a snapshot of the job with the given ID
+func (m *JobManager) Get(id string) (Job, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return entry.job, true
+}
+
+// Cancel stops a queued or running job
+func (m *JobManager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: %s", id)
+	}
+
+	switch entry.job.Status {
+	case JobStatusPending:
+		now := time.Now()
+		entry.job.Status = JobStatusCancelled
+		entry.job.FinishedAt = &now
+		m.recordFinished(entry)
+	case JobStatusRunning:
+		// The worker records the final status once the job returns
+	default:
+		return fmt.Errorf("job %s already finished with status %s", id, entry.job.Status)
+	}
+
+	entry.cancel()
+	return nil
+}
+
+// Shutdown cancels all outstanding jobs and waits for workers to exit
+func (m *JobManager) Shutdown() {
+	m.mu.Lock()
+	now := time.Now()
+	for _, entry := range m.jobs {
+		if entry.job.Status == JobStatusPending {
+			entry.job.Status = JobStatusCancelled
+			entry.job.FinishedAt = &now
+			m.recordFinished(entry)
+		}
+	}
+	m.cancel()
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// worker executes queued jobs until the manager is shut down
+func (m *JobManager) worker() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case entry := <-m.queue:
+			m.execute(entry)
+		}
+	}
+}
+
+// execute runs a single job and records its outcome
+func (m *JobManager) execute(entry *jobEntry) {
+	m.mu.Lock()
+	if entry.job.Status != JobStatusPending {
+		m.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	entry.job.Status = JobStatusRunning
+	entry.job.StartedAt = &now
+	m.mu.Unlock()
+
+	result, err := entry.fn(entry.ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	finished := time.Now()
+	entry.job.FinishedAt = &finished
+
+	switch {
+	case entry.ctx.Err() != nil:
+		entry.job.Status = JobStatusCancelled
+	case err != nil:
+		entry.job.Status = JobStatusFailed
+		entry.job.Error = err.Error()
+	default:
+		entry.job.Status = JobStatusCompleted
+		entry.job.Result = result
+	}
+
+	entry.cancel()
+	m.recordFinished(entry)
+}
+
+// recordFinished notes that a job finished and evicts the oldest finished
+// jobs beyond the limit. Callers must hold m.mu.
+func (m *JobManager) recordFinished(entry *jobEntry) {
+	m.finished = append(m.finished, entry.job.ID)
+	m.evictFinished()
+}
+
+// evictFinished drops the oldest finished jobs beyond maxFinished. Callers
+// must hold m.mu.
+func (m *JobManager) evictFinished() {
+	excess := len(m.finished) - m.maxFinished
+	if excess <= 0 {
+		return
+	}
+	for _, id := range m.finished[:excess] {
+		delete(m.jobs, id)
+	}
+	m.finished = append(m.finished[:0], m.finished[excess:]...)
+}
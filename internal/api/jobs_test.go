@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForStatus polls until the job reaches the wanted status
+func waitForStatus(t *testing.T, m *JobManager, id string, want JobStatus) Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, ok := m.Get(id)
+		if !ok {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.Status == want {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	job, _ := m.Get(id)
+	t.Fatalf("job %s status = %s, want %s", id, job.Status, want)
+	return job
+}
+
+func TestJobManagerBoundsConcurrency(t *testing.T) {
+	m := NewJobManager(2, 10)
+	defer m.Shutdown()
+
+	var running, peak int32
+	release := make(chan struct{})
+
+	ids := make([]string, 5)
+	for i := range ids {
+		id, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+			return "ok", nil
+		})
+		if err != nil {
+			t.Fatalf("Submit() error: %v", err)
+		}
+		ids[i] = id
+	}
+
+	waitForStatus(t, m, ids[0], JobStatusRunning)
+	waitForStatus(t, m, ids[1], JobStatusRunning)
+	if job, _ := m.Get(ids[4]); job.Status != JobStatusPending {
+		t.Errorf("fifth job status = %s, want %s", job.Status, JobStatusPending)
+	}
+
+	close(release)
+	for _, id := range ids {
+		job := waitForStatus(t, m, id, JobStatusCompleted)
+		if job.Result != "ok" {
+			t.Errorf("job result = %v, want ok", job.Result)
+		}
+	}
+
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestJobManagerCancel(t *testing.T) {
+	m := NewJobManager(1, 10)
+	defer m.Shutdown()
+
+	running, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Submit() error: %v", err)
+	}
+
+	var ran int32
+	queued, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+		atomic.StoreInt32(&ran, 1)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() error: %v", err)
+	}
+
+	waitForStatus(t, m, running, JobStatusRunning)
+
+	if err := m.Cancel(queued); err != nil {
+		t.Fatalf("Cancel(queued) error: %v", err)
+	}
+	waitForStatus(t, m, queued, JobStatusCancelled)
+
+	if err := m.Cancel(running); err != nil {
+		t.Fatalf("Cancel(running) error: %v", err)
+	}
+	waitForStatus(t, m, running, JobStatusCancelled)
+
+	if atomic.LoadInt32(&ran) != 0 {
+		t.Error("cancelled queued job was executed")
+	}
+
+	if err := m.Cancel(running); err == nil {
+		t.Error("Cancel() on finished job expected error")
+	}
+	if err := m.Cancel("missing"); err == nil {
+		t.Error("Cancel() on unknown job expected error")
+	}
+}
+
+func TestJobManagerFailure(t *testing.T) {
+	m := NewJobManager(1, 1)
+	defer m.Shutdown()
+
+	id, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("no data")
+	})
+	if err != nil {
+		t.Fatalf("Submit() error: %v", err)
+	}
+
+	job := waitForStatus(t, m, id, JobStatusFailed)
+	if job.Error != "no data" {
+		t.Errorf("job error = %q, want %q", job.Error, "no data")
+	}
+}
+
+func TestJobManagerEvictsOldestFinishedJobs(t *testing.T) {
+	m := NewJobManager(1, 10)
+	defer m.Shutdown()
+	m.SetMaxFinished(2)
+
+	ids := make([]string, 3)
+	for i := range ids {
+		id, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		})
+		if err != nil {
+			t.Fatalf("Submit() error: %v", err)
+		}
+		ids[i] = id
+		waitForStatus(t, m, id, JobStatusCompleted)
+	}
+
+	if _, ok := m.Get(ids[0]); ok {
+		t.Error("oldest finished job still present, want evicted")
+	}
+	for _, id := range ids[1:] {
+		if _, ok := m.Get(id); !ok {
+			t.Errorf("job %s evicted, want kept", id)
+		}
+	}
+
+	// Lowering the limit evicts immediately
+	m.SetMaxFinished(1)
+	if _, ok := m.Get(ids[1]); ok {
+		t.Error("job kept after lowering the limit, want evicted")
+	}
+	if _, ok := m.Get(ids[2]); !ok {
+		t.Error("newest finished job evicted, want kept")
+	}
+}
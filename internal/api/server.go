@@ -3,22 +3,31 @@ package api
 import (
 	"candlecore/internal/exchange"
 	ws "candlecore/internal/websocket"
+	"context"
+	"errors"
+	"log"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to drain
+const shutdownTimeout = 10 * time.Second
+
 // Server represents the API server
 type Server struct {
 	router     *gin.Engine
 	dataDir    string
 	hub        *ws.Hub
 	controller *BotController
+	jobs       *JobManager
 }
 
 // NewServer creates a new API server
-func NewServer(dataDir string) *Server {
+// backtestWorkers bounds how many backtests run concurrently
+func NewServer(dataDir string, backtestWorkers int) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	
 	router := gin.Default()
@@ -28,17 +37,15 @@ func NewServer(dataDir string) *Server {
 	hub := ws.NewHub()
 	go hub.Run()
 	
-	// Create exchange provider
-	provider := exchange.NewLocalFileProvider(dataDir)
-	
 	// Create bot controller
-	controller := NewBotController(provider, hub)
+	controller := NewBotController(dataDir, hub)
 	
 	s := &Server{
 		router:     router,
 		dataDir:    dataDir,
 		hub:        hub,
 		controller: controller,
+		jobs:       NewJobManager(backtestWorkers, backtestQueueSize),
 	}
 	
 	s.setupRoutes()
@@ -57,12 +64,68 @@ func (s *Server) setupRoutes() {
 		// Available symbols and timeframes
 		api.GET("/symbols", s.getSymbols)
 		api.GET("/timeframes", s.getTimeframes)
+
+		// Indicators computed over stored candles
+		api.GET("/data/:coin/:interval/indicators", s.getIndicators)
+
+		// Backtests run asynchronously on the job pool
+		api.POST("/backtest", s.submitBacktest)
+		api.GET("/backtest/results/:id", s.getBacktestResults)
+		api.GET("/backtest/results/:id/equity", s.getBacktestEquity)
+		api.DELETE("/backtest/:id", s.cancelBacktest)
+	}
+}
+
+// Run serves the API on port until ctx is cancelled, then shuts down
+// gracefully
+func (s *Server) Run(ctx context.Context, port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on listener until ctx is cancelled. Shutdown stops
+// the bot, closes WebSocket clients and event streams, waits up to
+// shutdownTimeout for other requests to finish and cancels running backtests.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	srv := &http.Server{Handler: s.router}
+
+	// Hijacked WebSocket connections and event streams never go idle on
+	// their own, so the hub closes them as soon as shutdown begins
+	srv.RegisterOnShutdown(s.hub.Stop)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.stop()
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down API server...")
+	s.controller.Stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	s.stop()
+
+	if serveErr := <-serveErr; !errors.Is(serveErr, http.ErrServerClosed) && err == nil {
+		err = serveErr
 	}
+	return err
 }
 
-// Run starts the API server
-func (s *Server) Run(port string) error {
-	return s.router.Run(":" + port)
+// stop releases the hub and the backtest workers
+func (s *Server) stop() {
+	s.hub.Stop()
+	s.jobs.Shutdown()
 }
 
 // corsMiddleware enables CORS for frontend access
@@ -70,7 +133,7 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+timestampHeader+", "+signatureHeader)
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestServeShutsDownGracefully(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	server := NewServer(t.TempDir(), 2)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	url := "http://" + listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, listener)
+	}()
+
+	transport := &http.Transport{}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(url + "/api/v1/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /health status = %d, want 200", resp.StatusCode)
+	}
+
+	// An open event stream must not hold up shutdown
+	stream, err := client.Get(url + "/api/v1/bot/stream")
+	if err != nil {
+		t.Fatalf("GET /stream: %v", err)
+	}
+	defer stream.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve() error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after cancel")
+	}
+
+	if _, err := io.ReadAll(stream.Body); err != nil {
+		t.Errorf("stream did not end cleanly: %v", err)
+	}
+	if _, err := client.Get(url + "/api/v1/health"); err == nil {
+		t.Error("request after shutdown succeeded")
+	}
+	transport.CloseIdleConnections()
+
+	// The hub, job workers and connection goroutines all exit
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("goroutines = %d, want at most %d:\n%s", runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
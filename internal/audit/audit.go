@@ -0,0 +1,244 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+// EventType identifies an order lifecycle event
+type EventType string
+
+const (
+	EventPlaced    EventType = "placed"
+	EventFilled    EventType = "filled"
+	EventRejected  EventType = "rejected"
+	EventCancelled EventType = "cancelled"
+)
+
+// Event is a single audit record. Seq increases by one per record and Hash
+// chains each record to the previous one, so edits, deletions, and
+// reordering are detectable with Verify.
+type Event struct {
+	Seq         uint64             `json:"seq"`
+	Time        time.Time          `json:"time"`
+	Type        EventType          `json:"type"`
+	OrderID     string             `json:"order_id,omitempty"`
+	Symbol      string             `json:"symbol,omitempty"`
+	Side        engine.OrderSide   `json:"side,omitempty"`
+	OrderType   engine.OrderType   `json:"order_type,omitempty"`
+	Status      engine.OrderStatus `json:"status,omitempty"`
+	Quantity    float64            `json:"quantity"`
+	Price       float64            `json:"price"`
+	FilledPrice float64            `json:"filled_price"`
+	FilledQty   float64            `json:"filled_qty"`
+	Fee         float64            `json:"fee"`
+	Error       string             `json:"error,omitempty"`
+	PrevHash    string             `json:"prev_hash"`
+	Hash        string             `json:"hash"`
+}
+
+// OrderAuditor records order lifecycle events. cause is set for rejections.
+type OrderAuditor interface {
+	Audit(event EventType, order *engine.Order, cause error) error
+}
+
+// FileAuditor appends hash-chained events to a JSONL file
+type FileAuditor struct {
+	mu       sync.Mutex
+	file     *os.File
+	seq      uint64
+	lastHash string
+	now      func() time.Time
+}
+
+// OpenFile opens or creates an audit log at path. An existing log is
+// verified and the chain continues from its last record.
+func OpenFile(path string) (*FileAuditor, error) {
+	seq, lastHash, err := Verify(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return &FileAuditor{
+		file:     file,
+		seq:      seq,
+		lastHash: lastHash,
+		now:      time.Now,
+	}, nil
+}
+
+// Audit appends an event for the order and syncs it to disk
+func (a *FileAuditor) Audit(eventType EventType, order *engine.Order, cause error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	event := Event{
+		Seq:         a.seq + 1,
+		Time:        a.now().UTC(),
+		Type:        eventType,
+		OrderID:     order.ID,
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		OrderType:   order.Type,
+		Status:      order.Status,
+		Quantity:    order.Quantity,
+		Price:       order.Price,
+		FilledPrice: order.FilledPrice,
+		FilledQty:   order.FilledQty,
+		Fee:         order.Fee,
+		PrevHash:    a.lastHash,
+	}
+	if cause != nil {
+		event.Error = cause.Error()
+	}
+
+	hash, err := hashEvent(event)
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	if err := a.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	a.seq = event.Seq
+	a.lastHash = event.Hash
+	return nil
+}
+
+// Close closes the audit log
+func (a *FileAuditor) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
+
+// Verify checks the sequence and hash chain of an audit log and returns the
+// last sequence number and hash
+func Verify(path string) (uint64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	var seq uint64
+	var lastHash string
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return 0, "", fmt.Errorf("audit record %d: %w", seq+1, err)
+		}
+
+		if event.Seq != seq+1 {
+			return 0, "", fmt.Errorf("audit record %d: sequence %d out of order", seq+1, event.Seq)
+		}
+		if event.PrevHash != lastHash {
+			return 0, "", fmt.Errorf("audit record %d: broken hash chain", event.Seq)
+		}
+
+		want, err := hashEvent(event)
+		if err != nil {
+			return 0, "", err
+		}
+		if event.Hash != want {
+			return 0, "", fmt.Errorf("audit record %d: hash mismatch", event.Seq)
+		}
+
+		seq = event.Seq
+		lastHash = event.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, "", fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return seq, lastHash, nil
+}
+
+// hashEvent hashes the event with its Hash field cleared
+func hashEvent(event Event) (string, error) {
+	event.Hash = ""
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Broker wraps an engine.Broker and records every order it handles
+type Broker struct {
+	engine.Broker
+	auditor OrderAuditor
+}
+
+// NewBroker returns a broker that audits order placement, fills,
+// rejections, and cancellations before returning to the caller
+func NewBroker(broker engine.Broker, auditor OrderAuditor) *Broker {
+	return &Broker{Broker: broker, auditor: auditor}
+}
+
+// PlaceOrder records the placement, submits the order, and records its
+// outcome. Orders are not submitted if the placement cannot be recorded.
+func (b *Broker) PlaceOrder(order *engine.Order) error {
+	if err := b.auditor.Audit(EventPlaced, order, nil); err != nil {
+		return fmt.Errorf("order not placed: %w", err)
+	}
+
+	placeErr := b.Broker.PlaceOrder(order)
+
+	var event EventType
+	switch {
+	case placeErr != nil, order.Status == engine.OrderStatusRejected:
+		event = EventRejected
+	case order.Status == engine.OrderStatusFilled:
+		event = EventFilled
+	case order.Status == engine.OrderStatusCancelled:
+		event = EventCancelled
+	default:
+		return placeErr
+	}
+
+	if err := b.auditor.Audit(event, order, placeErr); err != nil {
+		if placeErr != nil {
+			return fmt.Errorf("%w (audit failed: %v)", placeErr, err)
+		}
+		return err
+	}
+
+	return placeErr
+}
+
+// CancelOrder cancels the order and records the cancellation
+func (b *Broker) CancelOrder(orderID string) error {
+	if err := b.Broker.CancelOrder(orderID); err != nil {
+		return err
+	}
+	return b.auditor.Audit(EventCancelled, &engine.Order{ID: orderID, Status: engine.OrderStatusCancelled}, nil)
+}
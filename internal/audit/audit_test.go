@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"candlecore/internal/engine"
+)
+
+// stubBroker fills or rejects orders based on quantity
+type stubBroker struct{}
+
+func (stubBroker) GetAccount() *engine.Account                    { return &engine.Account{} }
+func (stubBroker) CancelOrder(orderID string) error               { return nil }
+func (stubBroker) UpdateMarketPrice(symbol string, price float64) {}
+func (stubBroker) GetPosition(symbol string) *engine.Position     { return nil }
+
+func (stubBroker) PlaceOrder(order *engine.Order) error {
+	if order.Quantity <= 0 {
+		order.Status = engine.OrderStatusRejected
+		return errors.New("invalid quantity")
+	}
+	order.Status = engine.OrderStatusFilled
+	order.FilledPrice = order.Price
+	order.FilledQty = order.Quantity
+	return nil
+}
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestBrokerAuditsOrderLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditor, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error: %v", err)
+	}
+
+	broker := NewBroker(stubBroker{}, auditor)
+	if err := broker.PlaceOrder(&engine.Order{ID: "a", Symbol: "BTC/USD", Quantity: 1, Price: 100}); err != nil {
+		t.Fatalf("PlaceOrder() error: %v", err)
+	}
+	if err := broker.PlaceOrder(&engine.Order{ID: "b", Symbol: "BTC/USD", Quantity: 0, Price: 100}); err == nil {
+		t.Fatal("PlaceOrder() expected rejection")
+	}
+	if err := broker.CancelOrder("c"); err != nil {
+		t.Fatalf("CancelOrder() error: %v", err)
+	}
+	auditor.Close()
+
+	events := readEvents(t, path)
+	want := []EventType{EventPlaced, EventFilled, EventPlaced, EventRejected, EventCancelled}
+	if len(events) != len(want) {
+		t.Fatalf("events = %d, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Type != want[i] {
+			t.Errorf("event %d type = %s, want %s", i, e.Type, want[i])
+		}
+		if e.Seq != uint64(i+1) {
+			t.Errorf("event %d seq = %d, want %d", i, e.Seq, i+1)
+		}
+	}
+	if events[3].Error != "invalid quantity" {
+		t.Errorf("rejection error = %q, want %q", events[3].Error, "invalid quantity")
+	}
+
+	// Reopening continues the chain
+	auditor, err = OpenFile(path)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	if err := auditor.Audit(EventCancelled, &engine.Order{ID: "d"}, nil); err != nil {
+		t.Fatalf("Audit() error: %v", err)
+	}
+	auditor.Close()
+
+	seq, _, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if seq != 6 {
+		t.Errorf("last seq = %d, want 6", seq)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditor, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error: %v", err)
+	}
+	broker := NewBroker(stubBroker{}, auditor)
+	for i := 0; i < 3; i++ {
+		if err := broker.PlaceOrder(&engine.Order{Symbol: "BTC/USD", Quantity: 1, Price: 100}); err != nil {
+			t.Fatalf("PlaceOrder() error: %v", err)
+		}
+	}
+	auditor.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	tampered := strings.Replace(string(data), `"quantity":1`, `"quantity":2`, 1)
+	if err := os.WriteFile(path, []byte(tampered), 0644); err != nil {
+		t.Fatalf("write audit log: %v", err)
+	}
+
+	if _, _, err := Verify(path); err == nil {
+		t.Fatal("Verify() expected error for tampered log")
+	}
+	if _, err := OpenFile(path); err == nil {
+		t.Fatal("OpenFile() expected error for tampered log")
+	}
+}
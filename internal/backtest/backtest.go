@@ -0,0 +1,217 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"candlecore/internal/audit"
+	"candlecore/internal/engine"
+	"candlecore/internal/logger"
+	"candlecore/internal/metrics"
+)
+
+// DefaultInitialBalance is used when Config.InitialBalance is zero
+const DefaultInitialBalance = 10000.0
+
+// Params is one combination of strategy parameters, e.g. {"fast": 10, "slow": 30}
+type Params map[string]interface{}
+
+// StrategyFactory builds a fresh strategy for a parameter combination.
+// It returns an error for combinations the strategy cannot run with.
+type StrategyFactory func(params Params) (engine.Strategy, error)
+
+// Config holds settings shared by every harness run
+type Config struct {
+	// InitialBalance is the quote currency each run starts with
+	InitialBalance float64
+
+	// Objective ranks optimization results; empty means ObjectiveNetPnL
+	Objective Objective
+
+	// Workers bounds how many runs Optimize executes at once; zero uses 4
+	Workers int
+
+	// Auditor, when set, records every order the broker handles. Runs
+	// started by Optimize share it.
+	Auditor audit.OrderAuditor
+
+	// Logger receives engine logs; nil discards them
+	Logger logger.Logger
+}
+
+// initialBalance returns the configured balance or the default
+func (c Config) initialBalance() float64 {
+	if c.InitialBalance > 0 {
+		return c.InitialBalance
+	}
+	return DefaultInitialBalance
+}
+
+// Result summarizes one backtest run
+type Result struct {
+	Params         Params          `json:"params"`
+	Candles        int             `json:"candles"`
+	InitialBalance float64         `json:"initial_balance"`
+	FinalBalance   float64         `json:"final_balance"`
+	NetPnL         float64         `json:"net_pnl"`
+	TradeCount     int             `json:"trade_count"`
+	Sharpe         float64         `json:"sharpe"`
+	ProfitFactor   float64         `json:"profit_factor"` // 0 without losing trades; ranked first by profit_factor when profitable
+	Rejected       int             `json:"rejected"`
+	Trades         []*engine.Trade `json:"-"`
+
+	// RejectedOrders lists the orders the broker refused, oldest first,
+	// with their RejectReason set
+	RejectedOrders []*engine.Order `json:"-"`
+}
+
+// Run backtests strategy over candles on a fresh engine and cash broker.
+// Positions still open after the last candle are closed at its close so
+// every run ends flat and its P&L is fully realized.
+func Run(ctx context.Context, candles []engine.Candle, strategy engine.Strategy, cfg Config) (*Result, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candles to backtest")
+	}
+
+	balance := cfg.initialBalance()
+	broker := newCashBroker(balance)
+	e := cfg.newEngine(broker, strategy)
+	if err := e.Run(ctx, candles); err != nil {
+		return nil, err
+	}
+
+	return newResult(broker, balance, len(candles)), nil
+}
+
+// newEngine builds an engine trading through broker, audited when an
+// Auditor is set, that closes its positions when the run ends
+func (c Config) newEngine(broker *cashBroker, strategy engine.Strategy, observers ...engine.Observer) *engine.Engine {
+	var orders engine.Broker = broker
+	if c.Auditor != nil {
+		orders = audit.NewBroker(broker, c.Auditor)
+	}
+
+	var log logger.Logger = quietLogger{}
+	if c.Logger != nil {
+		log = c.Logger
+	}
+
+	e := engine.New(orders, strategy, nopStore{}, log, observers...)
+	e.SetCloseOnFinish(true)
+	return e
+}
+
+// newResult summarizes a finished run on broker
+func newResult(broker *cashBroker, balance float64, candles int) *Result {
+	trades := broker.trades
+	return &Result{
+		Candles:        candles,
+		InitialBalance: balance,
+		FinalBalance:   broker.balance,
+		NetPnL:         broker.balance - balance,
+		TradeCount:     len(trades),
+		Sharpe:         metrics.SharpeRatio(trades, 0),
+		ProfitFactor:   profitFactor(trades),
+		Rejected:       len(broker.RejectedOrders()),
+		Trades:         trades,
+		RejectedOrders: broker.RejectedOrders(),
+	}
+}
+
+// profitFactor returns gross profit over gross loss, or 0 without losses
+func profitFactor(trades []*engine.Trade) float64 {
+	profit, loss := 0.0, 0.0
+	for _, t := range trades {
+		if t.NetPnL > 0 {
+			profit += t.NetPnL
+		} else {
+			loss -= t.NetPnL
+		}
+	}
+	if loss == 0 {
+		return 0
+	}
+	return profit / loss
+}
+
+// WindowResult is one walk-forward step: the parameters that did best
+// in-sample and how they then performed on the following unseen candles
+type WindowResult struct {
+	Window        int       `json:"window"`
+	InSampleFrom  time.Time `json:"in_sample_from"`
+	InSampleTo    time.Time `json:"in_sample_to"`
+	OutSampleFrom time.Time `json:"out_sample_from"`
+	OutSampleTo   time.Time `json:"out_sample_to"`
+	BestParams    Params    `json:"best_params"`
+	InSample      *Result   `json:"in_sample"`
+	OutOfSample   *Result   `json:"out_of_sample"`
+}
+
+// WalkForward splits candles into windows+1 equal segments and, for each
+// window i, optimizes over paramGrid on segment i (in-sample) by
+// Config.Objective and then runs the best parameters on segment i+1
+// (out-of-sample). Every run starts with a fresh strategy, so strategies with a warm-up
+// spend the start of each segment warming up. Combinations the factory
+// rejects are skipped.
+func WalkForward(ctx context.Context, candles []engine.Candle, windows int, factory StrategyFactory, paramGrid []Params, cfg Config) ([]WindowResult, error) {
+	if windows <= 0 {
+		return nil, fmt.Errorf("windows must be positive")
+	}
+	if len(paramGrid) == 0 {
+		return nil, fmt.Errorf("parameter grid is empty")
+	}
+
+	segment := len(candles) / (windows + 1)
+	if segment < 2 {
+		return nil, fmt.Errorf("insufficient data: %d candles for %d windows", len(candles), windows)
+	}
+
+	results := make([]WindowResult, 0, windows)
+	for w := 0; w < windows; w++ {
+		inSample := candles[w*segment : (w+1)*segment]
+		outSample := candles[(w+1)*segment : (w+2)*segment]
+		if w == windows-1 {
+			// The last out-of-sample segment absorbs the remainder
+			outSample = candles[(w+1)*segment:]
+		}
+
+		ranked, err := Optimize(ctx, inSample, factory, paramGrid, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("window %d: %w", w, err)
+		}
+		best := ranked[0]
+
+		strategy, err := factory(best.Params)
+		if err != nil {
+			return nil, err
+		}
+		oos, err := Run(ctx, outSample, strategy, cfg)
+		if err != nil {
+			return nil, err
+		}
+		oos.Params = best.Params
+
+		results = append(results, WindowResult{
+			Window:        w,
+			InSampleFrom:  inSample[0].Timestamp,
+			InSampleTo:    inSample[len(inSample)-1].Timestamp,
+			OutSampleFrom: outSample[0].Timestamp,
+			OutSampleTo:   outSample[len(outSample)-1].Timestamp,
+			BestParams:    best.Params,
+			InSample:      best,
+			OutOfSample:   oos,
+		})
+	}
+
+	return results, nil
+}
+
+// quietLogger discards engine logs; harnesses run many backtests and
+// report through their results instead
+type quietLogger struct{}
+
+func (quietLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (quietLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (quietLogger) Warn(msg string, keysAndValues ...interface{})  {}
+func (quietLogger) Error(msg string, keysAndValues ...interface{}) {}
@@ -0,0 +1,180 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"candlecore/internal/audit"
+	"candlecore/internal/engine"
+	"candlecore/internal/strategy"
+)
+
+// sineCandles builds hourly candles oscillating around 100 with a slow drift
+func sineCandles(n int, drift float64) []engine.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]engine.Candle, n)
+	for i := range candles {
+		price := 100 + 10*math.Sin(float64(i)/6) + drift*float64(i)
+		candles[i] = engine.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price + 0.5,
+			Low:       price - 0.5,
+			Close:     price,
+			Volume:    1000,
+		}
+	}
+	return candles
+}
+
+// macdFactory builds MACD strategies from "fast" and "slow" params
+func macdFactory(params Params) (engine.Strategy, error) {
+	fast, _ := params["fast"].(int)
+	slow, _ := params["slow"].(int)
+	if fast <= 0 || fast >= slow {
+		return nil, fmt.Errorf("fast must be positive and below slow")
+	}
+	return strategy.NewMACDStrategy("", fast, slow, 3, 1000), nil
+}
+
+func TestRunEndsFlat(t *testing.T) {
+	candles := sineCandles(200, 0)
+	result, err := Run(context.Background(), candles, strategy.NewMACDStrategy("", 3, 8, 3, 1000), Config{})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if result.TradeCount == 0 {
+		t.Fatal("expected trades on an oscillating series")
+	}
+	net := 0.0
+	for _, trade := range result.Trades {
+		net += trade.NetPnL
+	}
+	if math.Abs(result.FinalBalance-(DefaultInitialBalance+net)) > 1e-6 {
+		t.Errorf("FinalBalance = %.4f, want initial plus realized P&L %.4f", result.FinalBalance, DefaultInitialBalance+net)
+	}
+	if math.Abs(result.NetPnL-net) > 1e-6 {
+		t.Errorf("NetPnL = %.4f, want %.4f", result.NetPnL, net)
+	}
+}
+
+func TestRunReportsRejectedOrders(t *testing.T) {
+	// 50 cannot buy one unit near 100, so every entry is refused
+	candles := sineCandles(10, 0)
+	result, err := Run(context.Background(), candles, &exitStrategy{exits: map[int]bool{3: true}}, Config{InitialBalance: 50})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if result.TradeCount != 0 {
+		t.Errorf("TradeCount = %d, want 0", result.TradeCount)
+	}
+	if result.Rejected != 2 || len(result.RejectedOrders) != 2 {
+		t.Fatalf("Rejected = %d with %d orders, want 2 buys", result.Rejected, len(result.RejectedOrders))
+	}
+	for i, order := range result.RejectedOrders {
+		if order.Side != engine.OrderSideBuy || order.Status != engine.OrderStatusRejected || order.RejectReason == "" {
+			t.Errorf("rejected order %d = %s %s %q, want a rejected buy with a reason", i, order.Side, order.Status, order.RejectReason)
+		}
+	}
+}
+
+func TestRunAuditsOrders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditor, err := audit.OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error: %v", err)
+	}
+	defer auditor.Close()
+
+	candles := sineCandles(200, 0)
+	result, err := Run(context.Background(), candles, strategy.NewMACDStrategy("", 3, 8, 3, 1000), Config{Auditor: auditor})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.TradeCount == 0 || result.Rejected != 0 {
+		t.Fatalf("TradeCount = %d, Rejected = %d, want trades and no rejections", result.TradeCount, result.Rejected)
+	}
+
+	// Every trade is a buy and a sell, each recorded as placed then filled
+	seq, _, err := audit.Verify(path)
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if want := uint64(4 * result.TradeCount); seq != want {
+		t.Errorf("audit records = %d, want %d", seq, want)
+	}
+}
+
+func TestWalkForward(t *testing.T) {
+	candles := sineCandles(400, 0.02)
+	grid := []Params{
+		{"fast": 3, "slow": 8},
+		{"fast": 5, "slow": 13},
+		{"fast": 12, "slow": 26},
+		{"fast": 10, "slow": 5}, // invalid, skipped
+	}
+
+	results, err := WalkForward(context.Background(), candles, 3, macdFactory, grid, Config{InitialBalance: 5000})
+	if err != nil {
+		t.Fatalf("WalkForward() error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("windows = %d, want 3", len(results))
+	}
+
+	for i, w := range results {
+		if !w.OutSampleFrom.After(w.InSampleTo) {
+			t.Errorf("window %d: out-of-sample starts %v, not after in-sample end %v", i, w.OutSampleFrom, w.InSampleTo)
+		}
+		if i > 0 && !w.InSampleFrom.Equal(results[i-1].OutSampleFrom) {
+			t.Errorf("window %d: in-sample should roll onto the previous out-of-sample segment", i)
+		}
+		if w.BestParams["fast"] == 10 {
+			t.Errorf("window %d picked the invalid combination", i)
+		}
+
+		// The chosen params beat every other valid combination in-sample
+		inSample := candles[i*100 : (i+1)*100]
+		for _, params := range grid[:3] {
+			s, _ := macdFactory(params)
+			r, err := Run(context.Background(), inSample, s, Config{InitialBalance: 5000})
+			if err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			if r.NetPnL > w.InSample.NetPnL+1e-9 {
+				t.Errorf("window %d: %v made %.2f in-sample, more than best %v with %.2f",
+					i, params, r.NetPnL, w.BestParams, w.InSample.NetPnL)
+			}
+		}
+		if w.OutOfSample == nil || w.OutOfSample.InitialBalance != 5000 {
+			t.Errorf("window %d: missing out-of-sample result", i)
+		}
+	}
+	if results[2].OutOfSample.Candles != 100 {
+		t.Errorf("last out-of-sample candles = %d, want 100", results[2].OutOfSample.Candles)
+	}
+}
+
+func TestWalkForwardErrors(t *testing.T) {
+	candles := sineCandles(100, 0)
+	grid := []Params{{"fast": 3, "slow": 8}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WalkForward(ctx, candles, 2, macdFactory, grid, Config{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled WalkForward() error = %v, want context.Canceled", err)
+	}
+	if _, err := WalkForward(context.Background(), candles, 0, macdFactory, grid, Config{}); err == nil {
+		t.Error("expected error for zero windows")
+	}
+	if _, err := WalkForward(context.Background(), candles, 2, macdFactory, []Params{{"fast": 9, "slow": 3}}, Config{}); err == nil {
+		t.Error("expected error when every combination is invalid")
+	}
+}
@@ -0,0 +1,163 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"candlecore/internal/engine"
+)
+
+// cashBroker is an in-memory spot broker for harness runs. Market orders
+// fill in full at the order price without fees or slippage; buys beyond the
+// available balance are rejected and sells are capped at the position.
+type cashBroker struct {
+	balance   float64
+	positions map[string]*engine.Position
+	trades    []*engine.Trade
+	rejected  []*engine.Order
+	nextID    int
+}
+
+// newCashBroker creates a broker holding balance in quote currency
+func newCashBroker(balance float64) *cashBroker {
+	return &cashBroker{
+		balance:   balance,
+		positions: make(map[string]*engine.Position),
+	}
+}
+
+// GetAccount returns a snapshot of balance, equity and open positions.
+// Positions are ordered by symbol so runs are reproducible.
+func (b *cashBroker) GetAccount() *engine.Account {
+	account := &engine.Account{
+		Balance:      b.balance,
+		Equity:       b.balance,
+		TradeHistory: b.trades,
+	}
+	for _, symbol := range sortedSymbols(b.positions) {
+		p := b.positions[symbol]
+		account.Positions = append(account.Positions, p)
+		account.Equity += p.CurrentPrice * p.Quantity
+	}
+	return account
+}
+
+// sortedSymbols returns the position symbols in ascending order
+func sortedSymbols(positions map[string]*engine.Position) []string {
+	symbols := make([]string, 0, len(positions))
+	for symbol := range positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// PlaceOrder fills a market order immediately
+func (b *cashBroker) PlaceOrder(order *engine.Order) error {
+	b.nextID++
+	if order.ID == "" {
+		order.ID = fmt.Sprintf("order-%d", b.nextID)
+	}
+
+	switch order.Side {
+	case engine.OrderSideBuy:
+		cost := order.Price * order.Quantity
+		if order.Quantity <= 0 {
+			return b.reject(order, "invalid quantity: %.8f", order.Quantity)
+		}
+		if cost > b.balance {
+			return b.reject(order, "insufficient balance: need %.2f, have %.2f", cost, b.balance)
+		}
+		b.balance -= cost
+
+		if p, ok := b.positions[order.Symbol]; ok {
+			total := p.Quantity + order.Quantity
+			p.EntryPrice = (p.EntryPrice*p.Quantity + order.Price*order.Quantity) / total
+			p.Quantity = total
+		} else {
+			b.positions[order.Symbol] = &engine.Position{
+				Symbol:       order.Symbol,
+				Side:         engine.OrderSideBuy,
+				EntryPrice:   order.Price,
+				Quantity:     order.Quantity,
+				CurrentPrice: order.Price,
+				OpenedAt:     order.Timestamp,
+			}
+		}
+		order.FilledQty = order.Quantity
+	case engine.OrderSideSell:
+		p, ok := b.positions[order.Symbol]
+		if !ok {
+			return b.reject(order, "no open position for %s", order.Symbol)
+		}
+
+		qty := order.Quantity
+		if qty <= 0 || qty > p.Quantity {
+			qty = p.Quantity
+		}
+		pnl := (order.Price - p.EntryPrice) * qty
+		b.balance += order.Price * qty
+		b.trades = append(b.trades, &engine.Trade{
+			ID:         order.ID,
+			Symbol:     order.Symbol,
+			Side:       engine.OrderSideBuy,
+			EntryPrice: p.EntryPrice,
+			ExitPrice:  order.Price,
+			Quantity:   qty,
+			PnL:        pnl,
+			NetPnL:     pnl,
+			OpenedAt:   p.OpenedAt,
+			ClosedAt:   order.Timestamp,
+			Reason:     order.Reason,
+		})
+
+		p.Quantity -= qty
+		if p.Quantity <= 0 {
+			delete(b.positions, order.Symbol)
+		}
+		order.FilledQty = qty
+	default:
+		return b.reject(order, "unknown order side: %s", order.Side)
+	}
+
+	order.Status = engine.OrderStatusFilled
+	order.FilledPrice = order.Price
+	return nil
+}
+
+// reject marks the order rejected with the formatted reason, records it and
+// returns the reason as an error
+func (b *cashBroker) reject(order *engine.Order, format string, args ...interface{}) error {
+	order.Status = engine.OrderStatusRejected
+	order.RejectReason = fmt.Sprintf(format, args...)
+	b.rejected = append(b.rejected, order)
+	return errors.New(order.RejectReason)
+}
+
+// RejectedOrders returns the orders rejected so far, oldest first
+func (b *cashBroker) RejectedOrders() []*engine.Order {
+	return b.rejected
+}
+
+// CancelOrder is a no-op; market orders fill immediately
+func (b *cashBroker) CancelOrder(orderID string) error { return nil }
+
+// UpdateMarketPrice marks the symbol's position to price
+func (b *cashBroker) UpdateMarketPrice(symbol string, price float64) {
+	if p, ok := b.positions[symbol]; ok {
+		p.CurrentPrice = price
+		p.UnrealizedPnL = (price - p.EntryPrice) * p.Quantity
+	}
+}
+
+// GetPosition returns the open position for symbol, or nil
+func (b *cashBroker) GetPosition(symbol string) *engine.Position {
+	return b.positions[symbol]
+}
+
+// nopStore discards state; harness runs are never resumed
+type nopStore struct{}
+
+func (nopStore) SaveState(broker engine.Broker) error { return nil }
+func (nopStore) LoadState(broker engine.Broker) error { return nil }
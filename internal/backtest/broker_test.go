@@ -0,0 +1,81 @@
+package backtest
+
+import (
+	"strings"
+	"testing"
+
+	"candlecore/internal/engine"
+)
+
+func TestCashBrokerRejectReasons(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *engine.Order
+		want  string
+	}{
+		{"insufficient balance", &engine.Order{Side: engine.OrderSideBuy, Symbol: "BTC/USD", Quantity: 2, Price: 600}, "insufficient balance"},
+		{"zero quantity", &engine.Order{Side: engine.OrderSideBuy, Symbol: "BTC/USD", Quantity: 0, Price: 100}, "invalid quantity"},
+		{"no position", &engine.Order{Side: engine.OrderSideSell, Symbol: "BTC/USD", Quantity: 1, Price: 100}, "no open position"},
+	}
+
+	b := newCashBroker(1000)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := b.PlaceOrder(tt.order)
+			if err == nil {
+				t.Fatal("PlaceOrder() expected error")
+			}
+			if tt.order.Status != engine.OrderStatusRejected {
+				t.Errorf("status = %s, want rejected", tt.order.Status)
+			}
+			if !strings.HasPrefix(tt.order.RejectReason, tt.want) {
+				t.Errorf("RejectReason = %q, want prefix %q", tt.order.RejectReason, tt.want)
+			}
+			if err.Error() != tt.order.RejectReason {
+				t.Errorf("error = %q, want the reject reason", err)
+			}
+		})
+	}
+
+	// A filled order carries no reason and is not listed
+	filled := &engine.Order{Side: engine.OrderSideBuy, Symbol: "BTC/USD", Quantity: 1, Price: 100}
+	if err := b.PlaceOrder(filled); err != nil {
+		t.Fatalf("PlaceOrder() error: %v", err)
+	}
+	if filled.RejectReason != "" {
+		t.Errorf("filled order RejectReason = %q, want empty", filled.RejectReason)
+	}
+
+	rejected := b.RejectedOrders()
+	if len(rejected) != len(tests) {
+		t.Fatalf("RejectedOrders() = %d, want %d", len(rejected), len(tests))
+	}
+	for i, tt := range tests {
+		if rejected[i] != tt.order {
+			t.Errorf("RejectedOrders()[%d] is not the %s order", i, tt.name)
+		}
+	}
+}
+
+func TestCashBrokerPositionsSortedBySymbol(t *testing.T) {
+	b := newCashBroker(1000)
+	for _, symbol := range []string{"SOL/USD", "BTC/USD", "ETH/USD"} {
+		order := &engine.Order{Side: engine.OrderSideBuy, Symbol: symbol, Quantity: 1, Price: 100}
+		if err := b.PlaceOrder(order); err != nil {
+			t.Fatalf("PlaceOrder(%s) error: %v", symbol, err)
+		}
+	}
+
+	want := []string{"BTC/USD", "ETH/USD", "SOL/USD"}
+	for run := 0; run < 5; run++ {
+		positions := b.GetAccount().Positions
+		if len(positions) != len(want) {
+			t.Fatalf("positions = %d, want %d", len(positions), len(want))
+		}
+		for i, p := range positions {
+			if p.Symbol != want[i] {
+				t.Fatalf("Positions[%d] = %s, want %s", i, p.Symbol, want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/metrics"
+)
+
+// StrategyStats summarizes one side of a comparison
+type StrategyStats struct {
+	Name           string  `json:"name"`
+	NetPnL         float64 `json:"net_pnl"`
+	WinRate        float64 `json:"win_rate"`         // Percent of trades with positive net P&L
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Over the trade equity curve
+	Sharpe         float64 `json:"sharpe"`
+	TradeCount     int     `json:"trade_count"`
+	Result         *Result `json:"-"`
+}
+
+// Comparison holds two strategies backtested over the same candles
+type Comparison struct {
+	A StrategyStats `json:"a"`
+	B StrategyStats `json:"b"`
+
+	// EquityCorrelation is the Pearson correlation of the two strategies'
+	// account equity sampled on every candle
+	EquityCorrelation float64 `json:"equity_correlation"`
+}
+
+// Compare backtests a and b over the same candles, each on a fresh broker
+// built from cfg, and summarizes them side by side. a and b must be
+// separate strategy instances since strategies keep state between candles.
+func Compare(ctx context.Context, candles []engine.Candle, a, b engine.Strategy, cfg Config) (*Comparison, error) {
+	statsA, equityA, err := compareRun(ctx, candles, a, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a.Name(), err)
+	}
+	statsB, equityB, err := compareRun(ctx, candles, b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+
+	return &Comparison{
+		A:                 statsA,
+		B:                 statsB,
+		EquityCorrelation: metrics.Correlation(equityA, equityB),
+	}, nil
+}
+
+// compareRun backtests one strategy and returns its stats and the equity
+// seen on every candle
+func compareRun(ctx context.Context, candles []engine.Candle, s engine.Strategy, cfg Config) (StrategyStats, []float64, error) {
+	recorder := &equityRecorder{Strategy: s, equity: make([]float64, 0, len(candles))}
+	result, err := Run(ctx, candles, recorder, cfg)
+	if err != nil {
+		return StrategyStats{}, nil, err
+	}
+
+	wins := 0
+	for _, t := range result.Trades {
+		if t.NetPnL > 0 {
+			wins++
+		}
+	}
+	winRate := 0.0
+	if result.TradeCount > 0 {
+		winRate = float64(wins) / float64(result.TradeCount) * 100
+	}
+
+	stats := StrategyStats{
+		Name:           s.Name(),
+		NetPnL:         result.NetPnL,
+		WinRate:        winRate,
+		MaxDrawdownPct: metrics.MaxDrawdown(result.InitialBalance, result.Trades).Percent,
+		Sharpe:         result.Sharpe,
+		TradeCount:     result.TradeCount,
+		Result:         result,
+	}
+	return stats, recorder.equity, nil
+}
+
+// equityRecorder records the account equity the engine reports on each
+// candle before passing it to the wrapped strategy
+type equityRecorder struct {
+	engine.Strategy
+	equity []float64
+}
+
+// OnCandle records equity and delegates
+func (r *equityRecorder) OnCandle(candle engine.Candle, account *engine.Account) engine.Signal {
+	r.equity = append(r.equity, account.Equity)
+	return r.Strategy.OnCandle(candle, account)
+}
+
+// WriteComparison prints a comparison as an aligned text table
+func WriteComparison(w io.Writer, c *Comparison) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	rows := []struct {
+		label string
+		a, b  string
+	}{
+		{"Net P&L", fmt.Sprintf("%.2f", c.A.NetPnL), fmt.Sprintf("%.2f", c.B.NetPnL)},
+		{"Win Rate", fmt.Sprintf("%.2f%%", c.A.WinRate), fmt.Sprintf("%.2f%%", c.B.WinRate)},
+		{"Max Drawdown", fmt.Sprintf("%.2f%%", c.A.MaxDrawdownPct), fmt.Sprintf("%.2f%%", c.B.MaxDrawdownPct)},
+		{"Sharpe", fmt.Sprintf("%.2f", c.A.Sharpe), fmt.Sprintf("%.2f", c.B.Sharpe)},
+		{"Trades", fmt.Sprintf("%d", c.A.TradeCount), fmt.Sprintf("%d", c.B.TradeCount)},
+	}
+
+	fmt.Fprintf(tw, "\t%s\t%s\t\n", c.A.Name, c.B.Name)
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", row.label, row.a, row.b)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nEquity correlation: %.4f\n", c.EquityCorrelation)
+	return err
+}
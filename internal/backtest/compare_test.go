@@ -0,0 +1,66 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"candlecore/internal/strategy"
+)
+
+func TestCompareIsDeterministic(t *testing.T) {
+	candles := sineCandles(300, 0.05)
+
+	render := func() string {
+		t.Helper()
+		c, err := Compare(context.Background(), candles,
+			strategy.NewMACDStrategy("", 3, 8, 3, 1000),
+			strategy.NewMACDStrategy("", 6, 20, 3, 1000),
+			Config{})
+		if err != nil {
+			t.Fatalf("Compare() error: %v", err)
+		}
+		if c.A.TradeCount == 0 || c.B.TradeCount == 0 {
+			t.Fatalf("trade counts = %d and %d, want both to trade", c.A.TradeCount, c.B.TradeCount)
+		}
+		if c.EquityCorrelation < -1 || c.EquityCorrelation > 1 {
+			t.Errorf("EquityCorrelation = %.4f, want within [-1, 1]", c.EquityCorrelation)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteComparison(&buf, c); err != nil {
+			t.Fatalf("WriteComparison() error: %v", err)
+		}
+		return buf.String()
+	}
+
+	first := render()
+	if second := render(); second != first {
+		t.Errorf("second comparison differs:\n%s\nwant:\n%s", second, first)
+	}
+	for _, want := range []string{"MACD (3/8/3)", "MACD (6/20/3)", "Net P&L", "Equity correlation:"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("output missing %q:\n%s", want, first)
+		}
+	}
+}
+
+func TestCompareIdenticalStrategies(t *testing.T) {
+	candles := sineCandles(300, 0.05)
+	c, err := Compare(context.Background(), candles,
+		strategy.NewMACDStrategy("", 3, 8, 3, 1000),
+		strategy.NewMACDStrategy("", 3, 8, 3, 1000),
+		Config{})
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+
+	if c.A.NetPnL != c.B.NetPnL || c.A.TradeCount != c.B.TradeCount || c.A.WinRate != c.B.WinRate {
+		t.Errorf("identical strategies differ: %+v vs %+v", c.A, c.B)
+	}
+	if math.Abs(c.EquityCorrelation-1) > 1e-9 {
+		t.Errorf("EquityCorrelation = %.6f, want 1", c.EquityCorrelation)
+	}
+}
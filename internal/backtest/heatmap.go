@@ -0,0 +1,138 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// heatmapCellWidth is the printed width of one heatmap cell
+const heatmapCellWidth = 10
+
+// Heatmap is a 2D slice of optimization scores, used to spot parameter
+// regions that perform well as a whole rather than a single peak
+type Heatmap struct {
+	XLabel string
+	YLabel string
+	X      []int
+	Y      []int
+
+	// Values is indexed [y][x]; NaN marks combinations that were skipped
+	Values [][]float64
+}
+
+// NewHeatmap arranges optimization results on the xKey and yKey axes of the
+// grid passed to Grid. When the grid has further axes each cell holds the
+// best score across them. Combinations without a result are NaN.
+func NewHeatmap(axes map[string][]int, xKey, yKey string, results []*Result, objective Objective) (*Heatmap, error) {
+	xs, ok := axes[xKey]
+	if !ok {
+		return nil, fmt.Errorf("unknown heatmap axis: %s", xKey)
+	}
+	ys, ok := axes[yKey]
+	if !ok {
+		return nil, fmt.Errorf("unknown heatmap axis: %s", yKey)
+	}
+	if xKey == yKey {
+		return nil, fmt.Errorf("heatmap axes must differ")
+	}
+
+	h := &Heatmap{XLabel: xKey, YLabel: yKey, X: xs, Y: ys, Values: make([][]float64, len(ys))}
+	for i := range h.Values {
+		h.Values[i] = make([]float64, len(xs))
+		for j := range h.Values[i] {
+			h.Values[i][j] = math.NaN()
+		}
+	}
+
+	for _, result := range results {
+		x, xOK := result.Params[xKey].(int)
+		y, yOK := result.Params[yKey].(int)
+		if !xOK || !yOK {
+			continue
+		}
+		col, row := indexOf(xs, x), indexOf(ys, y)
+		if col < 0 || row < 0 {
+			continue
+		}
+		score := result.score(objective)
+		if current := h.Values[row][col]; math.IsNaN(current) || score > current {
+			h.Values[row][col] = score
+		}
+	}
+
+	return h, nil
+}
+
+// WriteHeatmap prints the heatmap as a terminal table with each cell shaded
+// from red (lowest score) to green (highest). Skipped cells print as "-"
+// without shading; unbounded scores, such as the profit factor of a run
+// without losses, print as "inf" in the top shade.
+func WriteHeatmap(w io.Writer, h *Heatmap) error {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, row := range h.Values {
+		for _, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			low = math.Min(low, v)
+			high = math.Max(high, v)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s \\ %s\n", h.YLabel, h.XLabel)
+	fmt.Fprintf(&b, "%*s", heatmapCellWidth, "")
+	for _, x := range h.X {
+		fmt.Fprintf(&b, "%*d", heatmapCellWidth, x)
+	}
+	b.WriteString("\n")
+
+	for i, row := range h.Values {
+		label := ""
+		if i < len(h.Y) {
+			label = fmt.Sprintf("%d", h.Y[i])
+		}
+		fmt.Fprintf(&b, "%*s", heatmapCellWidth, label)
+		for _, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, -1) {
+				fmt.Fprintf(&b, "%*s", heatmapCellWidth, "-")
+				continue
+			}
+			if math.IsInf(v, 1) {
+				fmt.Fprintf(&b, "\033[48;2;0;255;0m\033[30m%*s\033[0m", heatmapCellWidth, "inf")
+				continue
+			}
+			r, g := heatColor(v, low, high)
+			fmt.Fprintf(&b, "\033[48;2;%d;%d;0m\033[30m%*.2f\033[0m", r, g, heatmapCellWidth, v)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// heatColor maps v within [low, high] onto a red-yellow-green scale and
+// returns the red and green channels. A flat range shades as yellow.
+func heatColor(v, low, high float64) (int, int) {
+	t := 0.5
+	if high > low {
+		t = (v - low) / (high - low)
+	}
+	if t < 0.5 {
+		return 255, int(math.Round(510 * t))
+	}
+	return int(math.Round(510 * (1 - t))), 255
+}
+
+// indexOf returns the position of v in values, or -1
+func indexOf(values []int, v int) int {
+	for i, candidate := range values {
+		if candidate == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestHeatmapFromOptimize(t *testing.T) {
+	axes := map[string][]int{"fast": {2, 5, 10}, "slow": {5, 10, 20}}
+	results, err := Optimize(context.Background(), dipThenTrend(), maFactory, Grid(axes), Config{})
+	if err != nil {
+		t.Fatalf("Optimize() error: %v", err)
+	}
+
+	h, err := NewHeatmap(axes, "fast", "slow", results, ObjectiveNetPnL)
+	if err != nil {
+		t.Fatalf("NewHeatmap() error: %v", err)
+	}
+
+	// Rows are slow periods and columns fast periods; fast >= slow is skipped
+	for row, slow := range h.Y {
+		for col, fast := range h.X {
+			skipped := math.IsNaN(h.Values[row][col])
+			if skipped != (fast >= slow) {
+				t.Errorf("fast %d slow %d: skipped = %v, want %v", fast, slow, skipped, fast >= slow)
+			}
+		}
+	}
+	if got := h.Values[0][0]; got != results[0].NetPnL {
+		t.Errorf("fast 2 slow 5 = %.2f, want best result %.2f", got, results[0].NetPnL)
+	}
+
+	if _, err := NewHeatmap(axes, "fast", "period", results, ObjectiveNetPnL); err == nil {
+		t.Error("NewHeatmap() with unknown axis: want error")
+	}
+}
+
+func TestWriteHeatmap(t *testing.T) {
+	h := &Heatmap{
+		XLabel: "fast",
+		YLabel: "slow",
+		X:      []int{5, 10},
+		Y:      []int{20, 30},
+		Values: [][]float64{{-50, 100}, {math.NaN(), 25}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHeatmap(&buf, h); err != nil {
+		t.Fatalf("WriteHeatmap() error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"slow \\ fast",
+		"\033[48;2;255;0;0m", // lowest score in red
+		"\033[48;2;0;255;0m", // highest score in green
+		"   -",               // skipped cell
+		"100.00",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%q", want, out)
+		}
+	}
+
+	// An unbounded profit factor prints as inf rather than as skipped
+	buf.Reset()
+	h.Values[1][0] = math.Inf(1)
+	if err := WriteHeatmap(&buf, h); err != nil {
+		t.Fatalf("WriteHeatmap() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "       inf") {
+		t.Errorf("output missing inf cell:\n%q", buf.String())
+	}
+
+	// A grid with no scores or a single value still renders
+	for _, values := range [][][]float64{{{math.NaN()}}, {{7}}} {
+		h := &Heatmap{X: []int{1}, Y: []int{1}, Values: values}
+		if err := WriteHeatmap(&buf, h); err != nil {
+			t.Errorf("WriteHeatmap(%v) error: %v", values, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+
+	"candlecore/internal/engine"
+)
+
+// RunLive paper trades strategy on candles as they arrive, e.g. from
+// fetcher.BinanceFetcher.StreamCandles, on the same engine and cash broker
+// as Run. Errors received on errCh are logged through cfg.Logger and
+// skipped. It runs until ctx is cancelled or candleCh is closed; either is
+// a normal end, so open positions are closed at the last candle seen and
+// the result covers everything traded so far.
+func RunLive(ctx context.Context, candleCh <-chan engine.Candle, errCh <-chan error, strategy engine.Strategy, cfg Config) (*Result, error) {
+	balance := cfg.initialBalance()
+	broker := newCashBroker(balance)
+	counter := &candleCounter{}
+	e := cfg.newEngine(broker, strategy, counter)
+
+	if err := e.RunLive(ctx, candleCh, errCh); err != nil && !errors.Is(err, ctx.Err()) {
+		return nil, err
+	}
+
+	return newResult(broker, balance, counter.candles), nil
+}
+
+// candleCounter counts the candles an engine processes. The engine asks the
+// strategy for a signal exactly once per candle.
+type candleCounter struct {
+	candles int
+}
+
+func (c *candleCounter) OnSignal(signal engine.Signal, candle engine.Candle) { c.candles++ }
+func (c *candleCounter) OnOrderFilled(order *engine.Order)                   {}
+func (c *candleCounter) OnTrade(trade *engine.Trade)                         {}
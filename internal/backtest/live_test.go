@@ -0,0 +1,73 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/strategy"
+)
+
+func TestRunLiveMatchesRun(t *testing.T) {
+	candles := sineCandles(200, 0)
+	want, err := Run(context.Background(), candles, strategy.NewMACDStrategy("", 3, 8, 3, 1000), Config{})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	candleCh := make(chan engine.Candle)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(candleCh)
+		for i, candle := range candles {
+			if i == 50 {
+				errCh <- errors.New("feed hiccup")
+			}
+			candleCh <- candle
+		}
+	}()
+
+	got, err := RunLive(context.Background(), candleCh, errCh, strategy.NewMACDStrategy("", 3, 8, 3, 1000), Config{})
+	if err != nil {
+		t.Fatalf("RunLive() error: %v", err)
+	}
+
+	if got.Candles != 200 {
+		t.Errorf("Candles = %d, want 200", got.Candles)
+	}
+	if got.TradeCount != want.TradeCount || math.Abs(got.FinalBalance-want.FinalBalance) > 1e-9 {
+		t.Errorf("RunLive() = %d trades, balance %.4f; Run() = %d trades, balance %.4f",
+			got.TradeCount, got.FinalBalance, want.TradeCount, want.FinalBalance)
+	}
+}
+
+func TestRunLiveEndsFlatOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	candleCh := make(chan engine.Candle)
+	go func() {
+		// Enter on the first candle, then stop while holding
+		for _, candle := range sineCandles(3, 0) {
+			candleCh <- candle
+		}
+		cancel()
+	}()
+
+	buyOnce := &exitStrategy{exits: map[int]bool{}}
+	result, err := RunLive(ctx, candleCh, nil, buyOnce, Config{})
+	if err != nil {
+		t.Fatalf("RunLive() error: %v", err)
+	}
+
+	if result.Candles != 3 {
+		t.Errorf("Candles = %d, want 3", result.Candles)
+	}
+	if result.TradeCount != 1 {
+		t.Fatalf("TradeCount = %d, want the open position closed on cancel", result.TradeCount)
+	}
+	if closedAt := result.Trades[0].ClosedAt; !closedAt.Equal(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("trade closed at %v, want the last candle", closedAt)
+	}
+}
@@ -0,0 +1,144 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"candlecore/internal/engine"
+)
+
+// defaultWorkers bounds concurrent runs when Config.Workers is zero
+const defaultWorkers = 4
+
+// Objective selects the metric results are ranked by, highest first
+type Objective string
+
+const (
+	ObjectiveNetPnL       Objective = "net_pnl"
+	ObjectiveSharpe       Objective = "sharpe"
+	ObjectiveProfitFactor Objective = "profit_factor"
+)
+
+// score returns the result's value for the objective
+func (r *Result) score(objective Objective) float64 {
+	switch objective {
+	case ObjectiveSharpe:
+		return r.Sharpe
+	case ObjectiveProfitFactor:
+		// Profit without a single losing trade has no finite profit factor
+		// and beats every run that lost at least once
+		if r.ProfitFactor == 0 && r.NetPnL > 0 {
+			return math.Inf(1)
+		}
+		return r.ProfitFactor
+	default:
+		return r.NetPnL
+	}
+}
+
+// Grid builds every combination of the given integer parameter values,
+// e.g. {"fast": {5, 10}, "slow": {20, 30}} yields four combinations.
+// Keys vary in sorted order with the last key changing fastest.
+func Grid(axes map[string][]int) []Params {
+	keys := make([]string, 0, len(axes))
+	for key := range axes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	grid := []Params{{}}
+	for _, key := range keys {
+		next := make([]Params, 0, len(grid)*len(axes[key]))
+		for _, base := range grid {
+			for _, value := range axes[key] {
+				params := make(Params, len(base)+1)
+				for k, v := range base {
+					params[k] = v
+				}
+				params[key] = value
+				next = append(next, params)
+			}
+		}
+		grid = next
+	}
+	return grid
+}
+
+// Optimize backtests every combination in paramGrid over the same candles
+// on a bounded pool of Config.Workers goroutines and returns the results
+// ranked by Config.Objective, best first. Combinations the factory rejects,
+// such as a fast period not below the slow one, are skipped.
+func Optimize(ctx context.Context, candles []engine.Candle, factory StrategyFactory, paramGrid []Params, cfg Config) ([]*Result, error) {
+	switch cfg.Objective {
+	case "", ObjectiveNetPnL, ObjectiveSharpe, ObjectiveProfitFactor:
+	default:
+		return nil, fmt.Errorf("unknown objective: %s", cfg.Objective)
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	results := make([]*Result, len(paramGrid))
+	errs := make([]error, len(paramGrid))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				strategy, err := factory(paramGrid[i])
+				if err != nil {
+					continue
+				}
+				result, err := Run(ctx, candles, strategy, cfg)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				result.Params = paramGrid[i]
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range paramGrid {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ranked := make([]*Result, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			ranked = append(ranked, result)
+		}
+	}
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("no valid parameter combination")
+	}
+
+	// Stable sort keeps grid order among equal scores
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score(cfg.Objective) > ranked[j].score(cfg.Objective)
+	})
+	return ranked, nil
+}
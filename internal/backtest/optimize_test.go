@@ -0,0 +1,165 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/strategies"
+	"candlecore/internal/strategy"
+)
+
+// maFactory builds bot MA crossover strategies from "fast" and "slow" params
+func maFactory(params Params) (engine.Strategy, error) {
+	fast, _ := params["fast"].(int)
+	slow, _ := params["slow"].(int)
+	if fast <= 0 || fast >= slow {
+		return nil, fmt.Errorf("fast must be positive and below slow")
+	}
+	return strategy.FromBotStrategy(strategies.NewSimpleMAStrategy("BTC/USD", fast, slow)), nil
+}
+
+// dipThenTrend declines for 30 candles and then rises steadily, so the
+// fastest crossover enters earliest and at the lowest price
+func dipThenTrend() []engine.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var candles []engine.Candle
+	price := 130.0
+	for i := 0; i < 150; i++ {
+		if i < 30 {
+			price--
+		} else {
+			price += 1.5
+		}
+		candles = append(candles, engine.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price + 0.5,
+			Low:       price - 0.5,
+			Close:     price,
+			Volume:    1000,
+		})
+	}
+	return candles
+}
+
+func TestGrid(t *testing.T) {
+	grid := Grid(map[string][]int{"slow": {20, 30}, "fast": {5, 10}})
+	want := []Params{
+		{"fast": 5, "slow": 20},
+		{"fast": 5, "slow": 30},
+		{"fast": 10, "slow": 20},
+		{"fast": 10, "slow": 30},
+	}
+	if len(grid) != len(want) {
+		t.Fatalf("len(Grid) = %d, want %d", len(grid), len(want))
+	}
+	for i := range want {
+		if grid[i]["fast"] != want[i]["fast"] || grid[i]["slow"] != want[i]["slow"] {
+			t.Errorf("Grid[%d] = %v, want %v", i, grid[i], want[i])
+		}
+	}
+}
+
+func TestOptimizeFindsBestParams(t *testing.T) {
+	grid := Grid(map[string][]int{"fast": {2, 5, 10, 20}, "slow": {5, 10, 20, 40}})
+
+	results, err := Optimize(context.Background(), dipThenTrend(), maFactory, grid, Config{Workers: 3})
+	if err != nil {
+		t.Fatalf("Optimize() error: %v", err)
+	}
+
+	// Only combinations with fast below slow run
+	if len(results) != 10 {
+		t.Fatalf("results = %d, want 10 valid combinations", len(results))
+	}
+	best := results[0].Params
+	if best["fast"] != 2 || best["slow"] != 5 {
+		t.Errorf("best params = %v, want fast 2 slow 5", best)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].NetPnL > results[i-1].NetPnL {
+			t.Errorf("results not ranked: %d has %.2f after %.2f", i, results[i].NetPnL, results[i-1].NetPnL)
+		}
+	}
+}
+
+func TestOptimizeObjectives(t *testing.T) {
+	candles := sineCandles(300, 0)
+	grid := []Params{{"fast": 3, "slow": 8}, {"fast": 5, "slow": 13}, {"fast": 8, "slow": 21}}
+
+	for _, objective := range []Objective{ObjectiveSharpe, ObjectiveProfitFactor} {
+		results, err := Optimize(context.Background(), candles, macdFactory, grid, Config{Objective: objective})
+		if err != nil {
+			t.Fatalf("Optimize(%s) error: %v", objective, err)
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].score(objective) > results[i-1].score(objective) {
+				t.Errorf("%s: results not ranked at %d", objective, i)
+			}
+		}
+	}
+
+	if _, err := Optimize(context.Background(), candles, macdFactory, grid, Config{Objective: "calmar"}); err == nil {
+		t.Error("expected error for unknown objective")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Optimize(ctx, candles, macdFactory, grid, Config{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled Optimize() error = %v, want context.Canceled", err)
+	}
+}
+
+// exitStrategy buys on the first candle and sells at the given indexes,
+// re-entering on the candle after each exit
+type exitStrategy struct {
+	exits map[int]bool
+	index int
+}
+
+func (s *exitStrategy) Name() string { return "exit" }
+
+func (s *exitStrategy) OnCandle(candle engine.Candle, account *engine.Account) engine.Signal {
+	defer func() { s.index++ }()
+	switch {
+	case s.exits[s.index]:
+		return engine.Signal{Action: engine.SignalActionSell, Symbol: "BTC/USD", Quantity: 1}
+	case s.index == 0 || s.exits[s.index-1]:
+		return engine.Signal{Action: engine.SignalActionBuy, Symbol: "BTC/USD", Quantity: 1}
+	}
+	return engine.Signal{Action: engine.SignalActionHold}
+}
+
+func (s *exitStrategy) OnTrade(trade *engine.Trade) {}
+
+func TestOptimizeProfitFactorRanksLossFreeRunsFirst(t *testing.T) {
+	// dipThenTrend falls for 30 candles, so an exit at 10 loses and one at
+	// 100 wins
+	factory := func(params Params) (engine.Strategy, error) {
+		exits := map[int]bool{100: true}
+		if params["mixed"] == 1 {
+			exits[10] = true
+		}
+		return &exitStrategy{exits: exits}, nil
+	}
+	grid := []Params{{"mixed": 1}, {"mixed": 0}}
+
+	results, err := Optimize(context.Background(), dipThenTrend(), factory, grid, Config{Objective: ObjectiveProfitFactor})
+	if err != nil {
+		t.Fatalf("Optimize() error: %v", err)
+	}
+
+	if results[0].Params["mixed"] != 0 {
+		t.Fatalf("best params = %v, want the run without losses", results[0].Params)
+	}
+	if results[0].ProfitFactor != 0 || results[0].NetPnL <= 0 {
+		t.Errorf("best run profit factor = %.2f, net = %.2f, want 0 and a profit", results[0].ProfitFactor, results[0].NetPnL)
+	}
+	if results[1].ProfitFactor <= 0 {
+		t.Errorf("mixed run profit factor = %.2f, want a finite positive value", results[1].ProfitFactor)
+	}
+}
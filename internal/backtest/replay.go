@@ -0,0 +1,385 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"candlecore/internal/bot"
+	"candlecore/internal/config"
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+	"candlecore/internal/metrics"
+	"candlecore/internal/strategies"
+)
+
+// Default bot strategy parameters used by NewStrategy
+const (
+	// DefaultWarmup covers strategies that do not report a warm-up period
+	DefaultWarmup = 31
+
+	defaultFastPeriod    = 10
+	defaultSlowPeriod    = 30
+	defaultRSIPeriod     = 14
+	defaultOversold      = 30.0
+	defaultOverbought    = 70.0
+	defaultVWAPLookback  = 20
+	defaultVWAPStdDev    = 2.0
+	defaultDonchianEntry = 20
+	defaultDonchianExit  = 10
+)
+
+// NewStrategy builds a bot strategy by name for symbol with its default
+// parameters
+func NewStrategy(name, symbol string) (bot.Strategy, error) {
+	switch name {
+	case "ma_crossover":
+		return strategies.NewSimpleMAStrategy(symbol, defaultFastPeriod, defaultSlowPeriod), nil
+	case "rsi":
+		return strategies.NewRSIStrategy(symbol, defaultRSIPeriod, defaultOversold, defaultOverbought), nil
+	case "rsi_divergence":
+		return strategies.NewRSIDivergenceStrategy(symbol, defaultRSIPeriod), nil
+	case "vwap_reversion":
+		return strategies.NewVWAPReversionStrategy(symbol, defaultVWAPLookback, defaultVWAPStdDev), nil
+	case "donchian_breakout":
+		return strategies.NewDonchianBreakoutStrategy(symbol, defaultDonchianEntry, defaultDonchianExit), nil
+	default:
+		return nil, fmt.Errorf("unknown strategy: %s", name)
+	}
+}
+
+// WarmupPeriod returns how many candles strategy needs before its first
+// decision, falling back to DefaultWarmup when it does not say
+func WarmupPeriod(strategy bot.Strategy) int {
+	if warmup := bot.WarmupPeriod(strategy); warmup > 0 {
+		return warmup
+	}
+	return DefaultWarmup
+}
+
+// ReplayRequest describes a backtest that replays stored candles through a
+// bot strategy. Strategy parameters are optional and default to the
+// strategy's own defaults: fast_period/slow_period for ma_crossover and
+// period/oversold/overbought for rsi. taker_fee and slippage_bps start from
+// DefaultReplayRequest; pass 0 for both to trade without costs.
+type ReplayRequest struct {
+	Symbol         string  `json:"symbol"`
+	Timeframe      string  `json:"timeframe"`
+	Strategy       string  `json:"strategy"`
+	DataSource     string  `json:"data_source"`
+	InitialBalance float64 `json:"initial_balance"`
+	FastPeriod     int     `json:"fast_period"`
+	SlowPeriod     int     `json:"slow_period"`
+	Period         int     `json:"period"`
+	Oversold       float64 `json:"oversold"`
+	Overbought     float64 `json:"overbought"`
+	TakerFee       float64 `json:"taker_fee"`    // e.g. 0.001 for 0.1%
+	SlippageBps    float64 `json:"slippage_bps"` // e.g. 5 for 0.05%
+}
+
+// DefaultReplayRequest returns a request carrying the configuration's
+// trading costs, so fields a caller leaves alone match the CLI defaults
+func DefaultReplayRequest() ReplayRequest {
+	defaults := config.Default()
+	return ReplayRequest{
+		TakerFee:    defaults.TakerFee,
+		SlippageBps: defaults.SlippageBps,
+	}
+}
+
+// ReplayResult summarizes a completed replay backtest
+type ReplayResult struct {
+	Symbol         string  `json:"symbol"`
+	Timeframe      string  `json:"timeframe"`
+	Strategy       string  `json:"strategy"`
+	Candles        int     `json:"candles"`
+	InitialBalance float64 `json:"initial_balance"`
+	FinalBalance   float64 `json:"final_balance"`
+	TotalPnL       float64 `json:"total_pnl"`
+	TradeCount     int     `json:"trade_count"`
+	WinningTrades  int     `json:"winning_trades"`
+	WinRate        float64 `json:"win_rate"` // Percent of closed trades with positive P&L
+
+	// Trades lists the closed trades for reports and exports; the API
+	// response only carries the summary
+	Trades []*engine.Trade `json:"-"`
+
+	// Equity is the balance after each trade, served by the equity endpoint
+	Equity []EquityPoint `json:"-"`
+}
+
+// EquityPoint is the account balance after a closed trade and how far it
+// sits below the highest balance reached so far
+type EquityPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Equity      float64   `json:"equity"`
+	Drawdown    float64   `json:"drawdown"`     // Decline from the running peak, in quote currency
+	DrawdownPct float64   `json:"drawdown_pct"` // Decline relative to the running peak
+}
+
+// RunReplay replays stored candles through a bot and summarizes the result.
+// The strategy decides from the candle that completes its warm-up period,
+// as the API's bot controller does. A position still open after the last
+// candle is closed at its close, so the final balance, P&L and equity curve
+// are fully realized and agree. req.InitialBalance must already be set.
+func RunReplay(ctx context.Context, provider exchange.DataProvider, req ReplayRequest) (*ReplayResult, error) {
+	timeframe := exchange.Timeframe(req.Timeframe)
+
+	candles, err := provider.GetCandles(req.Symbol, timeframe, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+
+	strategy, err := req.BuildStrategy()
+	if err != nil {
+		return nil, err
+	}
+	warmup := WarmupPeriod(strategy)
+
+	replay := NewReplayProvider(req.Symbol, timeframe, candles)
+	b := bot.NewBot(strategy, replay, bot.Config{
+		Symbol:         req.Symbol,
+		Timeframe:      timeframe,
+		InitialBalance: req.InitialBalance,
+		PositionSize:   10,
+		TakerFee:       req.TakerFee,
+		SlippageBps:    req.SlippageBps,
+	})
+
+	for i, candle := range candles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Only expose candles up to the current one so the strategy
+		// never sees future data
+		replay.Advance(i)
+
+		if i+1 < warmup {
+			continue
+		}
+
+		if _, err := b.ProcessCandle(candle); err != nil {
+			return nil, fmt.Errorf("failed to process candle %d: %w", i, err)
+		}
+	}
+	if len(candles) >= warmup {
+		b.ClosePosition(candles[len(candles)-1].Close)
+	}
+
+	trades := b.GetTrades()
+	wins := 0
+	for _, trade := range trades {
+		if trade.RealizedPnL > 0 {
+			wins++
+		}
+	}
+
+	winRate := 0.0
+	if len(trades) > 0 {
+		winRate = float64(wins) / float64(len(trades)) * 100
+	}
+
+	var start time.Time
+	if len(candles) > 0 {
+		start = candles[0].Timestamp
+	}
+	closed := engineTrades(trades)
+
+	return &ReplayResult{
+		Symbol:         req.Symbol,
+		Timeframe:      req.Timeframe,
+		Strategy:       req.Strategy,
+		Candles:        len(candles),
+		InitialBalance: req.InitialBalance,
+		FinalBalance:   b.GetBalance(),
+		TotalPnL:       b.GetTotalPnL(),
+		TradeCount:     len(trades),
+		WinningTrades:  wins,
+		WinRate:        winRate,
+		Trades:         closed,
+		Equity:         buildEquityCurve(req.InitialBalance, start, closed),
+	}, nil
+}
+
+// engineTrades converts closed bot positions to engine trades. A bot
+// position's realized P&L is already net of its fees.
+func engineTrades(positions []bot.Position) []*engine.Trade {
+	trades := make([]*engine.Trade, len(positions))
+	for i, p := range positions {
+		side := engine.OrderSideBuy
+		if p.Side == "short" {
+			side = engine.OrderSideSell
+		}
+
+		trade := &engine.Trade{
+			ID:         p.ID,
+			Symbol:     p.Symbol,
+			Side:       side,
+			EntryPrice: p.EntryPrice,
+			ExitPrice:  p.CurrentPrice,
+			Quantity:   p.Quantity,
+			PnL:        p.RealizedPnL + p.Fees,
+			Fee:        p.Fees,
+			NetPnL:     p.RealizedPnL,
+			OpenedAt:   p.OpenedAt,
+		}
+		if p.ClosedAt != nil {
+			trade.ClosedAt = *p.ClosedAt
+		}
+		trades[i] = trade
+	}
+	return trades
+}
+
+// buildEquityCurve replays trades against the initial balance. The first
+// point is the initial balance at start; each trade adds a point at its
+// close time.
+func buildEquityCurve(initialBalance float64, start time.Time, trades []*engine.Trade) []EquityPoint {
+	curve := metrics.EquityCurve(initialBalance, trades)
+	points := make([]EquityPoint, len(curve))
+
+	peak := initialBalance
+	for i, equity := range curve {
+		timestamp := start
+		if i > 0 {
+			timestamp = trades[i-1].ClosedAt
+		}
+		if equity > peak {
+			peak = equity
+		}
+
+		point := EquityPoint{Timestamp: timestamp, Equity: equity, Drawdown: peak - equity}
+		if peak > 0 {
+			point.DrawdownPct = point.Drawdown / peak * 100
+		}
+		points[i] = point
+	}
+	return points
+}
+
+// BuildStrategy creates the requested strategy and applies any parameters
+func (req ReplayRequest) BuildStrategy() (bot.Strategy, error) {
+	strategy, err := NewStrategy(req.Strategy, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FastPeriod < 0 || req.SlowPeriod < 0 || req.Period < 0 {
+		return nil, fmt.Errorf("periods must be positive")
+	}
+
+	params := make(map[string]interface{})
+	switch req.Strategy {
+	case "ma_crossover":
+		fast, slow := defaultFastPeriod, defaultSlowPeriod
+		if req.FastPeriod > 0 {
+			fast = req.FastPeriod
+			params["fast_period"] = fast
+		}
+		if req.SlowPeriod > 0 {
+			slow = req.SlowPeriod
+			params["slow_period"] = slow
+		}
+		if fast >= slow {
+			return nil, fmt.Errorf("fast_period must be less than slow_period")
+		}
+	case "rsi_divergence":
+		if req.Period > 0 {
+			params["period"] = req.Period
+		}
+	case "rsi":
+		oversold, overbought := defaultOversold, defaultOverbought
+		if req.Period > 0 {
+			params["period"] = req.Period
+		}
+		if req.Oversold > 0 {
+			oversold = req.Oversold
+			params["oversold"] = oversold
+		}
+		if req.Overbought > 0 {
+			overbought = req.Overbought
+			params["overbought"] = overbought
+		}
+		if oversold >= overbought || overbought >= 100 {
+			return nil, fmt.Errorf("oversold must be less than overbought, and both below 100")
+		}
+	}
+
+	if err := strategy.Configure(params); err != nil {
+		return nil, err
+	}
+
+	return strategy, nil
+}
+
+// ReplayProvider serves a candle series up to a moving cursor, so a bot
+// reading history through it never sees candles after the current one
+type ReplayProvider struct {
+	symbol    string
+	timeframe exchange.Timeframe
+	candles   []exchange.Candle
+	cursor    int
+}
+
+// NewReplayProvider creates a replay provider positioned before the first
+// candle
+func NewReplayProvider(symbol string, timeframe exchange.Timeframe, candles []exchange.Candle) *ReplayProvider {
+	return &ReplayProvider{
+		symbol:    symbol,
+		timeframe: timeframe,
+		candles:   candles,
+		cursor:    -1,
+	}
+}
+
+// Advance makes candles up to and including index visible
+func (p *ReplayProvider) Advance(index int) {
+	p.cursor = index
+}
+
+// Push appends a candle and makes it visible, for series that grow while
+// they are replayed
+func (p *ReplayProvider) Push(candle exchange.Candle) {
+	p.candles = append(p.candles, candle)
+	p.cursor = len(p.candles) - 1
+}
+
+// GetCandles returns the most recent visible candles
+func (p *ReplayProvider) GetCandles(symbol string, timeframe exchange.Timeframe, limit int) ([]exchange.Candle, error) {
+	if symbol != p.symbol || timeframe != p.timeframe {
+		return nil, fmt.Errorf("no replay data for %s %s", symbol, timeframe)
+	}
+
+	visible := p.candles[:p.cursor+1]
+	if limit <= 0 || limit >= len(visible) {
+		return visible, nil
+	}
+	return visible[len(visible)-limit:], nil
+}
+
+// StreamCandles streams the visible candles
+func (p *ReplayProvider) StreamCandles(symbol string, timeframe exchange.Timeframe) (<-chan exchange.Candle, error) {
+	candles, err := p.GetCandles(symbol, timeframe, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan exchange.Candle, len(candles))
+	for _, candle := range candles {
+		ch <- candle
+	}
+	close(ch)
+
+	return ch, nil
+}
+
+// GetSupportedTimeframes returns the replayed timeframe
+func (p *ReplayProvider) GetSupportedTimeframes() []exchange.Timeframe {
+	return []exchange.Timeframe{p.timeframe}
+}
+
+// GetSupportedSymbols returns the replayed symbol
+func (p *ReplayProvider) GetSupportedSymbols() []string {
+	return []string{p.symbol}
+}
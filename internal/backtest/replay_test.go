@@ -0,0 +1,239 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/config"
+	"candlecore/internal/exchange"
+)
+
+// writeSineCSV writes an hourly oscillating price series so crossovers occur
+func writeSineCSV(t *testing.T, dir, symbol string, n int) {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("timestamp,open,high,low,close,volume\n")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		price := 100 + 10*math.Sin(float64(i)/8)
+		fmt.Fprintf(&b, "%s,%.4f,%.4f,%.4f,%.4f,1000\n",
+			start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339),
+			price, price+1, price-1, price)
+	}
+
+	path := filepath.Join(dir, symbol+"_1h.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+}
+
+func TestRunReplay(t *testing.T) {
+	dir := t.TempDir()
+	writeSineCSV(t, dir, "bitcoin", 300)
+
+	req := ReplayRequest{
+		Symbol:         "bitcoin",
+		Timeframe:      "1h",
+		Strategy:       "ma_crossover",
+		InitialBalance: 10000,
+		FastPeriod:     5,
+		SlowPeriod:     20,
+	}
+
+	result, err := RunReplay(context.Background(), exchange.NewLocalFileProvider(dir), req)
+	if err != nil {
+		t.Fatalf("RunReplay() error: %v", err)
+	}
+
+	if result.Candles != 300 {
+		t.Errorf("Candles = %d, want 300", result.Candles)
+	}
+	if result.TradeCount == 0 {
+		t.Fatal("expected trades on an oscillating series")
+	}
+	if result.WinningTrades > result.TradeCount {
+		t.Errorf("WinningTrades = %d exceeds TradeCount %d", result.WinningTrades, result.TradeCount)
+	}
+	wantRate := float64(result.WinningTrades) / float64(result.TradeCount) * 100
+	if math.Abs(result.WinRate-wantRate) > 1e-9 {
+		t.Errorf("WinRate = %.2f, want %.2f", result.WinRate, wantRate)
+	}
+	if len(result.Trades) != result.TradeCount {
+		t.Fatalf("len(Trades) = %d, want %d", len(result.Trades), result.TradeCount)
+	}
+	for i, trade := range result.Trades {
+		if trade.ClosedAt.Before(trade.OpenedAt) || trade.ClosedAt.Year() != 2024 {
+			t.Errorf("trade %d closed at %v, want a candle time after %v", i, trade.ClosedAt, trade.OpenedAt)
+		}
+	}
+}
+
+func TestReplayRequestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ReplayRequest
+		wantErr bool
+	}{
+		{"defaults", ReplayRequest{Strategy: "ma_crossover"}, false},
+		{"custom periods", ReplayRequest{Strategy: "ma_crossover", FastPeriod: 5, SlowPeriod: 50}, false},
+		{"fast above default slow", ReplayRequest{Strategy: "ma_crossover", FastPeriod: 40}, true},
+		{"negative period", ReplayRequest{Strategy: "rsi", Period: -1}, true},
+		{"inverted rsi levels", ReplayRequest{Strategy: "rsi", Oversold: 80}, true},
+		{"unknown strategy", ReplayRequest{Strategy: "macd"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.req.BuildStrategy()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BuildStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplayWarmupFollowsStrategy(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ReplayRequest
+		want int
+	}{
+		{"ma_crossover slow period", ReplayRequest{Strategy: "ma_crossover", SlowPeriod: 50}, 51},
+		{"rsi default", ReplayRequest{Strategy: "rsi"}, 15},
+		{"rsi_divergence period", ReplayRequest{Strategy: "rsi_divergence", Period: 40}, 46},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := tt.req.BuildStrategy()
+			if err != nil {
+				t.Fatalf("BuildStrategy() error: %v", err)
+			}
+			if got := WarmupPeriod(strategy); got != tt.want {
+				t.Errorf("WarmupPeriod() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplayRequestCostDefaults(t *testing.T) {
+	defaults := config.Default()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantFee  float64
+		wantSlip float64
+	}{
+		{"omitted", `{"symbol":"bitcoin"}`, defaults.TakerFee, defaults.SlippageBps},
+		{"explicit zero", `{"taker_fee":0,"slippage_bps":0}`, 0, 0},
+		{"custom", `{"taker_fee":0.002,"slippage_bps":10}`, 0.002, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := DefaultReplayRequest()
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if req.TakerFee != tt.wantFee || req.SlippageBps != tt.wantSlip {
+				t.Errorf("TakerFee, SlippageBps = %v, %v, want %v, %v", req.TakerFee, req.SlippageBps, tt.wantFee, tt.wantSlip)
+			}
+		})
+	}
+}
+
+func TestReplayEquityCurve(t *testing.T) {
+	dir := t.TempDir()
+	writeSineCSV(t, dir, "bitcoin", 300)
+
+	result, err := RunReplay(context.Background(), exchange.NewLocalFileProvider(dir), ReplayRequest{
+		Symbol:         "bitcoin",
+		Timeframe:      "1h",
+		Strategy:       "ma_crossover",
+		InitialBalance: 10000,
+		FastPeriod:     5,
+		SlowPeriod:     20,
+	})
+	if err != nil {
+		t.Fatalf("RunReplay() error: %v", err)
+	}
+
+	points := result.Equity
+	if len(points) != result.TradeCount+1 {
+		t.Fatalf("len(Equity) = %d, want %d", len(points), result.TradeCount+1)
+	}
+	if points[0].Equity != 10000 || points[0].Drawdown != 0 {
+		t.Errorf("first point = %+v, want the initial balance without drawdown", points[0])
+	}
+	if last := points[len(points)-1]; math.Abs(last.Equity-result.FinalBalance) > 1e-9 {
+		t.Errorf("last equity = %.4f, want final balance %.4f", last.Equity, result.FinalBalance)
+	}
+
+	peak := 0.0
+	for i, p := range points {
+		peak = math.Max(peak, p.Equity)
+		if math.Abs(p.Drawdown-(peak-p.Equity)) > 1e-9 {
+			t.Errorf("point %d drawdown = %.4f, want %.4f", i, p.Drawdown, peak-p.Equity)
+		}
+		if i > 0 && p.Timestamp.Before(points[i-1].Timestamp) {
+			t.Errorf("point %d at %v is before the previous point", i, p.Timestamp)
+		}
+	}
+}
+
+func TestReplayClosesOpenPositionAtEnd(t *testing.T) {
+	// A dip then a steady rise leaves the MA crossover long at the end
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("timestamp,open,high,low,close,volume\n")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 80; i++ {
+		price := 100 - float64(i)
+		if i >= 40 {
+			price = 60 + float64(i-40)*2
+		}
+		fmt.Fprintf(&b, "%s,%.4f,%.4f,%.4f,%.4f,1000\n",
+			start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339),
+			price, price+1, price-1, price)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bitcoin_1h.csv"), []byte(b.String()), 0644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	result, err := RunReplay(context.Background(), exchange.NewLocalFileProvider(dir), ReplayRequest{
+		Symbol:         "bitcoin",
+		Timeframe:      "1h",
+		Strategy:       "ma_crossover",
+		InitialBalance: 10000,
+		FastPeriod:     5,
+		SlowPeriod:     20,
+		TakerFee:       0.001,
+	})
+	if err != nil {
+		t.Fatalf("RunReplay() error: %v", err)
+	}
+
+	if result.TradeCount == 0 {
+		t.Fatal("expected the open position to be closed as a trade")
+	}
+	lastTrade := result.Trades[len(result.Trades)-1]
+	if want := start.Add(79 * time.Hour); !lastTrade.ClosedAt.Equal(want) {
+		t.Errorf("last trade closed at %v, want the last candle %v", lastTrade.ClosedAt, want)
+	}
+	last := result.Equity[len(result.Equity)-1]
+	if math.Abs(last.Equity-result.FinalBalance) > 1e-9 {
+		t.Errorf("last equity = %.4f, want final balance %.4f", last.Equity, result.FinalBalance)
+	}
+	if math.Abs(result.FinalBalance-(result.InitialBalance+result.TotalPnL)) > 1e-9 {
+		t.Errorf("FinalBalance = %.4f, want initial plus TotalPnL %.4f", result.FinalBalance, result.InitialBalance+result.TotalPnL)
+	}
+}
@@ -2,7 +2,11 @@ package bot
 
 import (
 	"candlecore/internal/exchange"
+	"candlecore/internal/sizing"
+	"math"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Signal represents a trading signal
@@ -24,6 +28,7 @@ type Decision struct {
 	Confidence float64           `json:"confidence"` // 0-100
 	Reasoning  string            `json:"reasoning"`
 	Indicators map[string]float64 `json:"indicators"` // indicator values at decision time
+	StopLoss   float64           `json:"stop_loss,omitempty"` // protective stop for buy signals, 0 if none
 }
 
 // Position represents an open position
@@ -36,6 +41,9 @@ type Position struct {
 	CurrentPrice float64 `json:"current_price"`
 	UnrealizedPnL float64 `json:"unrealized_pnl"`
 	RealizedPnL   float64 `json:"realized_pnl"`
+	HighWaterMark float64 `json:"high_water_mark,omitempty"` // Best price seen while open, for trailing stops
+	StopLoss      float64 `json:"stop_loss,omitempty"`       // Price that closes a long position, 0 if none
+	Fees          float64 `json:"fees,omitempty"`            // Commissions paid so far; realized and unrealized PnL are net of them
 	OpenedAt   time.Time `json:"opened_at"`
 	ClosedAt   *time.Time `json:"closed_at,omitempty"`
 }
@@ -52,6 +60,22 @@ type Strategy interface {
 	Configure(params map[string]interface{}) error
 }
 
+// WarmupStrategy is implemented by strategies that know how much history
+// they need before their decisions mean anything
+type WarmupStrategy interface {
+	// WarmupPeriod returns the number of candles needed for a first decision
+	WarmupPeriod() int
+}
+
+// WarmupPeriod returns the strategy's warm-up period, or 0 when it does
+// not report one
+func WarmupPeriod(s Strategy) int {
+	if w, ok := s.(WarmupStrategy); ok {
+		return w.WarmupPeriod()
+	}
+	return 0
+}
+
 // Bot represents the trading bot
 type Bot struct {
 	strategy      Strategy
@@ -62,14 +86,48 @@ type Bot struct {
 	balance       float64
 	initialBalance float64
 	trades        []Position
+	trailingStopPct float64
+	riskPct       float64
+	positionSize  float64 // percent of balance for a full-confidence entry
+	takerFee      float64
+	slippageBps   float64
+	lastCandleAt  time.Time // timestamp of the candle being processed
 }
 
+const (
+	// defaultPositionSizePct is the entry size when Config.PositionSize is unset
+	defaultPositionSizePct = 10.0
+
+	// minConfidence floors decision confidence when sizing entries
+	minConfidence = 20.0
+)
+
 // Config contains bot configuration
 type Config struct {
 	Symbol         string
 	Timeframe      exchange.Timeframe
 	InitialBalance float64
-	PositionSize   float64 // Percentage of balance per trade (0-100)
+	// PositionSize is the percentage of balance (0-100) a full-confidence
+	// entry invests; lower confidence scales it down. Zero uses 10%.
+	PositionSize float64
+
+	// TrailingStopPct closes a long position once price retraces this
+	// percent from its highest close since entry (e.g. 5 for 5%). Zero disables.
+	TrailingStopPct float64
+
+	// RiskPct sizes entries that carry a stop loss so that hitting the stop
+	// loses this percent of the balance (e.g. 1 for 1%). Zero, or a decision
+	// without a stop, falls back to fixed sizing.
+	RiskPct float64
+
+	// TakerFee is the commission rate charged on the value of every entry
+	// and exit (e.g. 0.001 for 0.1%). The bot only trades at market, so
+	// maker rates never apply.
+	TakerFee float64
+
+	// SlippageBps moves every fill against the bot by this many basis
+	// points of the candle close (e.g. 5 for 0.05%)
+	SlippageBps float64
 }
 
 // NewBot creates a new trading bot
@@ -82,11 +140,18 @@ func NewBot(strategy Strategy, provider exchange.DataProvider, config Config) *B
 		balance:        config.InitialBalance,
 		initialBalance: config.InitialBalance,
 		trades:         make([]Position, 0),
+		trailingStopPct: config.TrailingStopPct,
+		riskPct:        config.RiskPct,
+		positionSize:   config.PositionSize,
+		takerFee:       config.TakerFee,
+		slippageBps:    config.SlippageBps,
 	}
 }
 
 // ProcessCandle processes a new candle and executes strategy
 func (b *Bot) ProcessCandle(candle exchange.Candle) (*Decision, error) {
+	b.lastCandleAt = candle.Timestamp
+
 	// Get recent candles for analysis
 	candles, err := b.provider.GetCandles(b.symbol, b.timeframe, 200)
 	if err != nil {
@@ -105,8 +170,18 @@ func (b *Bot) ProcessCandle(candle exchange.Candle) (*Decision, error) {
 	return decision, nil
 }
 
-// executeDecision executes a trading decision
+// executeDecision executes a trading decision. An open position is marked
+// and its stops checked on every candle before the signal is acted on, so
+// repeated BUY signals cannot hold off a stop; a position stopped out on
+// this candle is not re-entered on it.
 func (b *Bot) executeDecision(decision *Decision, candle exchange.Candle) {
+	if b.position != nil {
+		b.updatePosition(candle.Close)
+		if b.position == nil {
+			return
+		}
+	}
+
 	switch decision.Signal {
 	case SignalBuy:
 		if b.position == nil || b.position.Side == "short" {
@@ -117,10 +192,7 @@ func (b *Bot) executeDecision(decision *Decision, candle exchange.Candle) {
 			b.closePosition(candle.Close)
 		}
 	case SignalHold:
-		// Update unrealized PnL if position exists
-		if b.position != nil {
-			b.updatePosition(candle.Close)
-		}
+		// The position was already marked above
 	}
 }
 
@@ -131,8 +203,20 @@ func (b *Bot) enterPosition(side string, price float64, decision *Decision) {
 		b.closePosition(price)
 	}
 
-	// Calculate position size (use 10% of balance for simplicity)
-	quantity := (b.balance * 0.1) / price
+	// Longs buy to open and shorts sell to open
+	price = b.fillPrice(price, side == "long")
+
+	// Size by risk when the decision carries a stop, otherwise by confidence
+	quantity := 0.0
+	if b.riskPct > 0 && decision.StopLoss > 0 {
+		quantity = sizing.RiskSizedQuantity(b.balance, price, decision.StopLoss, b.riskPct)
+	}
+	if quantity == 0 {
+		quantity = b.balance * b.entrySizePct(decision.Confidence) / 100 / price
+	}
+
+	fee := price * quantity * b.takerFee
+	b.balance -= fee
 
 	b.position = &Position{
 		ID:         b.generateID(),
@@ -142,16 +226,51 @@ func (b *Bot) enterPosition(side string, price float64, decision *Decision) {
 		Quantity:   quantity,
 		CurrentPrice: price,
 		UnrealizedPnL: 0,
+		HighWaterMark: price,
+		StopLoss:   decision.StopLoss,
+		Fees:       fee,
 		OpenedAt:   decision.Timestamp,
 	}
 }
 
+// fillPrice applies slippage to a market fill at price, raising buys and
+// lowering sells
+func (b *Bot) fillPrice(price float64, buying bool) float64 {
+	slip := price * b.slippageBps / 10000
+	if buying {
+		return price + slip
+	}
+	return price - slip
+}
+
+// entrySizePct returns the percent of balance to invest at a confidence.
+// The configured position size is the maximum and is scaled by confidence,
+// which is floored at minConfidence so weak signals still take a small
+// position. A confidence of 0 means the strategy did not report one and
+// takes the full size.
+func (b *Bot) entrySizePct(confidence float64) float64 {
+	maxPct := b.positionSize
+	if maxPct <= 0 {
+		maxPct = defaultPositionSizePct
+	}
+	maxPct = math.Min(maxPct, 100)
+
+	if confidence <= 0 {
+		return maxPct
+	}
+	confidence = math.Max(minConfidence, math.Min(confidence, 100))
+	return maxPct * confidence / 100
+}
+
 // closePosition closes the current position
 func (b *Bot) closePosition(price float64) {
 	if b.position == nil {
 		return
 	}
 
+	// Longs sell to close and shorts buy to close
+	price = b.fillPrice(price, b.position.Side != "long")
+
 	// Calculate PnL
 	var pnl float64
 	if b.position.Side == "long" {
@@ -160,13 +279,20 @@ func (b *Bot) closePosition(price float64) {
 		pnl = (b.position.EntryPrice - price) * b.position.Quantity
 	}
 
+	// The entry fee already left the balance when the position opened
+	exitFee := price * b.position.Quantity * b.takerFee
+	b.position.Fees += exitFee
+
 	b.position.CurrentPrice = price
-	b.position.RealizedPnL = pnl
-	now := time.Now()
-	b.position.ClosedAt = &now
+	b.position.RealizedPnL = pnl - b.position.Fees
+	closedAt := b.lastCandleAt
+	if closedAt.IsZero() {
+		closedAt = time.Now()
+	}
+	b.position.ClosedAt = &closedAt
 
 	// Update balance
-	b.balance += pnl
+	b.balance += pnl - exitFee
 
 	// Store trade
 	b.trades = append(b.trades, *b.position)
@@ -175,7 +301,8 @@ func (b *Bot) closePosition(price float64) {
 	b.position = nil
 }
 
-// updatePosition updates unrealized PnL
+// updatePosition updates unrealized PnL and closes long positions that hit
+// their stop loss or retrace past the trailing stop
 func (b *Bot) updatePosition(price float64) {
 	if b.position == nil {
 		return
@@ -188,6 +315,35 @@ func (b *Bot) updatePosition(price float64) {
 	} else {
 		b.position.UnrealizedPnL = (b.position.EntryPrice - price) * b.position.Quantity
 	}
+	b.position.UnrealizedPnL -= b.position.Fees
+
+	if b.position.Side != "long" {
+		return
+	}
+
+	if b.position.StopLoss > 0 && price <= b.position.StopLoss {
+		b.closePosition(price)
+		return
+	}
+
+	if b.trailingStopPct <= 0 {
+		return
+	}
+
+	if price > b.position.HighWaterMark {
+		b.position.HighWaterMark = price
+	}
+
+	stop := b.position.HighWaterMark * (1 - b.trailingStopPct/100)
+	if price <= stop {
+		b.closePosition(price)
+	}
+}
+
+// ClosePosition closes the open position, if any, at price with the usual
+// slippage and fees, e.g. to settle a replay at its last candle
+func (b *Bot) ClosePosition(price float64) {
+	b.closePosition(price)
 }
 
 // GetPosition returns the current position
@@ -200,13 +356,41 @@ func (b *Bot) GetBalance() float64 {
 	return b.balance
 }
 
-// GetTotalPnL returns total profit/loss
+// GetRealizedPnL returns the summed P&L of closed trades
+func (b *Bot) GetRealizedPnL() float64 {
+	realized := 0.0
+	for _, trade := range b.trades {
+		realized += trade.RealizedPnL
+	}
+	return realized
+}
+
+// GetUnrealizedPnL returns the P&L of the open position, or 0 when flat
+func (b *Bot) GetUnrealizedPnL() float64 {
+	if b.position == nil {
+		return 0
+	}
+	return b.position.UnrealizedPnL
+}
+
+// GetTotalPnL returns realized plus unrealized profit/loss
 func (b *Bot) GetTotalPnL() float64 {
-	total := b.balance - b.initialBalance
-	if b.position != nil {
-		total += b.position.UnrealizedPnL
+	return b.GetRealizedPnL() + b.GetUnrealizedPnL()
+}
+
+// GetWinRate returns the percent of closed trades with positive P&L, or 0
+// before any trade has closed
+func (b *Bot) GetWinRate() float64 {
+	if len(b.trades) == 0 {
+		return 0
+	}
+	wins := 0
+	for _, trade := range b.trades {
+		if trade.RealizedPnL > 0 {
+			wins++
+		}
 	}
-	return total
+	return float64(wins) / float64(len(b.trades)) * 100
 }
 
 // GetTrades returns all completed trades
@@ -214,7 +398,8 @@ func (b *Bot) GetTrades() []Position {
 	return b.trades
 }
 
-// generateID generates a simple ID
+// generateID returns a random UUID, so IDs stay unique across bots and
+// runs once trades are persisted
 func (b *Bot) generateID() string {
-	return time.Now().Format("20060102150405")
+	return uuid.New().String()
 }
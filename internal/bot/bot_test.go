@@ -0,0 +1,373 @@
+package bot
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"candlecore/internal/exchange"
+
+	"github.com/google/uuid"
+)
+
+// scriptedStrategy buys on the first call and holds afterwards. A non-zero
+// stopPct attaches a stop loss that far below the entry close.
+type scriptedStrategy struct {
+	calls   int
+	stopPct float64
+}
+
+func (s *scriptedStrategy) Name() string { return "scripted" }
+
+func (s *scriptedStrategy) Analyze(candles []exchange.Candle) (*Decision, error) {
+	s.calls++
+	signal := SignalHold
+	if s.calls == 1 {
+		signal = SignalBuy
+	}
+	last := candles[len(candles)-1]
+	decision := &Decision{Signal: signal, Timestamp: last.Timestamp}
+	if signal == SignalBuy && s.stopPct > 0 {
+		decision.StopLoss = last.Close * (1 - s.stopPct/100)
+	}
+	return decision, nil
+}
+
+func (s *scriptedStrategy) Configure(params map[string]interface{}) error { return nil }
+
+// staticProvider returns the candles seen so far
+type staticProvider struct {
+	candles []exchange.Candle
+}
+
+func (p *staticProvider) GetCandles(symbol string, timeframe exchange.Timeframe, limit int) ([]exchange.Candle, error) {
+	return p.candles, nil
+}
+
+func (p *staticProvider) StreamCandles(symbol string, timeframe exchange.Timeframe) (<-chan exchange.Candle, error) {
+	return nil, nil
+}
+
+func (p *staticProvider) GetSupportedTimeframes() []exchange.Timeframe { return nil }
+func (p *staticProvider) GetSupportedSymbols() []string                { return nil }
+
+// replay feeds closes through the bot one candle at a time
+func replay(t *testing.T, b *Bot, provider *staticProvider, closes ...float64) {
+	t.Helper()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, c := range closes {
+		candle := exchange.Candle{Timestamp: start.Add(time.Duration(i) * time.Hour), Open: c, High: c, Low: c, Close: c}
+		provider.candles = append(provider.candles, candle)
+		if _, err := b.ProcessCandle(candle); err != nil {
+			t.Fatalf("ProcessCandle() error: %v", err)
+		}
+	}
+}
+
+func TestTrailingStop(t *testing.T) {
+	provider := &staticProvider{}
+	b := NewBot(&scriptedStrategy{}, provider, Config{
+		Symbol:          "bitcoin",
+		Timeframe:       exchange.Timeframe1h,
+		InitialBalance:  10000,
+		TrailingStopPct: 5,
+	})
+
+	// Peak at 120 puts the stop at 114
+	replay(t, b, provider, 100, 110, 120, 118, 116, 114, 112, 110)
+
+	if b.GetPosition() != nil {
+		t.Fatal("expected trailing stop to close the position")
+	}
+	trades := b.GetTrades()
+	if len(trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(trades))
+	}
+	if trades[0].HighWaterMark != 120 {
+		t.Errorf("high water mark = %.2f, want 120", trades[0].HighWaterMark)
+	}
+	if math.Abs(trades[0].CurrentPrice-114) > 1e-9 {
+		t.Errorf("exit price = %.2f, want 114", trades[0].CurrentPrice)
+	}
+}
+
+func TestTrailingStopDisabled(t *testing.T) {
+	provider := &staticProvider{}
+	b := NewBot(&scriptedStrategy{}, provider, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+	})
+
+	replay(t, b, provider, 100, 120, 110, 90)
+
+	if b.GetPosition() == nil {
+		t.Fatal("expected position to stay open without a trailing stop")
+	}
+}
+
+func TestRiskSizedEntry(t *testing.T) {
+	provider := &staticProvider{}
+	b := NewBot(&scriptedStrategy{stopPct: 5}, provider, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+		RiskPct:        1,
+	})
+
+	// Entry at 100 with a stop at 95 risks 1% of 10000 over a 5 point distance
+	replay(t, b, provider, 100, 98, 95)
+
+	trades := b.GetTrades()
+	if len(trades) != 1 {
+		t.Fatalf("trades = %d, want 1 (stop loss should close the position)", len(trades))
+	}
+	if math.Abs(trades[0].Quantity-20) > 1e-9 {
+		t.Errorf("quantity = %.4f, want 20", trades[0].Quantity)
+	}
+	if math.Abs(trades[0].RealizedPnL+100) > 1e-9 {
+		t.Errorf("realized PnL = %.4f, want -100 (1%% of balance)", trades[0].RealizedPnL)
+	}
+}
+
+func TestRiskSizingFallsBackWithoutStop(t *testing.T) {
+	provider := &staticProvider{}
+	b := NewBot(&scriptedStrategy{}, provider, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+		RiskPct:        1,
+	})
+
+	replay(t, b, provider, 100)
+
+	position := b.GetPosition()
+	if position == nil {
+		t.Fatal("expected an open position")
+	}
+	if math.Abs(position.Quantity-10) > 1e-9 {
+		t.Errorf("quantity = %.4f, want fixed sizing of 10", position.Quantity)
+	}
+}
+
+// confidentStrategy buys on the first call with a fixed confidence
+type confidentStrategy struct {
+	scriptedStrategy
+	confidence float64
+}
+
+func (s *confidentStrategy) Analyze(candles []exchange.Candle) (*Decision, error) {
+	decision, err := s.scriptedStrategy.Analyze(candles)
+	if err == nil {
+		decision.Confidence = s.confidence
+	}
+	return decision, err
+}
+
+func TestConfidenceWeightedSizing(t *testing.T) {
+	tests := []struct {
+		name         string
+		positionSize float64
+		confidence   float64
+		want         float64
+	}{
+		{"full confidence", 20, 100, 20},
+		{"half confidence", 20, 50, 10},
+		{"floored confidence", 20, 5, 4},
+		{"clamped confidence", 20, 150, 20},
+		{"unreported confidence", 20, 0, 20},
+		{"default size", 0, 50, 5},
+		{"clamped size", 150, 100, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &staticProvider{}
+			b := NewBot(&confidentStrategy{confidence: tt.confidence}, provider, Config{
+				Symbol:         "bitcoin",
+				Timeframe:      exchange.Timeframe1h,
+				InitialBalance: 10000,
+				PositionSize:   tt.positionSize,
+			})
+
+			replay(t, b, provider, 100)
+
+			position := b.GetPosition()
+			if position == nil {
+				t.Fatal("expected an open position")
+			}
+			if math.Abs(position.Quantity-tt.want) > 1e-9 {
+				t.Errorf("quantity = %.4f, want %.4f", position.Quantity, tt.want)
+			}
+		})
+	}
+}
+
+// sequenceStrategy emits one signal per call, holding once the script runs out
+type sequenceStrategy struct {
+	signals []Signal
+	calls   int
+}
+
+func (s *sequenceStrategy) Name() string { return "sequence" }
+
+func (s *sequenceStrategy) Analyze(candles []exchange.Candle) (*Decision, error) {
+	signal := SignalHold
+	if s.calls < len(s.signals) {
+		signal = s.signals[s.calls]
+	}
+	s.calls++
+	return &Decision{Signal: signal, Timestamp: candles[len(candles)-1].Timestamp}, nil
+}
+
+func (s *sequenceStrategy) Configure(params map[string]interface{}) error { return nil }
+
+func TestStopsFireDuringRepeatedBuySignals(t *testing.T) {
+	buys := make([]Signal, 8)
+	for i := range buys {
+		buys[i] = SignalBuy
+	}
+
+	provider := &staticProvider{}
+	b := NewBot(&sequenceStrategy{signals: buys}, provider, Config{
+		Symbol:          "bitcoin",
+		Timeframe:       exchange.Timeframe1h,
+		InitialBalance:  10000,
+		TrailingStopPct: 5,
+	})
+
+	// Peak at 120 puts the stop at 114; BUY keeps firing through the fall
+	replay(t, b, provider, 100, 110, 120, 116, 113, 108)
+
+	trades := b.GetTrades()
+	if len(trades) != 1 {
+		t.Fatalf("trades = %d, want the trailing stop exit", len(trades))
+	}
+	if trades[0].HighWaterMark != 120 {
+		t.Errorf("high water mark = %.2f, want 120", trades[0].HighWaterMark)
+	}
+	if trades[0].CurrentPrice != 113 {
+		t.Errorf("exit price = %.2f, want 113", trades[0].CurrentPrice)
+	}
+
+	// The next BUY re-enters after the stop-out candle
+	if position := b.GetPosition(); position == nil || position.EntryPrice != 108 {
+		t.Errorf("position = %+v, want a re-entry at 108", position)
+	}
+}
+
+func TestPnLBreakdown(t *testing.T) {
+	provider := &staticProvider{}
+	strategy := &sequenceStrategy{signals: []Signal{SignalBuy, SignalSell, SignalBuy, SignalHold}}
+	b := NewBot(strategy, provider, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+		PositionSize:   10,
+	})
+
+	// 10 units bought at 100 and sold at 110, then 10.1 units bought at 100
+	// and marked at 90
+	replay(t, b, provider, 100, 110, 100, 90)
+
+	if got := b.GetRealizedPnL(); math.Abs(got-100) > 1e-9 {
+		t.Errorf("GetRealizedPnL() = %.4f, want 100", got)
+	}
+	if got := b.GetUnrealizedPnL(); math.Abs(got-(-101)) > 1e-9 {
+		t.Errorf("GetUnrealizedPnL() = %.4f, want -101", got)
+	}
+	if got := b.GetTotalPnL(); math.Abs(got-(-1)) > 1e-9 {
+		t.Errorf("GetTotalPnL() = %.4f, want -1", got)
+	}
+	if got := b.GetWinRate(); got != 100 {
+		t.Errorf("GetWinRate() = %.2f, want 100", got)
+	}
+}
+
+func TestRoundTripFees(t *testing.T) {
+	provider := &staticProvider{}
+	strategy := &sequenceStrategy{signals: []Signal{SignalBuy, SignalSell}}
+	b := NewBot(strategy, provider, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+		PositionSize:   10,
+		TakerFee:       0.001,
+		SlippageBps:    10,
+	})
+
+	replay(t, b, provider, 100, 100)
+
+	// Buys fill at 100.1 and sell at 99.9; 1000 / 100.1 units are bought
+	qty := 1000 / 100.1
+	entryFee := 1000 * 0.001
+	exitFee := 99.9 * qty * 0.001
+	slippage := 0.2 * qty
+	wantLoss := entryFee + exitFee + slippage
+
+	trades := b.GetTrades()
+	if len(trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(trades))
+	}
+	if math.Abs(trades[0].Fees-(entryFee+exitFee)) > 1e-9 {
+		t.Errorf("Fees = %.6f, want %.6f", trades[0].Fees, entryFee+exitFee)
+	}
+	if math.Abs(trades[0].RealizedPnL+wantLoss) > 1e-9 {
+		t.Errorf("RealizedPnL = %.6f, want %.6f", trades[0].RealizedPnL, -wantLoss)
+	}
+	if math.Abs(b.GetBalance()-(10000-wantLoss)) > 1e-9 {
+		t.Errorf("balance = %.6f, want %.6f", b.GetBalance(), 10000-wantLoss)
+	}
+}
+
+func TestOpenPositionPnLIncludesEntryFee(t *testing.T) {
+	provider := &staticProvider{}
+	b := NewBot(&scriptedStrategy{}, provider, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+		PositionSize:   10,
+		TakerFee:       0.001,
+	})
+
+	replay(t, b, provider, 100, 100)
+
+	// A flat price leaves only the 1.00 entry fee
+	if got := b.GetUnrealizedPnL(); math.Abs(got+1) > 1e-9 {
+		t.Errorf("GetUnrealizedPnL() = %.4f, want -1", got)
+	}
+	if got := b.GetTotalPnL(); math.Abs(got-(b.GetBalance()-10000)) > 1e-9 {
+		t.Errorf("GetTotalPnL() = %.4f, want the balance change %.4f", got, b.GetBalance()-10000)
+	}
+}
+
+func TestTradeIDsAreUnique(t *testing.T) {
+	b := NewBot(&sequenceStrategy{}, &staticProvider{}, Config{
+		Symbol:         "bitcoin",
+		Timeframe:      exchange.Timeframe1h,
+		InitialBalance: 10000,
+		PositionSize:   10,
+	})
+
+	// Every round trip happens on the same candle, within the same second
+	candle := exchange.Candle{Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Open: 100, High: 100, Low: 100, Close: 100}
+	for i := 0; i < 50; i++ {
+		b.executeDecision(&Decision{Signal: SignalBuy, Timestamp: candle.Timestamp}, candle)
+		b.executeDecision(&Decision{Signal: SignalSell, Timestamp: candle.Timestamp}, candle)
+	}
+
+	trades := b.GetTrades()
+	if len(trades) != 50 {
+		t.Fatalf("trades = %d, want 50", len(trades))
+	}
+	seen := make(map[string]bool)
+	for _, trade := range trades {
+		if _, err := uuid.Parse(trade.ID); err != nil {
+			t.Errorf("ID %q is not a UUID: %v", trade.ID, err)
+		}
+		if seen[trade.ID] {
+			t.Errorf("duplicate ID %q", trade.ID)
+		}
+		seen[trade.ID] = true
+	}
+}
@@ -0,0 +1,108 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+
+	"candlecore/internal/engine"
+)
+
+// NoopBroker is a dry-run engine.Broker for inspecting the orders a
+// strategy produces. Every order is recorded and reported as filled at its
+// price, but balance and equity never change. Positions follow the
+// recorded quantities only so the engine and strategy see their own
+// entries and forward sell signals; they carry no P&L.
+type NoopBroker struct {
+	balance   float64
+	orders    []*engine.Order
+	positions map[string]*engine.Position
+}
+
+// NewNoopBroker creates a dry-run broker that always reports balance
+func NewNoopBroker(balance float64) *NoopBroker {
+	return &NoopBroker{
+		balance:   balance,
+		positions: make(map[string]*engine.Position),
+	}
+}
+
+// GetAccount returns the fixed balance and the recorded positions, ordered
+// by symbol
+func (b *NoopBroker) GetAccount() *engine.Account {
+	account := &engine.Account{
+		Balance: b.balance,
+		Equity:  b.balance,
+	}
+	symbols := make([]string, 0, len(b.positions))
+	for symbol := range b.positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		account.Positions = append(account.Positions, b.positions[symbol])
+	}
+	return account
+}
+
+// PlaceOrder records the order and marks it filled without moving funds
+func (b *NoopBroker) PlaceOrder(order *engine.Order) error {
+	if order.ID == "" {
+		order.ID = fmt.Sprintf("noop-%d", len(b.orders)+1)
+	}
+	b.orders = append(b.orders, order)
+
+	qty := order.Quantity
+	switch order.Side {
+	case engine.OrderSideBuy:
+		if p, ok := b.positions[order.Symbol]; ok {
+			p.Quantity += qty
+		} else {
+			b.positions[order.Symbol] = &engine.Position{
+				Symbol:       order.Symbol,
+				Side:         engine.OrderSideBuy,
+				EntryPrice:   order.Price,
+				Quantity:     qty,
+				CurrentPrice: order.Price,
+				OpenedAt:     order.Timestamp,
+			}
+		}
+	case engine.OrderSideSell:
+		p, ok := b.positions[order.Symbol]
+		if ok {
+			if qty <= 0 || qty > p.Quantity {
+				qty = p.Quantity
+			}
+			p.Quantity -= qty
+			if p.Quantity <= 0 {
+				delete(b.positions, order.Symbol)
+			}
+		}
+	}
+
+	order.Status = engine.OrderStatusFilled
+	order.FilledPrice = order.Price
+	order.FilledQty = qty
+	return nil
+}
+
+// CancelOrder is a no-op; recorded orders fill immediately
+func (b *NoopBroker) CancelOrder(orderID string) error { return nil }
+
+// UpdateMarketPrice records the latest price on the symbol's position
+func (b *NoopBroker) UpdateMarketPrice(symbol string, price float64) {
+	if p, ok := b.positions[symbol]; ok {
+		p.CurrentPrice = price
+	}
+}
+
+// GetPosition returns the recorded position for symbol, or nil
+func (b *NoopBroker) GetPosition(symbol string) *engine.Position {
+	return b.positions[symbol]
+}
+
+// GetPlacedOrders returns every order placed so far, oldest first
+func (b *NoopBroker) GetPlacedOrders() []*engine.Order {
+	orders := make([]*engine.Order, len(b.orders))
+	copy(orders, b.orders)
+	return orders
+}
@@ -0,0 +1,96 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/logger"
+)
+
+// scriptedStrategy returns a predetermined signal per candle index
+type scriptedStrategy struct {
+	signals map[int]engine.Signal
+	index   int
+}
+
+func (s *scriptedStrategy) Name() string { return "scripted" }
+
+func (s *scriptedStrategy) OnCandle(candle engine.Candle, account *engine.Account) engine.Signal {
+	defer func() { s.index++ }()
+	if signal, ok := s.signals[s.index]; ok {
+		return signal
+	}
+	return engine.Signal{Action: engine.SignalActionHold}
+}
+
+func (s *scriptedStrategy) OnTrade(trade *engine.Trade) {}
+
+type nopStore struct{}
+
+func (nopStore) SaveState(broker engine.Broker) error { return nil }
+func (nopStore) LoadState(broker engine.Broker) error { return nil }
+
+func TestNoopBrokerRecordsSignals(t *testing.T) {
+	strategy := &scriptedStrategy{signals: map[int]engine.Signal{
+		0: {Action: engine.SignalActionBuy, Symbol: "BTC/USD", Quantity: 2, Reason: "entry"},
+		1: {Action: engine.SignalActionBuy, Symbol: "BTC/USD", Quantity: 1, Reason: "add"},
+		2: {Action: engine.SignalActionSell, Symbol: "BTC/USD", Quantity: 3, Reason: "exit"},
+		// Flat again, so the engine drops this sell before the broker
+		3: {Action: engine.SignalActionSell, Symbol: "BTC/USD", Quantity: 1, Reason: "ignored"},
+	}}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{100, 105, 110, 108}
+	candles := make([]engine.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = engine.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      c,
+			High:      c,
+			Low:       c,
+			Close:     c,
+		}
+	}
+
+	b := NewNoopBroker(10000)
+	e := engine.New(b, strategy, nopStore{}, logger.New("error"))
+	if err := e.Run(context.Background(), candles); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	want := []struct {
+		side   engine.OrderSide
+		qty    float64
+		price  float64
+		reason string
+	}{
+		{engine.OrderSideBuy, 2, 100, "entry"},
+		{engine.OrderSideBuy, 1, 105, "add"},
+		{engine.OrderSideSell, 3, 110, "exit"},
+	}
+
+	orders := b.GetPlacedOrders()
+	if len(orders) != len(want) {
+		t.Fatalf("recorded orders = %d, want %d", len(orders), len(want))
+	}
+	for i, w := range want {
+		o := orders[i]
+		if o.Side != w.side || o.Quantity != w.qty || o.Price != w.price || o.Reason != w.reason {
+			t.Errorf("order %d = %s %.0f @ %.0f (%s), want %s %.0f @ %.0f (%s)",
+				i, o.Side, o.Quantity, o.Price, o.Reason, w.side, w.qty, w.price, w.reason)
+		}
+		if o.Status != engine.OrderStatusFilled {
+			t.Errorf("order %d status = %s, want filled", i, o.Status)
+		}
+	}
+
+	account := b.GetAccount()
+	if account.Balance != 10000 || account.Equity != 10000 {
+		t.Errorf("account = %.2f / %.2f, want balance and equity unchanged at 10000", account.Balance, account.Equity)
+	}
+	if len(account.Positions) != 0 {
+		t.Errorf("positions = %d, want none after the exit", len(account.Positions))
+	}
+}
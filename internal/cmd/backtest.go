@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"candlecore/internal/audit"
+	"candlecore/internal/backtest"
+	"candlecore/internal/config"
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+	"candlecore/internal/fetcher"
+	"candlecore/internal/logger"
+	"candlecore/internal/strategy"
+)
+
+// engineConfig returns the engine run settings for cfg: its initial
+// balance and, when audit_log is set, an auditor that close releases
+func engineConfig(cfg *config.Config) (run backtest.Config, close func(), err error) {
+	run = backtest.Config{InitialBalance: cfg.InitialBalance}
+	if cfg.AuditLog == "" {
+		return run, func() {}, nil
+	}
+
+	auditor, err := audit.OpenFile(cfg.AuditLog)
+	if err != nil {
+		return run, nil, err
+	}
+	run.Auditor = auditor
+	return run, func() { auditor.Close() }, nil
+}
+
+// runConfiguredBacktest runs the strategy selected in cfg over the
+// provider's candles for coin on the backtest engine, starting from
+// cfg.InitialBalance. Orders are appended to cfg.AuditLog when it is set.
+func runConfiguredBacktest(ctx context.Context, cfg *config.Config, provider exchange.DataProvider, coin string, timeframe exchange.Timeframe) (*backtest.Result, error) {
+	s, err := strategy.FromConfig(cfg.Strategy, coin)
+	if err != nil {
+		return nil, err
+	}
+
+	candles, err := provider.GetCandles(coin, timeframe, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+
+	run, closeAudit, err := engineConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAudit()
+
+	return backtest.Run(ctx, exchange.ToEngineCandles(candles, coin), s, run)
+}
+
+// liveSetup returns the settings and strategy for --live. A config file
+// supplies both when configPath is set; otherwise the environment supplies
+// the settings, req the initial balance and the bot strategy, which runs
+// through FromBotStrategy.
+func liveSetup(configPath string, req backtest.ReplayRequest) (*config.Config, engine.Strategy, error) {
+	if configPath != "" {
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		s, err := strategy.FromConfig(cfg.Strategy, req.Symbol)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cfg, s, nil
+	}
+
+	cfg := config.FromEnv()
+	cfg.InitialBalance = req.InitialBalance
+	s, err := req.BuildStrategy()
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, strategy.FromBotStrategy(s), nil
+}
+
+// runLive paper trades s on Binance candles for coin as they close, polling
+// every poll, until ctx is cancelled. The engine logs at cfg.LogLevel and
+// orders are appended to cfg.AuditLog when it is set.
+func runLive(ctx context.Context, cfg *config.Config, s engine.Strategy, coin, interval string, poll time.Duration) (*backtest.Result, error) {
+	symbol := fetcher.BinanceSymbolFromCoinID(coin)
+	if symbol == "" {
+		return nil, fmt.Errorf("no Binance symbol known for %s", coin)
+	}
+
+	run, closeAudit, err := engineConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAudit()
+	run.Logger = logger.New(cfg.LogLevel)
+
+	candles, errs := fetcher.NewBinanceFetcher().StreamCandles(ctx, symbol, interval, poll)
+	return backtest.RunLive(ctx, candles, errs, s, run)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/audit"
+	"candlecore/internal/config"
+	"candlecore/internal/exchange"
+)
+
+func TestRunConfiguredBacktest(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("timestamp,open,high,low,close,volume\n")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 300; i++ {
+		price := 100 + 10*math.Sin(float64(i)/8)
+		fmt.Fprintf(&b, "%s,%.4f,%.4f,%.4f,%.4f,1000\n",
+			start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339),
+			price, price+1, price-1, price)
+	}
+	dir := filepath.Dir(writeDataFile(t, b.String()))
+	provider := exchange.NewLocalFileProvider(dir)
+
+	for _, name := range []string{"ma", "rsi", "macd"} {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Strategy.Name = name
+			cfg.InitialBalance = 5000
+			cfg.AuditLog = filepath.Join(t.TempDir(), "audit.jsonl")
+
+			result, err := runConfiguredBacktest(context.Background(), cfg, provider, "bitcoin", exchange.Timeframe1h)
+			if err != nil {
+				t.Fatalf("runConfiguredBacktest() error: %v", err)
+			}
+			if result.InitialBalance != 5000 {
+				t.Errorf("InitialBalance = %.2f, want 5000", result.InitialBalance)
+			}
+			if result.TradeCount == 0 {
+				t.Fatal("expected trades on an oscillating series")
+			}
+
+			// Each trade has at least a buy and a sell, each recorded as
+			// placed then filled
+			seq, _, err := audit.Verify(cfg.AuditLog)
+			if err != nil {
+				t.Fatalf("Verify() error: %v", err)
+			}
+			if min := uint64(4 * result.TradeCount); seq < min {
+				t.Errorf("audit records = %d, want at least %d", seq, min)
+			}
+		})
+	}
+
+	cfg := config.Default()
+	cfg.Strategy.Name = "rsi"
+	cfg.Strategy.RSI.Oversold = 90
+	if _, err := runConfiguredBacktest(context.Background(), cfg, provider, "bitcoin", exchange.Timeframe1h); err == nil {
+		t.Error("runConfiguredBacktest() expected error for invalid rsi levels")
+	}
+}
@@ -2,10 +2,23 @@ package cmd
 
 import (
 	"candlecore/internal/api"
+	"candlecore/internal/backtest"
+	"candlecore/internal/config"
+	"candlecore/internal/exchange"
+	"candlecore/internal/export"
+	"candlecore/internal/fetcher"
+	"candlecore/internal/report"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -33,26 +46,310 @@ var serveCmd = &cobra.Command{
 	Long:  "Starts the REST API and WebSocket server for bot control and frontend integration.",
 	Run: func(cmd *cobra.Command, args []string) {
 		port, _ := cmd.Flags().GetString("port")
+		workers, _ := cmd.Flags().GetInt("backtest-workers")
 		
 		fmt.Printf("Starting Candlecore API Server on port %s...\n", port)
 		fmt.Printf("Data directory: %s\n", dataDir)
 		fmt.Println()
 		
-		server := api.NewServer(dataDir)
-		
-		if err := server.Run(port); err != nil {
+		server := api.NewServer(dataDir, workers)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := server.Run(ctx, port); err != nil {
 			fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 			os.Exit(1)
 		}
 	},
 }
 
+// dataCmd groups historical data management commands
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Manage historical candle data",
+}
+
+// scrapeCmd downloads candles for one or all coins across several intervals
+var scrapeCmd = &cobra.Command{
+	Use:   "scrape",
+	Short: "Download historical candles",
+	Long: `Downloads historical candles for a coin, or every supported coin with
+--coin all, into the data directory.
+
+Candles are paged from Binance klines for the coin's USDT pair. Each interval
+is written to {coin}_{interval}.csv and all requests share one rate limit.`,
+	Example: "  candlecore data scrape --coin bitcoin --intervals 1h,4h,1d --days 90\n  candlecore data scrape --coin all --intervals 1d --days 365",
+	Run: func(cmd *cobra.Command, args []string) {
+		coin, _ := cmd.Flags().GetString("coin")
+		intervalList, _ := cmd.Flags().GetString("intervals")
+		days, _ := cmd.Flags().GetInt("days")
+		delay, _ := cmd.Flags().GetDuration("request-delay")
+
+		coins := []string{coin}
+		if coin == "all" {
+			coins = fetcher.SupportedCoins()
+		} else if !fetcher.ValidateCoinID(coin) {
+			fmt.Fprintf(os.Stderr, "Unsupported coin: %s\n", coin)
+			os.Exit(1)
+		}
+		if days <= 0 {
+			fmt.Fprintln(os.Stderr, "--days must be positive")
+			os.Exit(1)
+		}
+
+		intervals, err := parseIntervals(intervalList)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid intervals: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		limiter := &throttle{interval: delay}
+		failed := 0
+
+	downloads:
+		for _, coin := range coins {
+			for _, interval := range intervals {
+				fmt.Printf("Downloading %s %s (%d days)...\n", coin, interval, days)
+
+				candles, err := scrapeInterval(ctx, limiter, coin, interval, days)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "  Failed: %v\n", err)
+					failed++
+					if ctx.Err() != nil {
+						break downloads
+					}
+					continue
+				}
+
+				filename := filepath.Join(dataDir, fmt.Sprintf("%s_%s.csv", coin, interval))
+				if err := writeCandlesCSV(filename, candles); err != nil {
+					fmt.Fprintf(os.Stderr, "  Failed: %v\n", err)
+					failed++
+					continue
+				}
+
+				fmt.Printf("  Wrote %d candles to %s (%s to %s)\n", len(candles), filename,
+					candles[0].Timestamp.UTC().Format("2006-01-02 15:04"),
+					candles[len(candles)-1].Timestamp.UTC().Format("2006-01-02 15:04"))
+			}
+		}
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// validateCmd checks a candle CSV file before it is used for backtesting
+var validateCmd = &cobra.Command{
+	Use:   "validate <file>",
+	Short: "Check a candle CSV file for bad rows and gaps",
+	Long: `Loads a candle CSV file the same way backtests do and reports the candle
+count, date range, detected interval, gaps and every row that was rejected.
+Exits non-zero when any problem is found.`,
+	Example: "  candlecore data validate data/historical/bitcoin_1h.csv",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ok, err := validateDataFile(os.Stdout, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Validation failed: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+// backtestCmd replays local candles through a strategy and prints a summary
+var backtestCmd = &cobra.Command{
+	Use:   "backtest",
+	Short: "Backtest a strategy on local candle data",
+	Long: `Replays {coin}_{interval}.csv from the data directory through a strategy
+and prints the performance summary. Use "data scrape" to download candles first.
+
+With --config, the strategy, initial balance and audit log come from a YAML
+config file instead of the strategy flags.
+
+With --live, the strategy paper trades Binance candles as they close instead,
+with no fees, until interrupted, then prints the summary.`,
+	Example: `  candlecore backtest --coin bitcoin --interval 1h --strategy ma_crossover --fast 10 --slow 30
+  candlecore backtest --coin bitcoin --interval 1h --config config.yaml
+  candlecore backtest --coin bitcoin --interval 1m --strategy rsi --live`,
+	Run: func(cmd *cobra.Command, args []string) {
+		coin, _ := cmd.Flags().GetString("coin")
+		interval, _ := cmd.Flags().GetString("interval")
+		strategy, _ := cmd.Flags().GetString("strategy")
+		balance, _ := cmd.Flags().GetFloat64("balance")
+		reportPath, _ := cmd.Flags().GetString("report")
+		exportPath, _ := cmd.Flags().GetString("export")
+		configPath, _ := cmd.Flags().GetString("config")
+		live, _ := cmd.Flags().GetBool("live")
+		pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+
+		req := backtest.ReplayRequest{
+			Symbol:         coin,
+			Timeframe:      interval,
+			Strategy:       strategy,
+			DataSource:     string(exchange.DataSourceLocal),
+			InitialBalance: balance,
+		}
+		req.FastPeriod, _ = cmd.Flags().GetInt("fast")
+		req.SlowPeriod, _ = cmd.Flags().GetInt("slow")
+		req.Period, _ = cmd.Flags().GetInt("rsi-period")
+		req.Oversold, _ = cmd.Flags().GetFloat64("oversold")
+		req.Overbought, _ = cmd.Flags().GetFloat64("overbought")
+		req.TakerFee, _ = cmd.Flags().GetFloat64("taker-fee")
+		req.SlippageBps, _ = cmd.Flags().GetFloat64("slippage-bps")
+
+		if !exchange.Timeframe(interval).IsValid() {
+			fmt.Fprintf(os.Stderr, "Invalid interval: %s\n", interval)
+			os.Exit(1)
+		}
+		if balance <= 0 {
+			fmt.Fprintln(os.Stderr, "--balance must be positive")
+			os.Exit(1)
+		}
+		if req.TakerFee < 0 || req.TakerFee > 1 || req.SlippageBps < 0 {
+			fmt.Fprintln(os.Stderr, "--taker-fee must be between 0 and 1 and --slippage-bps non-negative")
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		provider := exchange.NewLocalFileProvider(dataDir)
+		r := report.Report{
+			Title:      fmt.Sprintf("%s %s %s", coin, interval, strategy),
+			Parameters: backtestParams(cmd),
+		}
+
+		if live {
+			if pollInterval <= 0 {
+				fmt.Fprintln(os.Stderr, "--poll-interval must be positive")
+				os.Exit(1)
+			}
+
+			cfg, s, err := liveSetup(configPath, req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Live setup failed: %v\n", err)
+				os.Exit(1)
+			}
+			if configPath != "" {
+				r.Title = fmt.Sprintf("%s %s %s", coin, interval, cfg.Strategy.Name)
+			}
+
+			fmt.Printf("Paper trading %s %s on Binance; press Ctrl+C to stop\n", coin, interval)
+			result, err := runLive(ctx, cfg, s, coin, interval, pollInterval)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Live run failed: %v\n", err)
+				os.Exit(1)
+			}
+			r.InitialBalance, r.FinalBalance, r.Trades = result.InitialBalance, result.FinalBalance, result.Trades
+		} else if configPath != "" {
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+				os.Exit(1)
+			}
+
+			result, err := runConfiguredBacktest(ctx, cfg, provider, coin, exchange.Timeframe(interval))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Backtest failed: %v\n", err)
+				os.Exit(1)
+			}
+			r.Title = fmt.Sprintf("%s %s %s", coin, interval, cfg.Strategy.Name)
+			r.InitialBalance, r.FinalBalance, r.Trades = result.InitialBalance, result.FinalBalance, result.Trades
+		} else {
+			result, err := backtest.RunReplay(ctx, provider, req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Backtest failed: %v\n", err)
+				os.Exit(1)
+			}
+			r.InitialBalance, r.FinalBalance, r.Trades = result.InitialBalance, result.FinalBalance, result.Trades
+		}
+
+		if err := report.WriteMarkdown(os.Stdout, r); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print summary: %v\n", err)
+			os.Exit(1)
+		}
+
+		if reportPath != "" {
+			if err := report.Write(reportPath, r); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("\nReport written to %s\n", reportPath)
+		}
+
+		if exportPath != "" {
+			if err := export.WriteFile(exportPath, r.Trades); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to export trades: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("\nExported %d trades to %s\n", len(r.Trades), exportPath)
+		}
+	},
+}
+
 func init() {
+	defaults := config.Default()
+
 	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", "data/historical", "Directory for storing historical data")
 	
 	serveCmd.Flags().StringP("port", "p", "8080", "Port to run the server on")
+	serveCmd.Flags().Int("backtest-workers", 2, "Maximum number of backtests to run concurrently")
 	
+	scrapeCmd.Flags().String("coin", "bitcoin", "CoinGecko coin ID to download ("+strings.Join(fetcher.SupportedCoins(), ", ")+"), or all")
+	scrapeCmd.Flags().String("intervals", "1d", "Comma-separated intervals to download (1m,5m,15m,1h,4h,1d)")
+	scrapeCmd.Flags().Int("days", 30, "Number of days of history to download")
+	scrapeCmd.Flags().Duration("request-delay", 1500*time.Millisecond, "Minimum delay between API requests")
+
+	backtestCmd.Flags().String("coin", "bitcoin", "Coin whose local candles to replay")
+	backtestCmd.Flags().String("interval", "1h", "Candle interval (1m,5m,15m,1h,4h,1d)")
+	backtestCmd.Flags().String("strategy", "ma_crossover", "Strategy to run (ma_crossover, rsi, rsi_divergence, vwap_reversion, donchian_breakout)")
+	backtestCmd.Flags().Float64("balance", 10000, "Initial balance")
+	backtestCmd.Flags().Int("fast", 0, "Fast MA period for ma_crossover (default 10)")
+	backtestCmd.Flags().Int("slow", 0, "Slow MA period for ma_crossover (default 30)")
+	backtestCmd.Flags().Int("rsi-period", 0, "RSI period for rsi and rsi_divergence (default 14)")
+	backtestCmd.Flags().Float64("oversold", 0, "RSI oversold level for rsi (default 30)")
+	backtestCmd.Flags().Float64("overbought", 0, "RSI overbought level for rsi (default 70)")
+	backtestCmd.Flags().Float64("taker-fee", defaults.TakerFee, "Commission rate per entry and exit (0.001 = 0.1%)")
+	backtestCmd.Flags().Float64("slippage-bps", defaults.SlippageBps, "Slippage against every fill in basis points")
+	backtestCmd.Flags().String("report", "", "Also write a report file (.html or .md)")
+	backtestCmd.Flags().String("export", "", "Also export the trade history (.csv or .json)")
+	backtestCmd.Flags().Bool("live", false, "Paper trade on Binance candles as they close instead of replaying local data, until Ctrl+C or SIGTERM")
+	backtestCmd.Flags().Duration("poll-interval", time.Duration(defaults.LiveData.PollInterval)*time.Second, "How often --live polls Binance for a new candle")
+	backtestCmd.Flags().String("config", "", "Run the strategy and balance from a YAML config (strategy.name ma, rsi or macd) on the engine without fees; --strategy, its parameters and --balance are ignored")
+
+	dataCmd.AddCommand(scrapeCmd)
+	dataCmd.AddCommand(validateCmd)
+
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(dataCmd)
+	rootCmd.AddCommand(backtestCmd)
+}
+
+// backtestParams lists the backtest settings for the report, leaving out
+// strategy parameters that fall back to their defaults
+func backtestParams(cmd *cobra.Command) []report.Param {
+	live, _ := cmd.Flags().GetBool("live")
+
+	var params []report.Param
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "report" || f.Name == "export" || f.Name == "help" || (f.Name == "poll-interval" && !live) {
+			return
+		}
+		if value := f.Value.String(); value == "0" || value == "" || value == "false" {
+			return
+		}
+		params = append(params, report.Param{Name: f.Name, Value: f.Value.String()})
+	})
+	return params
 }
 
 // Execute runs the root command
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/fetcher"
+)
+
+// throttle spaces out API requests shared across all downloads in one command
+type throttle struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// wait blocks until at least interval has passed since the previous request
+func (t *throttle) wait(ctx context.Context) error {
+	if !t.last.IsZero() {
+		if remaining := t.interval - time.Since(t.last); remaining > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(remaining):
+			}
+		}
+	}
+	t.last = time.Now()
+	return nil
+}
+
+// parseIntervals splits and validates a comma-separated interval list
+func parseIntervals(value string) ([]string, error) {
+	var intervals []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		interval := strings.TrimSpace(part)
+		if interval == "" || seen[interval] {
+			continue
+		}
+		if !fetcher.ValidateInterval(interval) {
+			return nil, fmt.Errorf("unsupported interval: %s", interval)
+		}
+		seen[interval] = true
+		intervals = append(intervals, interval)
+	}
+
+	if len(intervals) == 0 {
+		return nil, fmt.Errorf("at least one interval is required")
+	}
+
+	return intervals, nil
+}
+
+// scrapeInterval downloads one interval for a coin by paging Binance klines.
+// CoinGecko is not used because its OHLC granularity is chosen by the API
+// from the requested range rather than by interval.
+func scrapeInterval(ctx context.Context, limiter *throttle, coinID, interval string, days int) ([]engine.Candle, error) {
+	symbol := fetcher.BinanceSymbolFromCoinID(coinID)
+	if symbol == "" {
+		return nil, fmt.Errorf("no Binance symbol known for %s", coinID)
+	}
+
+	start := time.Now().AddDate(0, 0, -days)
+	candles, err := fetcher.NewBinanceFetcher().FetchCandlesRange(ctx, symbol, interval, start, limiter.wait)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candles returned for %s %s", symbol, interval)
+	}
+
+	return candles, nil
+}
+
+// writeCandlesCSV writes candles in the format read by LocalFileProvider
+func writeCandlesCSV(filename string, candles []engine.Candle) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"timestamp", "open", "high", "low", "close", "volume"}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, c := range candles {
+		record := []string{
+			c.Timestamp.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(c.Open, 'f', -1, 64),
+			strconv.FormatFloat(c.High, 'f', -1, 64),
+			strconv.FormatFloat(c.Low, 'f', -1, 64),
+			strconv.FormatFloat(c.Close, 'f', -1, 64),
+			strconv.FormatFloat(c.Volume, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write candle: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush %s: %w", filename, err)
+	}
+
+	return file.Close()
+}
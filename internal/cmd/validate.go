@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"candlecore/internal/exchange"
+)
+
+// maxListedProblems caps how many rejected rows and gaps are printed
+const maxListedProblems = 20
+
+// validateDataFile inspects a candle CSV file, prints a summary to w and
+// reports whether the file is free of problems
+func validateDataFile(w io.Writer, path string) (bool, error) {
+	r, err := exchange.InspectFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(w, "File:     %s\n", r.Path)
+	fmt.Fprintf(w, "Candles:  %d\n", r.Candles)
+	if r.Candles > 0 {
+		fmt.Fprintf(w, "Range:    %s to %s\n",
+			r.First.UTC().Format(time.RFC3339), r.Last.UTC().Format(time.RFC3339))
+	}
+	if r.Interval > 0 {
+		fmt.Fprintf(w, "Interval: %s\n", r.Interval)
+	}
+	fmt.Fprintf(w, "Gaps:     %d (%d missing candles)\n", len(r.Gaps), r.MissingCandles())
+	fmt.Fprintf(w, "Rejected: %d rows\n", len(r.Rejected))
+	if len(r.Unsorted) > 0 {
+		fmt.Fprintf(w, "Unsorted: %d rows\n", len(r.Unsorted))
+	}
+
+	for i, row := range r.Rejected {
+		if i == maxListedProblems {
+			fmt.Fprintf(w, "  ... and %d more rejected rows\n", len(r.Rejected)-i)
+			break
+		}
+		fmt.Fprintf(w, "  line %d: %v\n", row.Line, row.Err)
+	}
+	for i, line := range r.Unsorted {
+		if i == maxListedProblems {
+			fmt.Fprintf(w, "  ... and %d more unsorted rows\n", len(r.Unsorted)-i)
+			break
+		}
+		fmt.Fprintf(w, "  line %d: timestamp does not follow the previous row\n", line)
+	}
+	for i, gap := range r.Gaps {
+		if i == maxListedProblems {
+			fmt.Fprintf(w, "  ... and %d more gaps\n", len(r.Gaps)-i)
+			break
+		}
+		fmt.Fprintf(w, "  gap: %d candles missing from %s to %s\n", gap.Missing,
+			gap.Start.UTC().Format(time.RFC3339), gap.End.UTC().Format(time.RFC3339))
+	}
+
+	if r.OK() {
+		fmt.Fprintln(w, "OK")
+	} else {
+		fmt.Fprintln(w, "Problems found")
+	}
+	return r.OK(), nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDataFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bitcoin_1h.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	return path
+}
+
+func TestValidateDataFileGood(t *testing.T) {
+	path := writeDataFile(t, `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,105,95,102,10
+2024-01-01T01:00:00Z,102,106,101,104,12
+2024-01-01T02:00:00Z,104,107,103,106,9
+`)
+
+	var out bytes.Buffer
+	ok, err := validateDataFile(&out, path)
+	if err != nil {
+		t.Fatalf("validateDataFile() error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("validateDataFile() = false for a clean file:\n%s", out.String())
+	}
+	for _, want := range []string{"Candles:  3", "Interval: 1h0m0s", "Gaps:     0", "OK"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestValidateDataFileCorrupt(t *testing.T) {
+	path := writeDataFile(t, `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,105,95,102,10
+2024-01-01T01:00:00Z,102,99,101,104,12
+2024-01-01T02:00:00Z,104,107,103
+2024-01-01T03:00:00Z,104,107,103,106,9
+2024-01-01T06:00:00Z,106,108,104,107,11
+`)
+
+	var out bytes.Buffer
+	ok, err := validateDataFile(&out, path)
+	if err != nil {
+		t.Fatalf("validateDataFile() error: %v", err)
+	}
+	if ok {
+		t.Fatalf("validateDataFile() = true for a corrupt file:\n%s", out.String())
+	}
+	for _, want := range []string{
+		"Candles:  3",
+		"Rejected: 2 rows",
+		"line 3: invalid candle",
+		"line 4: expected 6 fields, got 4",
+		"Gaps:     2 (4 missing candles)",
+		"Problems found",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if _, err := validateDataFile(&out, filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
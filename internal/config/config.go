@@ -30,6 +30,14 @@ type Config struct {
 	// Logging
 	LogLevel string `yaml:"log_level"` // debug, info, warn, error
 
+	// Order audit log (JSONL, hash-chained); empty disables auditing
+	AuditLog string `yaml:"audit_log"`
+
+	// API authentication: the shared secret gating bot control, empty
+	// leaving it open, and whether it gates the read-only bot endpoints too
+	APISecret    string `yaml:"api_secret"`
+	APIAuthReads bool   `yaml:"api_auth_reads"`
+
 	// Strategy configuration
 	Strategy StrategyConfig `yaml:"strategy"`
 }
@@ -55,12 +63,89 @@ type LiveDataConfig struct {
 	PollInterval int    `yaml:"poll_interval"`  // Seconds between polling for new candles
 }
 
-// StrategyConfig holds strategy-specific parameters
+// Strategy kinds selectable with strategy.name
+const (
+	StrategyMA   = "ma"
+	StrategyRSI  = "rsi"
+	StrategyMACD = "macd"
+)
+
+// StrategyConfig holds strategy-specific parameters. The MA parameters stay
+// at the top level so existing configs keep working; rsi and macd read
+// their own blocks.
 type StrategyConfig struct {
-	Name         string  `yaml:"name"`
-	FastPeriod   int     `yaml:"fast_period"`
-	SlowPeriod   int     `yaml:"slow_period"`
-	PositionSize float64 `yaml:"position_size"` // How much to invest per trade
+	Name         string     `yaml:"name"` // ma (also simple_ma, sma_cross), rsi, macd
+	FastPeriod   int        `yaml:"fast_period"`
+	SlowPeriod   int        `yaml:"slow_period"`
+	PositionSize float64    `yaml:"position_size"` // How much to invest per trade
+	RSI          RSIConfig  `yaml:"rsi"`
+	MACD         MACDConfig `yaml:"macd"`
+}
+
+// RSIConfig holds RSI strategy parameters
+type RSIConfig struct {
+	Period     int     `yaml:"period"`
+	Oversold   float64 `yaml:"oversold"`
+	Overbought float64 `yaml:"overbought"`
+}
+
+// MACDConfig holds MACD strategy parameters
+type MACDConfig struct {
+	FastPeriod   int `yaml:"fast_period"`
+	SlowPeriod   int `yaml:"slow_period"`
+	SignalPeriod int `yaml:"signal_period"`
+}
+
+// Kind returns the canonical strategy kind for Name. Earlier MA names
+// (simple_ma, sma_cross) and an empty name map to ma.
+func (s StrategyConfig) Kind() (string, error) {
+	switch s.Name {
+	case "", StrategyMA, "simple_ma", "sma_cross", "ma_crossover":
+		return StrategyMA, nil
+	case StrategyRSI:
+		return StrategyRSI, nil
+	case StrategyMACD:
+		return StrategyMACD, nil
+	default:
+		return "", fmt.Errorf("unknown strategy: %s (use ma, rsi, or macd)", s.Name)
+	}
+}
+
+// Validate checks the parameters of the selected strategy only
+func (s StrategyConfig) Validate() error {
+	kind, err := s.Kind()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case StrategyMA:
+		if s.FastPeriod <= 0 || s.SlowPeriod <= 0 {
+			return fmt.Errorf("strategy periods must be positive")
+		}
+		if s.FastPeriod >= s.SlowPeriod {
+			return fmt.Errorf("fast_period must be less than slow_period")
+		}
+	case StrategyRSI:
+		if s.RSI.Period <= 0 {
+			return fmt.Errorf("rsi period must be positive")
+		}
+		if s.RSI.Oversold <= 0 || s.RSI.Overbought >= 100 {
+			return fmt.Errorf("rsi oversold and overbought must be between 0 and 100")
+		}
+		if s.RSI.Oversold >= s.RSI.Overbought {
+			return fmt.Errorf("rsi oversold must be less than overbought")
+		}
+	case StrategyMACD:
+		if s.MACD.FastPeriod <= 0 || s.MACD.SlowPeriod <= 0 || s.MACD.SignalPeriod <= 0 {
+			return fmt.Errorf("macd periods must be positive")
+		}
+		if s.MACD.FastPeriod >= s.MACD.SlowPeriod {
+			return fmt.Errorf("macd fast_period must be less than slow_period")
+		}
+	}
+
+	return nil
 }
 
 // Load reads configuration from a YAML file with environment variable overrides
@@ -73,8 +158,42 @@ func Load(path string) (*Config, error) {
 		fmt.Println("Loaded configuration from .env file")
 	}
 
-	// Set defaults
-	cfg := &Config{
+	cfg := Default()
+
+	// Try to load from file
+	if _, err := os.Stat(path); err == nil {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	// Override with environment variables
+	applyEnvOverrides(cfg)
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// FromEnv returns the defaults with CANDLECORE_* environment overrides
+// applied, for callers that read no config file. It does not validate.
+func FromEnv() *Config {
+	cfg := Default()
+	applyEnvOverrides(cfg)
+	return cfg
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
 		InitialBalance: 10000.0,
 		TakerFee:       0.001,
 		MakerFee:       0.0005,
@@ -104,30 +223,18 @@ func Load(path string) (*Config, error) {
 			FastPeriod:   10,
 			SlowPeriod:   30,
 			PositionSize: 1000.0,
+			RSI: RSIConfig{
+				Period:     14,
+				Oversold:   30,
+				Overbought: 70,
+			},
+			MACD: MACDConfig{
+				FastPeriod:   12,
+				SlowPeriod:   26,
+				SignalPeriod: 9,
+			},
 		},
 	}
-
-	// Try to load from file
-	if _, err := os.Stat(path); err == nil {
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
-		}
-
-		if err := yaml.Unmarshal(data, cfg); err != nil {
-			return nil, fmt.Errorf("failed to parse config file: %w", err)
-		}
-	}
-
-	// Override with environment variables
-	applyEnvOverrides(cfg)
-
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
-	return cfg, nil
 }
 
 // applyEnvOverrides applies environment variable overrides to the configuration
@@ -171,6 +278,21 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.LogLevel = val
 	}
 
+	if val := os.Getenv("CANDLECORE_AUDIT_LOG"); val != "" {
+		cfg.AuditLog = val
+	}
+
+	// API authentication
+	if val := os.Getenv("CANDLECORE_API_SECRET"); val != "" {
+		cfg.APISecret = val
+	}
+
+	if val := os.Getenv("CANDLECORE_API_AUTH_READS"); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			cfg.APIAuthReads = b
+		}
+	}
+
 	// Database settings
 	if val := os.Getenv("CANDLECORE_DB_ENABLED"); val != "" {
 		if b, err := strconv.ParseBool(val); err == nil {
@@ -279,12 +401,8 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("slippage_bps must be non-negative")
 	}
 
-	if c.Strategy.FastPeriod <= 0 || c.Strategy.SlowPeriod <= 0 {
-		return fmt.Errorf("strategy periods must be positive")
-	}
-
-	if c.Strategy.FastPeriod >= c.Strategy.SlowPeriod {
-		return fmt.Errorf("fast_period must be less than slow_period")
+	if err := c.Strategy.Validate(); err != nil {
+		return err
 	}
 
 	// Validate database config if enabled
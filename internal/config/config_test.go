@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadYAML writes a config file and loads it
+func loadYAML(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoadStrategyBlocks(t *testing.T) {
+	cfg, err := loadYAML(t, `
+strategy:
+  name: rsi
+  rsi:
+    period: 7
+    oversold: 20
+`)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	kind, _ := cfg.Strategy.Kind()
+	if kind != StrategyRSI {
+		t.Errorf("kind = %s, want rsi", kind)
+	}
+	if cfg.Strategy.RSI.Period != 7 || cfg.Strategy.RSI.Oversold != 20 {
+		t.Errorf("rsi = %+v, want period 7 oversold 20", cfg.Strategy.RSI)
+	}
+	if cfg.Strategy.RSI.Overbought != 70 {
+		t.Errorf("overbought = %.0f, want default 70", cfg.Strategy.RSI.Overbought)
+	}
+}
+
+func TestLoadLegacyStrategyConfig(t *testing.T) {
+	cfg, err := loadYAML(t, `
+strategy:
+  name: simple_ma
+  fast_period: 5
+  slow_period: 20
+  position_size: 500
+`)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	kind, _ := cfg.Strategy.Kind()
+	if kind != StrategyMA || cfg.Strategy.FastPeriod != 5 || cfg.Strategy.SlowPeriod != 20 {
+		t.Errorf("strategy = %s %+v, want ma 5/20", kind, cfg.Strategy)
+	}
+}
+
+func TestStrategyValidation(t *testing.T) {
+	valid := StrategyConfig{
+		FastPeriod: 10, SlowPeriod: 30,
+		RSI:  RSIConfig{Period: 14, Oversold: 30, Overbought: 70},
+		MACD: MACDConfig{FastPeriod: 12, SlowPeriod: 26, SignalPeriod: 9},
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(s *StrategyConfig)
+		wantErr bool
+	}{
+		{"ma defaults", func(s *StrategyConfig) { s.Name = "ma" }, false},
+		{"sma_cross alias", func(s *StrategyConfig) { s.Name = "sma_cross" }, false},
+		{"ma inverted periods", func(s *StrategyConfig) { s.Name = "ma"; s.FastPeriod = 40 }, true},
+		{"rsi defaults", func(s *StrategyConfig) { s.Name = "rsi" }, false},
+		{"rsi ignores ma periods", func(s *StrategyConfig) { s.Name = "rsi"; s.FastPeriod = 40 }, false},
+		{"rsi oversold above overbought", func(s *StrategyConfig) { s.Name = "rsi"; s.RSI.Oversold = 75 }, true},
+		{"rsi zero period", func(s *StrategyConfig) { s.Name = "rsi"; s.RSI.Period = 0 }, true},
+		{"rsi overbought at 100", func(s *StrategyConfig) { s.Name = "rsi"; s.RSI.Overbought = 100 }, true},
+		{"macd defaults", func(s *StrategyConfig) { s.Name = "macd" }, false},
+		{"macd fast above slow", func(s *StrategyConfig) { s.Name = "macd"; s.MACD.FastPeriod = 30 }, true},
+		{"macd zero signal", func(s *StrategyConfig) { s.Name = "macd"; s.MACD.SignalPeriod = 0 }, true},
+		{"unknown strategy", func(s *StrategyConfig) { s.Name = "bollinger" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid
+			tt.mutate(&s)
+			err := s.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuditLogSetting(t *testing.T) {
+	cfg, err := loadYAML(t, "audit_log: orders.jsonl\n")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.AuditLog != "orders.jsonl" {
+		t.Errorf("AuditLog = %q, want orders.jsonl", cfg.AuditLog)
+	}
+
+	t.Setenv("CANDLECORE_AUDIT_LOG", "env.jsonl")
+	if got := FromEnv().AuditLog; got != "env.jsonl" {
+		t.Errorf("FromEnv().AuditLog = %q, want env.jsonl", got)
+	}
+}
@@ -3,6 +3,9 @@ package engine
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"time"
 
 	"candlecore/internal/logger"
 )
@@ -47,25 +50,238 @@ type StateStore interface {
 	LoadState(broker Broker) error
 }
 
+// Observer is notified of engine activity, e.g. for custom analytics or
+// external alerts. Callbacks run synchronously on the engine goroutine, so
+// slow work should be handed off.
+type Observer interface {
+	// OnSignal is called with every strategy signal, including HOLD,
+	// before it is executed
+	OnSignal(signal Signal, candle Candle)
+
+	// OnOrderFilled is called for every filled order, whether it came from
+	// a signal, a stop, an exit rule or a close on finish
+	OnOrderFilled(order *Order)
+
+	// OnTrade is called for every trade an order closes, after its
+	// excursion statistics are stamped
+	OnTrade(trade *Trade)
+}
+
+// MarkPriceFunc selects the price used to value open positions for a candle
+type MarkPriceFunc func(candle Candle) float64
+
+// MarkAtClose values positions at the candle close (default)
+func MarkAtClose(candle Candle) float64 { return candle.Close }
+
+// MarkAtLow values positions at the candle low, the most conservative
+// reference for long positions
+func MarkAtLow(candle Candle) float64 { return candle.Low }
+
+// MarkAtHigh values positions at the candle high
+func MarkAtHigh(candle Candle) float64 { return candle.High }
+
+// PyramidConfig controls scaling into an existing position
+type PyramidConfig struct {
+	// MaxEntries is the maximum number of buy entries per position,
+	// including the initial one. Zero means unlimited.
+	MaxEntries int
+
+	// AddScale sizes each add-on relative to the previous entry:
+	// 1 keeps every entry the same size, 0.5 halves each add-on.
+	// Zero is treated as 1.
+	AddScale float64
+}
+
+// RiskLimits caps how much of the account buy signals may commit. Buys
+// that would breach a limit are rejected and logged rather than failing
+// the run.
+type RiskLimits struct {
+	// MaxOpenPositions is the most symbols that may hold a position at once.
+	// Adding to an already open position does not count. Zero means unlimited.
+	MaxOpenPositions int
+
+	// MaxExposurePct is the most total position value, after the buy, as a
+	// percent of account equity (e.g. 50 for 50%). Zero means unlimited.
+	MaxExposurePct float64
+}
+
 // Engine is the main trading engine that orchestrates everything
 type Engine struct {
 	broker Broker
 	strategy Strategy
 	store  StateStore
 	logger logger.Logger
+
+	// Notified of signals, fills and closed trades
+	observers []Observer
+
+	// Minimum unrealized profit required before strategy exits are honored
+	minProfitAbs float64
+	minProfitPct float64
+
+	// Price reference for position valuation, independent of execution price
+	markPrice MarkPriceFunc
+
+	// Price that stop-loss and take-profit levels are compared against;
+	// nil checks the full candle range
+	stopTrigger MarkPriceFunc
+
+	// Scale-in limits and the number of filled entries per open position
+	pyramid PyramidConfig
+	entries map[string]int
+
+	// Account-wide caps on open positions and exposure
+	riskLimits RiskLimits
+
+	// Optional exit predicate evaluated for open positions on every candle
+	exitRule ExitRule
+
+	// Stop-loss and take-profit levels per open position
+	stops map[string]stopLevels
+
+	// Price extremes and initial stop per open position, stamped on trades
+	excursions map[string]*excursion
+
+	// Longest a position may stay open before it is closed; 0 disables
+	maxHold time.Duration
+
+	// Candles between periodic state saves; 0 saves only when a run ends
+	saveInterval int
+
+	// Close every open position when a run finishes or is stopped, at the
+	// last candle seen for its symbol
+	closeOnFinish bool
+	lastCandles   map[string]Candle
+
+	// Symbol given to candles that carry none, so strategy signals, broker
+	// positions and price updates agree on one key
+	symbol string
+}
+
+// stopLevels holds the protective exit prices for one position
+type stopLevels struct {
+	stopLoss   float64
+	takeProfit float64
+}
+
+// excursion tracks the price range seen while a position is open.
+// prevLow and prevHigh hold the range before the current candle so a stop
+// fill can cap the exit candle's extreme at the fill price.
+type excursion struct {
+	low         float64
+	high        float64
+	prevLow     float64
+	prevHigh    float64
+	initialStop float64
 }
 
-// New creates a new trading engine
-func New(broker Broker, strategy Strategy, store StateStore, log logger.Logger) *Engine {
+// defaultSaveInterval is how many candles pass between periodic state saves
+const defaultSaveInterval = 10
+
+// New creates a new trading engine. Observers, if any, are notified in the
+// order given.
+func New(broker Broker, strategy Strategy, store StateStore, log logger.Logger, observers ...Observer) *Engine {
 	return &Engine{
-		broker:   broker,
-		strategy: strategy,
-		store:    store,
-		logger:   log,
+		broker:       broker,
+		strategy:     strategy,
+		store:        store,
+		logger:       log,
+		observers:    observers,
+		markPrice:    MarkAtClose,
+		entries:      make(map[string]int),
+		stops:        make(map[string]stopLevels),
+		excursions:   make(map[string]*excursion),
+		saveInterval: defaultSaveInterval,
+		lastCandles:  make(map[string]Candle),
 	}
 }
 
-// Run executes the backtest/paper trading loop
+// SetSymbol sets the symbol of single-symbol runs. Candles without a symbol
+// are tagged with it before valuation and before the strategy sees them.
+// Empty leaves candles untagged, which prices every open position.
+func (e *Engine) SetSymbol(symbol string) {
+	e.symbol = symbol
+}
+
+// SetSaveInterval sets how many candles pass between periodic state saves.
+// Zero disables periodic saves; state is still saved when a run ends.
+func (e *Engine) SetSaveInterval(candles int) {
+	if candles < 0 {
+		candles = 0
+	}
+	e.saveInterval = candles
+}
+
+// SetPyramiding configures how many times, and at what size, buy signals
+// may add to an already open position. Buy signals beyond MaxEntries are
+// ignored.
+func (e *Engine) SetPyramiding(cfg PyramidConfig) {
+	e.pyramid = cfg
+}
+
+// SetRiskLimits sets the account-wide limits enforced on buy signals
+func (e *Engine) SetRiskLimits(limits RiskLimits) {
+	e.riskLimits = limits
+}
+
+// SetMaxHoldDuration closes any position that has been open longer than d,
+// measured from Position.OpenedAt to the candle timestamp so backtests stay
+// deterministic. The exit fills at the candle close ahead of the exit rule
+// and strategy, and is not subject to the minimum-profit guard. Zero
+// disables it.
+func (e *Engine) SetMaxHoldDuration(d time.Duration) {
+	e.maxHold = d
+}
+
+// SetCloseOnFinish closes every open position when Run or RunLive returns,
+// whether the candles ran out or ctx was cancelled, so a run ends flat with
+// its P&L fully realized. Each position is sold at the close of the last
+// candle seen for its symbol, before state is saved.
+func (e *Engine) SetCloseOnFinish(enabled bool) {
+	e.closeOnFinish = enabled
+}
+
+// SetExitRule pairs the strategy with an independent exit rule. The rule
+// sees every candle and can close any open position before the strategy
+// is consulted. Exits it triggers are subject to the minimum-profit guard
+// like strategy SELL signals. A nil rule disables it.
+func (e *Engine) SetExitRule(rule ExitRule) {
+	e.exitRule = rule
+}
+
+// SetMarkPriceFunc sets how open positions are valued on each candle.
+// Orders still execute at the candle close; only valuation changes.
+// Stop-loss and take-profit levels are checked against the full candle
+// range unless SetStopTrigger says otherwise. A nil function restores the
+// default close-price marking.
+func (e *Engine) SetMarkPriceFunc(fn MarkPriceFunc) {
+	if fn == nil {
+		fn = MarkAtClose
+	}
+	e.markPrice = fn
+}
+
+// SetStopTrigger makes stop-loss and take-profit levels trigger on a single
+// reference price instead of the candle range: a stop fires when fn(candle)
+// is at or below it, a take-profit when fn(candle) is at or above it, and the
+// exit fills at that price. Passing the mark price function keeps stops in
+// line with how positions are valued. A nil function restores intrabar
+// triggering on the candle low and high.
+func (e *Engine) SetStopTrigger(fn MarkPriceFunc) {
+	e.stopTrigger = fn
+}
+
+// SetMinProfitToExit suppresses strategy SELL signals until the open position
+// has reached the given unrealized profit. absolute is in quote currency and
+// percent is relative to the position's entry value (e.g. 2 for 2%).
+// A zero value disables that threshold; when both are set, both must be met.
+func (e *Engine) SetMinProfitToExit(absolute, percent float64) {
+	e.minProfitAbs = absolute
+	e.minProfitPct = percent
+}
+
+// Run executes the backtest/paper trading loop. State is saved every
+// save interval and once more when all candles have been processed.
 func (e *Engine) Run(ctx context.Context, candles []Candle) error {
 	e.logger.Info("Engine starting",
 		"strategy", e.strategy.Name(),
@@ -77,49 +293,360 @@ func (e *Engine) Run(ctx context.Context, candles []Candle) error {
 		select {
 		case <-ctx.Done():
 			e.logger.Info("Engine stopped by context", "processed_candles", i)
+			e.finish()
 			return ctx.Err()
 		default:
 		}
 
-		// Update market price for position valuation
-		e.broker.UpdateMarketPrice("BTC/USD", candle.Close)
+		e.processCandle(i, candle)
+	}
+
+	e.finish()
 
-		// Get current account state
-		account := e.broker.GetAccount()
+	e.logger.Info("Engine completed successfully", "total_candles", len(candles))
+	return nil
+}
+
+// processCandle runs the per-candle pipeline shared by Run and RunLive:
+// valuation, stops, exit rule, strategy signal, execution and periodic
+// state saves. i is the candle's position in the stream.
+func (e *Engine) processCandle(i int, candle Candle) {
+	if candle.Symbol == "" {
+		candle.Symbol = e.symbol
+	}
+	e.lastCandles[candle.Symbol] = candle
+
+	// Update market price for position valuation
+	e.updateMarketPrices(candle)
 
-		// Log current state
-		e.logger.Debug("Processing candle",
-			"index", i,
-			"timestamp", candle.Timestamp,
-			"close", candle.Close,
-			"balance", account.Balance,
-			"equity", account.Equity,
+	// Stops trigger intrabar, ahead of any strategy or exit rule decision
+	e.checkStops(candle)
+
+	if e.maxHold > 0 {
+		e.checkMaxHold(candle)
+	}
+
+	// Let the exit rule close positions before the strategy runs
+	if e.exitRule != nil {
+		e.exitRule.OnCandle(candle)
+		e.applyExitRule(candle)
+	}
+
+	// Get current account state
+	account := e.broker.GetAccount()
+
+	// Log current state
+	e.logger.Debug("Processing candle",
+		"index", i,
+		"timestamp", candle.Timestamp,
+		"close", candle.Close,
+		"balance", account.Balance,
+		"equity", account.Equity,
+	)
+
+	// Get strategy signal
+	signal := e.strategy.OnCandle(candle, account)
+	for _, o := range e.observers {
+		o.OnSignal(signal, candle)
+	}
+
+	// Execute signal
+	if err := e.executeSignal(signal, candle); err != nil {
+		e.logger.Error("Failed to execute signal",
+			"error", err,
+			"signal", signal.Action,
+			"candle_index", i,
 		)
+		// Continue processing rather than failing completely
+		return
+	}
+
+	// Periodically save state
+	if e.saveInterval > 0 && i > 0 && i%e.saveInterval == 0 {
+		e.saveState()
+	}
+}
+
+// RunLive runs the same per-candle pipeline as Run on candles as they
+// arrive, e.g. from fetcher.BinanceFetcher.StreamCandles. Errors received on
+// errCh are logged and the loop keeps going. It returns when ctx is
+// cancelled or candleCh is closed, saving state on the way out.
+func (e *Engine) RunLive(ctx context.Context, candleCh <-chan Candle, errCh <-chan error) error {
+	e.logger.Info("Engine starting live", "strategy", e.strategy.Name())
+
+	processed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Engine stopped by context", "processed_candles", processed)
+			e.finish()
+			return ctx.Err()
+		case err, ok := <-errCh:
+			if !ok {
+				// A nil channel blocks, leaving only candles and ctx
+				errCh = nil
+				continue
+			}
+			e.logger.Warn("Live candle feed error", "error", err)
+		case candle, ok := <-candleCh:
+			if !ok {
+				e.logger.Info("Live candle feed closed", "processed_candles", processed)
+				e.finish()
+				return nil
+			}
+			e.processCandle(processed, candle)
+			processed++
+		}
+	}
+}
+
+// finish closes open positions when close-on-finish is enabled, then
+// saves state
+func (e *Engine) finish() {
+	if e.closeOnFinish {
+		e.closeAllPositions()
+	}
+	e.saveState()
+}
+
+// closeAllPositions sells every open position at the close of the last
+// candle seen for its symbol. Untagged candles price every symbol.
+func (e *Engine) closeAllPositions() {
+	const reason = "close on finish"
+
+	for _, position := range e.broker.GetAccount().Positions {
+		if position.Quantity == 0 {
+			continue
+		}
+
+		candle, ok := e.lastCandles[position.Symbol]
+		if !ok {
+			candle, ok = e.lastCandles[""]
+		}
+		if !ok {
+			e.logger.Warn("No candle to close position at", "symbol", position.Symbol)
+			continue
+		}
 
-		// Get strategy signal
-		signal := e.strategy.OnCandle(candle, account)
+		e.logger.Info("Closing position on finish",
+			"symbol", position.Symbol,
+			"quantity", position.Quantity,
+			"price", candle.Close,
+		)
 
-		// Execute signal
-		if err := e.executeSignal(signal, candle); err != nil {
-			e.logger.Error("Failed to execute signal",
+		if err := e.placeSell(position.Symbol, position.Quantity, candle.Close, candle, reason); err != nil {
+			e.logger.Error("Failed to close position on finish",
 				"error", err,
-				"signal", signal.Action,
-				"candle_index", i,
+				"symbol", position.Symbol,
 			)
-			// Continue processing rather than failing completely
+		}
+	}
+}
+
+// saveState persists broker state, logging rather than failing on error
+func (e *Engine) saveState() {
+	if err := e.store.SaveState(e.broker); err != nil {
+		e.logger.Warn("Failed to save state", "error", err)
+	}
+}
+
+// RunMulti runs a multi-asset backtest. Candles from every symbol are
+// merged into one time-ordered stream, each tagged with its symbol, and
+// fed through Run. The strategy and any exit rule receive every candle and
+// should key their state by Candle.Symbol; prices, stops, and exits only
+// touch the position for the candle's own symbol.
+func (e *Engine) RunMulti(ctx context.Context, candlesBySymbol map[string][]Candle) error {
+	return e.Run(ctx, MergeCandles(candlesBySymbol))
+}
+
+// MergeCandles tags candles with their symbol and merges them into a single
+// stream ordered by time, breaking ties by symbol name
+func MergeCandles(candlesBySymbol map[string][]Candle) []Candle {
+	total := 0
+	for _, candles := range candlesBySymbol {
+		total += len(candles)
+	}
+
+	merged := make([]Candle, 0, total)
+	for symbol, candles := range candlesBySymbol {
+		for _, c := range candles {
+			c.Symbol = symbol
+			merged = append(merged, c)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Timestamp.Equal(merged[j].Timestamp) {
+			return merged[i].Symbol < merged[j].Symbol
+		}
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+
+	return merged
+}
+
+// appliesTo reports whether a candle prices the given symbol. Candles
+// without a symbol come from single-symbol runs and apply to every position.
+func appliesTo(candle Candle, symbol string) bool {
+	return candle.Symbol == "" || candle.Symbol == symbol
+}
+
+// updateMarketPrices marks open positions at the candle's mark price.
+// Symbols come from the broker's positions, so valuation follows whatever
+// symbol the strategy traded rather than a fixed pair.
+func (e *Engine) updateMarketPrices(candle Candle) {
+	price := e.markPrice(candle)
+	for _, position := range e.broker.GetAccount().Positions {
+		if !appliesTo(candle, position.Symbol) {
+			continue
+		}
+		e.broker.UpdateMarketPrice(position.Symbol, price)
+
+		if ex, ok := e.excursions[position.Symbol]; ok {
+			ex.prevLow, ex.prevHigh = ex.low, ex.high
+			ex.low = math.Min(ex.low, candle.Low)
+			ex.high = math.Max(ex.high, candle.High)
+		}
+	}
+}
+
+// checkStops closes positions whose stop-loss or take-profit was touched by
+// the candle range. A gap through the level fills at the open. When both
+// levels fall inside one candle the stop is assumed to have hit first.
+// With a stop trigger set, levels are compared against the trigger price
+// and exits fill there. Symbols are checked in sorted order so fills are
+// deterministic across runs.
+func (e *Engine) checkStops(candle Candle) {
+	low, high := candle.Low, candle.High
+	if e.stopTrigger != nil {
+		low = e.stopTrigger(candle)
+		high = low
+	}
+
+	symbols := make([]string, 0, len(e.stops))
+	for symbol := range e.stops {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		levels := e.stops[symbol]
+		if !appliesTo(candle, symbol) {
 			continue
 		}
 
-		// Periodically save state (every 10 candles for example)
-		if i%10 == 0 && i > 0 {
-			if err := e.store.SaveState(e.broker); err != nil {
-				e.logger.Warn("Failed to save state", "error", err)
+		position := e.broker.GetPosition(symbol)
+		if position == nil || position.Quantity == 0 {
+			delete(e.stops, symbol)
+			continue
+		}
+
+		var price float64
+		var reason string
+		ex := e.excursions[symbol]
+		switch {
+		case levels.stopLoss > 0 && low <= levels.stopLoss:
+			price = math.Min(candle.Open, levels.stopLoss)
+			if e.stopTrigger != nil {
+				price = low
+			}
+			reason = "stop-loss hit"
+			// Prices below the fill were never held
+			if ex != nil {
+				ex.low = math.Min(ex.prevLow, price)
 			}
+		case levels.takeProfit > 0 && high >= levels.takeProfit:
+			price = math.Max(candle.Open, levels.takeProfit)
+			if e.stopTrigger != nil {
+				price = high
+			}
+			reason = "take-profit hit"
+			if ex != nil {
+				ex.high = math.Max(ex.prevHigh, price)
+			}
+		default:
+			continue
+		}
+
+		e.logger.Info("Executing protective exit",
+			"symbol", symbol,
+			"reason", reason,
+			"price", price,
+		)
+
+		if err := e.placeSell(symbol, position.Quantity, price, candle, reason); err != nil {
+			e.logger.Error("Failed to execute protective exit",
+				"error", err,
+				"symbol", symbol,
+				"reason", reason,
+			)
+			// The position stays open, so the whole candle range counts
+			if ex != nil {
+				ex.low = math.Min(ex.low, candle.Low)
+				ex.high = math.Max(ex.high, candle.High)
+			}
+			continue
 		}
+		delete(e.stops, symbol)
 	}
+}
 
-	e.logger.Info("Engine completed successfully", "total_candles", len(candles))
-	return nil
+// checkMaxHold closes positions held longer than the maximum hold duration
+func (e *Engine) checkMaxHold(candle Candle) {
+	const reason = "max hold duration exceeded"
+
+	for _, position := range e.broker.GetAccount().Positions {
+		if position.Quantity == 0 || !appliesTo(candle, position.Symbol) {
+			continue
+		}
+
+		held := candle.Timestamp.Sub(position.OpenedAt)
+		if held <= e.maxHold {
+			continue
+		}
+
+		e.logger.Info("Executing time-based exit",
+			"symbol", position.Symbol,
+			"held", held,
+			"max_hold", e.maxHold,
+			"price", candle.Close,
+		)
+
+		if err := e.placeSell(position.Symbol, position.Quantity, candle.Close, candle, reason); err != nil {
+			e.logger.Error("Failed to execute time-based exit",
+				"error", err,
+				"symbol", position.Symbol,
+			)
+		}
+	}
+}
+
+// applyExitRule closes every open position the exit rule flags
+func (e *Engine) applyExitRule(candle Candle) {
+	for _, position := range e.broker.GetAccount().Positions {
+		if position.Quantity == 0 || !appliesTo(candle, position.Symbol) {
+			continue
+		}
+
+		exit, reason := e.exitRule.ShouldExit(position, candle)
+		if !exit {
+			continue
+		}
+
+		signal := Signal{
+			Action:   SignalActionSell,
+			Symbol:   position.Symbol,
+			Quantity: position.Quantity,
+			Reason:   fmt.Sprintf("%s: %s", e.exitRule.Name(), reason),
+		}
+		if err := e.executeSell(signal, candle); err != nil {
+			e.logger.Error("Failed to execute exit rule",
+				"error", err,
+				"rule", e.exitRule.Name(),
+				"symbol", position.Symbol,
+			)
+		}
+	}
 }
 
 // executeSignal converts a strategy signal into broker orders
@@ -139,10 +666,44 @@ func (e *Engine) executeSignal(signal Signal, candle Candle) error {
 
 // executeBuy executes a buy signal
 func (e *Engine) executeBuy(signal Signal, candle Candle) error {
+	// Entries and stops are tracked per open position; a flat symbol starts over
+	position := e.broker.GetPosition(signal.Symbol)
+	if position == nil || position.Quantity == 0 {
+		e.entries[signal.Symbol] = 0
+		delete(e.stops, signal.Symbol)
+		delete(e.excursions, signal.Symbol)
+	}
+
+	entries := e.entries[signal.Symbol]
+	if e.pyramid.MaxEntries > 0 && entries >= e.pyramid.MaxEntries {
+		e.logger.Debug("Ignoring BUY signal - maximum pyramid entries reached",
+			"symbol", signal.Symbol,
+			"entries", entries,
+			"max_entries", e.pyramid.MaxEntries,
+		)
+		return nil
+	}
+
+	quantity := signal.Quantity
+	if entries > 0 && e.pyramid.AddScale > 0 {
+		quantity *= math.Pow(e.pyramid.AddScale, float64(entries))
+	}
+
+	if reason := e.riskLimitBreach(signal.Symbol, quantity*candle.Close); reason != "" {
+		e.logger.Warn("Rejecting BUY signal - risk limit exceeded",
+			"symbol", signal.Symbol,
+			"quantity", quantity,
+			"price", candle.Close,
+			"limit", reason,
+		)
+		return nil
+	}
+
 	e.logger.Info("Executing BUY signal",
 		"symbol", signal.Symbol,
-		"quantity", signal.Quantity,
+		"quantity", quantity,
 		"price", candle.Close,
+		"entry", entries+1,
 		"reason", signal.Reason,
 	)
 
@@ -151,12 +712,90 @@ func (e *Engine) executeBuy(signal Signal, candle Candle) error {
 		Side:      OrderSideBuy,
 		Type:      OrderTypeMarket,
 		Symbol:    signal.Symbol,
-		Quantity:  signal.Quantity,
+		Quantity:  quantity,
 		Price:     candle.Close, // Market order uses current price
 		Status:    OrderStatusPending,
+		Reason:    signal.Reason,
+	}
+
+	if err := e.broker.PlaceOrder(order); err != nil {
+		return err
+	}
+	e.notifyFilled(order)
+
+	if order.Status == OrderStatusFilled {
+		e.entries[signal.Symbol] = entries + 1
+		e.updateStops(signal)
+		e.trackEntry(signal, order.FilledPrice)
 	}
 
-	return e.broker.PlaceOrder(order)
+	return nil
+}
+
+// riskLimitBreach returns which risk limit a buy of the given value in
+// symbol would exceed, or "" when it is within every limit
+func (e *Engine) riskLimitBreach(symbol string, value float64) string {
+	if e.riskLimits.MaxOpenPositions <= 0 && e.riskLimits.MaxExposurePct <= 0 {
+		return ""
+	}
+
+	account := e.broker.GetAccount()
+	open := 0
+	adding := false
+	exposure := value
+	for _, position := range account.Positions {
+		if position.Quantity == 0 {
+			continue
+		}
+		open++
+		if position.Symbol == symbol {
+			adding = true
+		}
+		exposure += position.Quantity * position.CurrentPrice
+	}
+
+	if e.riskLimits.MaxOpenPositions > 0 && !adding && open >= e.riskLimits.MaxOpenPositions {
+		return fmt.Sprintf("max open positions (%d)", e.riskLimits.MaxOpenPositions)
+	}
+	if e.riskLimits.MaxExposurePct > 0 &&
+		(account.Equity <= 0 || exposure/account.Equity*100 > e.riskLimits.MaxExposurePct) {
+		return fmt.Sprintf("max exposure (%.2f%%)", e.riskLimits.MaxExposurePct)
+	}
+
+	return ""
+}
+
+// trackEntry starts or widens the excursion range of a filled BUY. The
+// first entry's stop-loss is kept as the initial risk for R-multiples.
+func (e *Engine) trackEntry(signal Signal, price float64) {
+	if ex, ok := e.excursions[signal.Symbol]; ok {
+		ex.low = math.Min(ex.low, price)
+		ex.high = math.Max(ex.high, price)
+		return
+	}
+	e.excursions[signal.Symbol] = &excursion{
+		low:         price,
+		high:        price,
+		prevLow:     price,
+		prevHigh:    price,
+		initialStop: signal.StopLoss,
+	}
+}
+
+// updateStops records the protective levels carried by a filled BUY
+func (e *Engine) updateStops(signal Signal) {
+	if signal.StopLoss <= 0 && signal.TakeProfit <= 0 {
+		return
+	}
+
+	levels := e.stops[signal.Symbol]
+	if signal.StopLoss > 0 {
+		levels.stopLoss = signal.StopLoss
+	}
+	if signal.TakeProfit > 0 {
+		levels.takeProfit = signal.TakeProfit
+	}
+	e.stops[signal.Symbol] = levels
 }
 
 // executeSell executes a sell signal
@@ -168,6 +807,16 @@ func (e *Engine) executeSell(signal Signal, candle Candle) error {
 		return nil
 	}
 
+	if !e.minProfitReached(position) {
+		e.logger.Debug("Suppressing SELL signal - minimum profit to exit not reached",
+			"symbol", signal.Symbol,
+			"unrealized_pnl", position.UnrealizedPnL,
+			"min_profit", e.minProfitAbs,
+			"min_profit_pct", e.minProfitPct,
+		)
+		return nil
+	}
+
 	e.logger.Info("Executing SELL signal",
 		"symbol", signal.Symbol,
 		"quantity", signal.Quantity,
@@ -175,15 +824,109 @@ func (e *Engine) executeSell(signal Signal, candle Candle) error {
 		"reason", signal.Reason,
 	)
 
+	return e.placeSell(signal.Symbol, signal.Quantity, candle.Close, candle, signal.Reason)
+}
+
+// placeSell submits a market sell at the given price and stamps excursion
+// statistics on the trades it closes
+func (e *Engine) placeSell(symbol string, quantity, price float64, candle Candle, reason string) error {
+	closed := len(e.broker.GetAccount().TradeHistory)
+
 	order := &Order{
 		Timestamp: candle.Timestamp,
 		Side:      OrderSideSell,
 		Type:      OrderTypeMarket,
-		Symbol:    signal.Symbol,
-		Quantity:  signal.Quantity,
-		Price:     candle.Close,
+		Symbol:    symbol,
+		Quantity:  quantity,
+		Price:     price,
 		Status:    OrderStatusPending,
+		Reason:    reason,
+	}
+
+	if err := e.broker.PlaceOrder(order); err != nil {
+		return err
+	}
+
+	e.stampExcursions(symbol, closed)
+	e.notifyFilled(order)
+	e.notifyTrades(closed)
+	return nil
+}
+
+// notifyFilled passes a filled order to every observer
+func (e *Engine) notifyFilled(order *Order) {
+	if order.Status != OrderStatusFilled {
+		return
+	}
+	for _, o := range e.observers {
+		o.OnOrderFilled(order)
+	}
+}
+
+// notifyTrades passes the trades past index closed of the trade history to
+// every observer
+func (e *Engine) notifyTrades(closed int) {
+	if len(e.observers) == 0 {
+		return
+	}
+
+	history := e.broker.GetAccount().TradeHistory
+	if closed > len(history) {
+		closed = len(history)
+	}
+	for _, trade := range history[closed:] {
+		for _, o := range e.observers {
+			o.OnTrade(trade)
+		}
+	}
+}
+
+// stampExcursions sets MAE, MFE and R-multiple on the symbol's trades past
+// index closed of the trade history, using candle lows and highs from entry
+// through the exit candle. A stop fill caps the exit candle's extreme at the
+// fill price. Tracking ends once the position is flat.
+func (e *Engine) stampExcursions(symbol string, closed int) {
+	ex, ok := e.excursions[symbol]
+	if !ok {
+		return
+	}
+
+	history := e.broker.GetAccount().TradeHistory
+	if closed > len(history) {
+		closed = len(history)
+	}
+	for _, trade := range history[closed:] {
+		if trade.Symbol != symbol {
+			continue
+		}
+		trade.MAE = math.Max(0, trade.EntryPrice-ex.low) * trade.Quantity
+		trade.MFE = math.Max(0, ex.high-trade.EntryPrice) * trade.Quantity
+		if risk := (trade.EntryPrice - ex.initialStop) * trade.Quantity; ex.initialStop > 0 && risk > 0 {
+			trade.RMultiple = trade.NetPnL / risk
+		}
+	}
+
+	if position := e.broker.GetPosition(symbol); position == nil || position.Quantity == 0 {
+		delete(e.excursions, symbol)
+	}
+}
+
+// minProfitReached reports whether a position satisfies the configured
+// minimum-profit-to-exit thresholds
+func (e *Engine) minProfitReached(position *Position) bool {
+	if e.minProfitAbs > 0 && position.UnrealizedPnL < e.minProfitAbs {
+		return false
+	}
+
+	if e.minProfitPct > 0 {
+		entryValue := position.EntryPrice * position.Quantity
+		if entryValue <= 0 {
+			return false
+		}
+		if position.UnrealizedPnL/entryValue*100 < e.minProfitPct {
+			return false
+		}
 	}
 
-	return e.broker.PlaceOrder(order)
+	return true
 }
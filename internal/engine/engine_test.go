@@ -0,0 +1,1045 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"candlecore/internal/logger"
+)
+
+// testBroker is a minimal in-memory broker that fills market orders at the
+// order price without fees, for exercising engine control flow.
+type testBroker struct {
+	balance   float64
+	positions map[string]*Position
+	orders    []*Order
+	trades    []*Trade
+}
+
+func newTestBroker(balance float64) *testBroker {
+	return &testBroker{
+		balance:   balance,
+		positions: make(map[string]*Position),
+	}
+}
+
+func (b *testBroker) GetAccount() *Account {
+	account := &Account{
+		Balance:      b.balance,
+		Equity:       b.balance,
+		TradeHistory: b.trades,
+	}
+	for _, p := range b.positions {
+		account.Positions = append(account.Positions, p)
+		account.Equity += p.CurrentPrice * p.Quantity
+	}
+	return account
+}
+
+func (b *testBroker) PlaceOrder(order *Order) error {
+	b.orders = append(b.orders, order)
+	order.Status = OrderStatusFilled
+	order.FilledPrice = order.Price
+	order.FilledQty = order.Quantity
+
+	switch order.Side {
+	case OrderSideBuy:
+		b.balance -= order.Price * order.Quantity
+		if p, ok := b.positions[order.Symbol]; ok {
+			total := p.Quantity + order.Quantity
+			p.EntryPrice = (p.EntryPrice*p.Quantity + order.Price*order.Quantity) / total
+			p.Quantity = total
+		} else {
+			b.positions[order.Symbol] = &Position{
+				Symbol:       order.Symbol,
+				Side:         OrderSideBuy,
+				EntryPrice:   order.Price,
+				Quantity:     order.Quantity,
+				CurrentPrice: order.Price,
+				OpenedAt:     order.Timestamp,
+			}
+		}
+	case OrderSideSell:
+		p, ok := b.positions[order.Symbol]
+		if !ok {
+			return nil
+		}
+		qty := order.Quantity
+		if qty <= 0 || qty > p.Quantity {
+			qty = p.Quantity
+		}
+		b.balance += order.Price * qty
+		b.trades = append(b.trades, &Trade{
+			Symbol:     order.Symbol,
+			Side:       OrderSideBuy,
+			EntryPrice: p.EntryPrice,
+			ExitPrice:  order.Price,
+			Quantity:   qty,
+			PnL:        (order.Price - p.EntryPrice) * qty,
+			NetPnL:     (order.Price - p.EntryPrice) * qty,
+			OpenedAt:   p.OpenedAt,
+			ClosedAt:   order.Timestamp,
+			Reason:     order.Reason,
+		})
+		p.Quantity -= qty
+		if p.Quantity <= 0 {
+			delete(b.positions, order.Symbol)
+		}
+	}
+	return nil
+}
+
+func (b *testBroker) CancelOrder(orderID string) error { return nil }
+
+func (b *testBroker) UpdateMarketPrice(symbol string, price float64) {
+	if p, ok := b.positions[symbol]; ok {
+		p.CurrentPrice = price
+		p.UnrealizedPnL = (price - p.EntryPrice) * p.Quantity
+	}
+}
+
+func (b *testBroker) GetPosition(symbol string) *Position {
+	return b.positions[symbol]
+}
+
+// scriptedStrategy returns a predetermined signal per candle index
+type scriptedStrategy struct {
+	signals map[int]Signal
+	index   int
+}
+
+func (s *scriptedStrategy) Name() string { return "scripted" }
+
+func (s *scriptedStrategy) OnCandle(candle Candle, account *Account) Signal {
+	defer func() { s.index++ }()
+	if signal, ok := s.signals[s.index]; ok {
+		return signal
+	}
+	return Signal{Action: SignalActionHold}
+}
+
+func (s *scriptedStrategy) OnTrade(trade *Trade) {}
+
+type nopStore struct{}
+
+func (nopStore) SaveState(broker Broker) error { return nil }
+func (nopStore) LoadState(broker Broker) error { return nil }
+
+// makeCandles builds hourly candles from close prices with a 1% range
+func makeCandles(closes ...float64) []Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      c,
+			High:      c * 1.01,
+			Low:       c * 0.99,
+			Close:     c,
+			Volume:    1000,
+		}
+	}
+	return candles
+}
+
+func newTestEngine(broker Broker, strategy Strategy) *Engine {
+	return New(broker, strategy, nopStore{}, logger.New("error"))
+}
+
+func TestMinProfitToExit(t *testing.T) {
+	buy := Signal{Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1}
+	sell := Signal{Action: SignalActionSell, Symbol: "BTC/USD", Quantity: 1}
+
+	tests := []struct {
+		name       string
+		absolute   float64
+		percent    float64
+		closes     []float64
+		signals    map[int]Signal
+		wantClosed bool
+	}{
+		{
+			name:       "no guard exits immediately",
+			closes:     []float64{100, 101, 102},
+			signals:    map[int]Signal{0: buy, 1: sell},
+			wantClosed: true,
+		},
+		{
+			name:       "absolute minimum not reached suppresses exit",
+			absolute:   5,
+			closes:     []float64{100, 102, 103},
+			signals:    map[int]Signal{0: buy, 1: sell, 2: sell},
+			wantClosed: false,
+		},
+		{
+			name:       "absolute minimum reached allows exit",
+			absolute:   5,
+			closes:     []float64{100, 102, 106},
+			signals:    map[int]Signal{0: buy, 1: sell, 2: sell},
+			wantClosed: true,
+		},
+		{
+			name:       "percent minimum reached allows exit",
+			percent:    3,
+			closes:     []float64{100, 102, 104},
+			signals:    map[int]Signal{0: buy, 1: sell, 2: sell},
+			wantClosed: true,
+		},
+		{
+			name:       "losing position is held when target is never hit",
+			percent:    1,
+			closes:     []float64{100, 97, 95, 90},
+			signals:    map[int]Signal{0: buy, 1: sell, 2: sell, 3: sell},
+			wantClosed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: tt.signals})
+			e.SetMinProfitToExit(tt.absolute, tt.percent)
+
+			if err := e.Run(context.Background(), makeCandles(tt.closes...)); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			closed := broker.GetPosition("BTC/USD") == nil
+			if closed != tt.wantClosed {
+				t.Errorf("position closed = %v, want %v", closed, tt.wantClosed)
+			}
+		})
+	}
+}
+
+func TestMarkPriceFunc(t *testing.T) {
+	buy := Signal{Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1}
+
+	tests := []struct {
+		name     string
+		mark     MarkPriceFunc
+		wantMark float64
+	}{
+		{"default close", nil, 110},
+		{"low", MarkAtLow, 110 * 0.99},
+		{"high", MarkAtHigh, 110 * 1.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: map[int]Signal{0: buy}})
+			e.SetMarkPriceFunc(tt.mark)
+
+			if err := e.Run(context.Background(), makeCandles(100, 110)); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			position := broker.GetPosition("BTC/USD")
+			if position == nil {
+				t.Fatal("expected open position")
+			}
+			if position.EntryPrice != 100 {
+				t.Errorf("EntryPrice = %.2f, want execution at close 100", position.EntryPrice)
+			}
+			if math.Abs(position.CurrentPrice-tt.wantMark) > 1e-9 {
+				t.Errorf("CurrentPrice = %.4f, want %.4f", position.CurrentPrice, tt.wantMark)
+			}
+		})
+	}
+}
+
+func TestPyramiding(t *testing.T) {
+	buy := Signal{Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1}
+	sell := Signal{Action: SignalActionSell, Symbol: "BTC/USD", Quantity: 0}
+
+	tests := []struct {
+		name      string
+		cfg       PyramidConfig
+		signals   map[int]Signal
+		wantQtys  []float64
+		wantFinal float64
+	}{
+		{
+			name:      "unlimited averages every buy",
+			signals:   map[int]Signal{0: buy, 1: buy, 2: buy},
+			wantQtys:  []float64{1, 1, 1},
+			wantFinal: 3,
+		},
+		{
+			name:      "max entries ignores further buys",
+			cfg:       PyramidConfig{MaxEntries: 2},
+			signals:   map[int]Signal{0: buy, 1: buy, 2: buy, 3: buy},
+			wantQtys:  []float64{1, 1},
+			wantFinal: 2,
+		},
+		{
+			name:      "decreasing add-on size",
+			cfg:       PyramidConfig{MaxEntries: 3, AddScale: 0.5},
+			signals:   map[int]Signal{0: buy, 1: buy, 2: buy},
+			wantQtys:  []float64{1, 0.5, 0.25},
+			wantFinal: 1.75,
+		},
+		{
+			name:      "entries reset after position closes",
+			cfg:       PyramidConfig{MaxEntries: 1},
+			signals:   map[int]Signal{0: buy, 1: buy, 2: sell, 3: buy},
+			wantQtys:  []float64{1, 0, 1},
+			wantFinal: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: tt.signals})
+			e.SetPyramiding(tt.cfg)
+
+			if err := e.Run(context.Background(), makeCandles(100, 101, 102, 103)); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			if len(broker.orders) != len(tt.wantQtys) {
+				t.Fatalf("orders placed = %d, want %d", len(broker.orders), len(tt.wantQtys))
+			}
+			for i, order := range broker.orders {
+				if order.Side == OrderSideBuy && math.Abs(order.Quantity-tt.wantQtys[i]) > 1e-9 {
+					t.Errorf("order %d quantity = %.4f, want %.4f", i, order.Quantity, tt.wantQtys[i])
+				}
+			}
+
+			position := broker.GetPosition("BTC/USD")
+			if position == nil || math.Abs(position.Quantity-tt.wantFinal) > 1e-9 {
+				t.Errorf("final position = %+v, want quantity %.4f", position, tt.wantFinal)
+			}
+		})
+	}
+}
+
+func TestExitRule(t *testing.T) {
+	buy := Signal{Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1}
+
+	emaRule, err := NewEMAExitRule(3)
+	if err != nil {
+		t.Fatalf("NewEMAExitRule() error: %v", err)
+	}
+
+	broker := newTestBroker(10000)
+	e := newTestEngine(broker, &scriptedStrategy{signals: map[int]Signal{2: buy}})
+	e.SetExitRule(emaRule)
+
+	// EMA(3) seeds at 101 and trails the rise; the drop to 100 breaks below it
+	if err := e.Run(context.Background(), makeCandles(100, 101, 102, 104, 106, 100, 99)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if broker.GetPosition("BTC/USD") != nil {
+		t.Fatal("expected exit rule to close the position")
+	}
+	if len(broker.trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(broker.trades))
+	}
+	if got := broker.trades[0].ExitPrice; got != 100 {
+		t.Errorf("exit price = %.2f, want 100", got)
+	}
+}
+
+func TestRSIExitRule(t *testing.T) {
+	rule, err := NewRSIExitRule(2, 50)
+	if err != nil {
+		t.Fatalf("NewRSIExitRule() error: %v", err)
+	}
+
+	position := &Position{Symbol: "BTC/USD", Quantity: 1}
+	candles := makeCandles(100, 101, 102, 103, 99)
+
+	var exits []int
+	for i, c := range candles {
+		rule.OnCandle(c)
+		if exit, _ := rule.ShouldExit(position, c); exit {
+			exits = append(exits, i)
+		}
+	}
+
+	if len(exits) != 1 || exits[0] != 4 {
+		t.Errorf("exits at %v, want [4]", exits)
+	}
+
+	if _, err := NewRSIExitRule(14, 0); err == nil {
+		t.Error("NewRSIExitRule() with level 0 expected error")
+	}
+}
+
+func TestExitRulesTrackSymbolsSeparately(t *testing.T) {
+	tag := func(symbol string, candles []Candle) []Candle {
+		for i := range candles {
+			candles[i].Symbol = symbol
+		}
+		return candles
+	}
+	// BTC trends up and dips at the end; ETH trades far lower and flat, so a
+	// shared series would drag BTC's EMA and RSI down and vice versa
+	btc := tag("BTC", makeCandles(100, 101, 102, 103, 99))
+	eth := tag("ETH", makeCandles(10, 10, 10, 10, 10))
+
+	rules := map[string]func() (ExitRule, error){
+		"ema": func() (ExitRule, error) { return NewEMAExitRule(3) },
+		"rsi": func() (ExitRule, error) { return NewRSIExitRule(2, 50) },
+	}
+	for name, newRule := range rules {
+		t.Run(name, func(t *testing.T) {
+			rule, err := newRule()
+			if err != nil {
+				t.Fatalf("new rule error: %v", err)
+			}
+
+			exits := map[string][]int{}
+			for i := range btc {
+				for _, c := range []Candle{btc[i], eth[i]} {
+					rule.OnCandle(c)
+					position := &Position{Symbol: c.Symbol, Quantity: 1}
+					if exit, _ := rule.ShouldExit(position, c); exit {
+						exits[c.Symbol] = append(exits[c.Symbol], i)
+					}
+				}
+			}
+
+			if got := exits["BTC"]; len(got) != 1 || got[0] != 4 {
+				t.Errorf("BTC exits at %v, want [4]", got)
+			}
+			if got := exits["ETH"]; len(got) != 0 {
+				t.Errorf("ETH exits at %v, want none", got)
+			}
+		})
+	}
+}
+
+func TestMarketPriceFollowsPositionSymbol(t *testing.T) {
+	for _, symbol := range []string{"BTC/USD", "BTCUSDT"} {
+		t.Run(symbol, func(t *testing.T) {
+			buy := Signal{Action: SignalActionBuy, Symbol: symbol, Quantity: 1}
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: map[int]Signal{0: buy}})
+
+			if err := e.Run(context.Background(), makeCandles(100, 105, 120)); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			position := broker.GetPosition(symbol)
+			if position == nil {
+				t.Fatal("expected open position")
+			}
+			if position.CurrentPrice != 120 {
+				t.Errorf("CurrentPrice = %.2f, want 120", position.CurrentPrice)
+			}
+			if position.UnrealizedPnL != 20 {
+				t.Errorf("UnrealizedPnL = %.2f, want 20", position.UnrealizedPnL)
+			}
+		})
+	}
+}
+
+func TestStopLossAndTakeProfit(t *testing.T) {
+	tests := []struct {
+		name       string
+		signal     Signal
+		mark       MarkPriceFunc
+		trigger    MarkPriceFunc
+		candles    []Candle
+		wantExit   float64
+		wantReason string
+	}{
+		{
+			name:   "stop-loss on intrabar dip",
+			signal: Signal{StopLoss: 95},
+			candles: []Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 99, High: 99.5, Low: 94, Close: 98},
+			},
+			wantExit:   95,
+			wantReason: "stop-loss hit",
+		},
+		{
+			name:   "stop-loss gap fills at open",
+			signal: Signal{StopLoss: 95},
+			candles: []Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 90, High: 91, Low: 89, Close: 90},
+			},
+			wantExit:   90,
+			wantReason: "stop-loss hit",
+		},
+		{
+			name:   "take-profit on intrabar spike",
+			signal: Signal{StopLoss: 95, TakeProfit: 110},
+			candles: []Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 104, High: 112, Low: 103, Close: 105},
+			},
+			wantExit:   110,
+			wantReason: "take-profit hit",
+		},
+		{
+			name:   "stop-loss uses candle range regardless of mark",
+			signal: Signal{StopLoss: 95},
+			mark:   MarkAtHigh,
+			candles: []Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 99, High: 99.5, Low: 94, Close: 98},
+			},
+			wantExit:   95,
+			wantReason: "stop-loss hit",
+		},
+		{
+			name:    "stop-loss triggers on mark price",
+			signal:  Signal{StopLoss: 95},
+			trigger: MarkAtClose,
+			candles: []Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 99, High: 99.5, Low: 93, Close: 96},
+				{Open: 96, High: 97, Low: 93, Close: 94},
+			},
+			wantExit:   94,
+			wantReason: "stop-loss hit",
+		},
+		{
+			name:    "take-profit triggers on mark price",
+			signal:  Signal{StopLoss: 95, TakeProfit: 110},
+			trigger: MarkAtClose,
+			candles: []Candle{
+				{Open: 100, High: 101, Low: 99, Close: 100},
+				{Open: 104, High: 112, Low: 103, Close: 108},
+				{Open: 108, High: 113, Low: 107, Close: 111},
+			},
+			wantExit:   111,
+			wantReason: "take-profit hit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buy := tt.signal
+			buy.Action = SignalActionBuy
+			buy.Symbol = "BTC/USD"
+			buy.Quantity = 1
+
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := range tt.candles {
+				tt.candles[i].Timestamp = start.Add(time.Duration(i) * time.Hour)
+			}
+
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: map[int]Signal{0: buy}})
+			e.SetMarkPriceFunc(tt.mark)
+			e.SetStopTrigger(tt.trigger)
+			// The guard applies to strategy exits only, never to protective stops
+			e.SetMinProfitToExit(1000, 0)
+
+			if err := e.Run(context.Background(), tt.candles); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			if broker.GetPosition("BTC/USD") != nil {
+				t.Fatal("expected position to be closed")
+			}
+			if len(broker.trades) != 1 {
+				t.Fatalf("trades = %d, want 1", len(broker.trades))
+			}
+			trade := broker.trades[0]
+			if trade.ExitPrice != tt.wantExit {
+				t.Errorf("exit price = %.2f, want %.2f", trade.ExitPrice, tt.wantExit)
+			}
+			if trade.Reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", trade.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+// firstCandleBuyer buys each symbol on the first candle it sees for it
+type firstCandleBuyer struct {
+	seen map[string]bool
+}
+
+func (s *firstCandleBuyer) Name() string { return "first-candle-buyer" }
+
+func (s *firstCandleBuyer) OnCandle(candle Candle, account *Account) Signal {
+	if s.seen[candle.Symbol] {
+		return Signal{Action: SignalActionHold}
+	}
+	s.seen[candle.Symbol] = true
+	return Signal{Action: SignalActionBuy, Symbol: candle.Symbol, Quantity: 1}
+}
+
+func (s *firstCandleBuyer) OnTrade(trade *Trade) {}
+
+func TestRunMulti(t *testing.T) {
+	btc := makeCandles(100, 110, 120)
+	// ETH starts an hour later and falls while BTC rises
+	eth := makeCandles(50, 45, 40)
+	for i := range eth {
+		eth[i].Timestamp = eth[i].Timestamp.Add(30 * time.Minute)
+	}
+
+	merged := MergeCandles(map[string][]Candle{"BTC": btc, "ETH": eth})
+	for i := 1; i < len(merged); i++ {
+		if merged[i].Timestamp.Before(merged[i-1].Timestamp) {
+			t.Fatalf("merged stream out of order at %d", i)
+		}
+	}
+	if merged[0].Symbol != "BTC" || merged[1].Symbol != "ETH" {
+		t.Fatalf("merged order = %s, %s, want BTC, ETH", merged[0].Symbol, merged[1].Symbol)
+	}
+
+	broker := newTestBroker(10000)
+	e := newTestEngine(broker, &firstCandleBuyer{seen: make(map[string]bool)})
+	if err := e.RunMulti(context.Background(), map[string][]Candle{"BTC": btc, "ETH": eth}); err != nil {
+		t.Fatalf("RunMulti() error: %v", err)
+	}
+
+	tests := []struct {
+		symbol    string
+		wantEntry float64
+		wantMark  float64
+	}{
+		{"BTC", 100, 120},
+		{"ETH", 50, 40},
+	}
+	for _, tt := range tests {
+		position := broker.GetPosition(tt.symbol)
+		if position == nil {
+			t.Fatalf("expected open %s position", tt.symbol)
+		}
+		if position.EntryPrice != tt.wantEntry {
+			t.Errorf("%s entry = %.2f, want %.2f", tt.symbol, position.EntryPrice, tt.wantEntry)
+		}
+		if position.CurrentPrice != tt.wantMark {
+			t.Errorf("%s mark = %.2f, want %.2f", tt.symbol, position.CurrentPrice, tt.wantMark)
+		}
+	}
+}
+
+func TestStopsFillInSymbolOrder(t *testing.T) {
+	signals := make(map[int]Signal)
+	for i, symbol := range []string{"SOL", "BTC", "ETH"} {
+		signals[i] = Signal{Action: SignalActionBuy, Symbol: symbol, Quantity: 1, StopLoss: 95}
+	}
+
+	// Unsymbolled candles price every position, so one dip hits all stops
+	for run := 0; run < 5; run++ {
+		broker := newTestBroker(10000)
+		e := newTestEngine(broker, &scriptedStrategy{signals: signals})
+		if err := e.Run(context.Background(), makeCandles(100, 100, 100, 90)); err != nil {
+			t.Fatalf("Run() error: %v", err)
+		}
+
+		var got []string
+		for _, trade := range broker.trades {
+			got = append(got, trade.Symbol)
+		}
+		if want := []string{"BTC", "ETH", "SOL"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: stop fills = %v, want %v", run, got, want)
+		}
+	}
+}
+
+// countingStore counts state saves
+type countingStore struct {
+	saves int
+}
+
+func (s *countingStore) SaveState(broker Broker) error { s.saves++; return nil }
+func (s *countingStore) LoadState(broker Broker) error { return nil }
+
+func TestRunLive(t *testing.T) {
+	broker := newTestBroker(10000)
+	strategy := &scriptedStrategy{signals: map[int]Signal{
+		0: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1},
+		2: {Action: SignalActionSell, Symbol: "BTC/USD", Quantity: 1},
+	}}
+	store := &countingStore{}
+	e := New(broker, strategy, store, logger.New("error"))
+
+	candleCh := make(chan Candle)
+	errCh := make(chan error)
+	done := make(chan error, 1)
+	go func() { done <- e.RunLive(context.Background(), candleCh, errCh) }()
+
+	candles := makeCandles(100, 105, 110)
+	candleCh <- candles[0]
+	errCh <- errors.New("feed hiccup")
+	candleCh <- candles[1]
+	close(errCh)
+	candleCh <- candles[2]
+	close(candleCh)
+
+	if err := <-done; err != nil {
+		t.Fatalf("RunLive() error: %v", err)
+	}
+	if len(broker.trades) != 1 || broker.trades[0].PnL != 10 {
+		t.Fatalf("trades = %+v, want one trade with PnL 10", broker.trades)
+	}
+	if store.saves != 1 {
+		t.Errorf("state saves = %d, want 1 when the feed closes", store.saves)
+	}
+}
+
+func TestRunLiveStopsOnCancel(t *testing.T) {
+	store := &countingStore{}
+	e := New(newTestBroker(10000), &scriptedStrategy{}, store, logger.New("error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	candleCh := make(chan Candle)
+	done := make(chan error, 1)
+	go func() { done <- e.RunLive(ctx, candleCh, nil) }()
+
+	candleCh <- makeCandles(100)[0]
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunLive() error = %v, want context.Canceled", err)
+	}
+	if store.saves != 1 {
+		t.Errorf("state saves = %d, want 1 on shutdown", store.saves)
+	}
+}
+
+func TestSaveInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		want     int
+	}{
+		{"only at end", 0, 1},
+		{"default", defaultSaveInterval, 3},
+		{"every third candle", 3, 9},
+	}
+
+	closes := make([]float64, 25)
+	for i := range closes {
+		closes[i] = 100
+	}
+	candles := makeCandles(closes...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &countingStore{}
+			e := New(newTestBroker(10000), &scriptedStrategy{}, store, logger.New("error"))
+			e.SetSaveInterval(tt.interval)
+
+			if err := e.Run(context.Background(), candles); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			if store.saves != tt.want {
+				t.Errorf("saves = %d, want %d", store.saves, tt.want)
+			}
+		})
+	}
+}
+
+func TestRiskLimits(t *testing.T) {
+	buy := func(symbol string, qty float64) Signal {
+		return Signal{Action: SignalActionBuy, Symbol: symbol, Quantity: qty}
+	}
+
+	tests := []struct {
+		name       string
+		limits     RiskLimits
+		signals    map[int]Signal
+		wantOrders int
+	}{
+		{
+			name:       "no limits",
+			signals:    map[int]Signal{0: buy("BTC/USD", 10), 1: buy("ETH/USD", 10), 2: buy("SOL/USD", 10)},
+			wantOrders: 3,
+		},
+		{
+			name:       "max open positions rejects a new symbol",
+			limits:     RiskLimits{MaxOpenPositions: 2},
+			signals:    map[int]Signal{0: buy("BTC/USD", 10), 1: buy("ETH/USD", 10), 2: buy("SOL/USD", 10)},
+			wantOrders: 2,
+		},
+		{
+			name:       "max open positions allows adding to an open symbol",
+			limits:     RiskLimits{MaxOpenPositions: 1},
+			signals:    map[int]Signal{0: buy("BTC/USD", 10), 1: buy("BTC/USD", 10), 2: buy("ETH/USD", 10)},
+			wantOrders: 2,
+		},
+		{
+			// Each buy is worth 1000 of 10000 equity
+			name:       "max exposure rejects the buy that crosses it",
+			limits:     RiskLimits{MaxExposurePct: 25},
+			signals:    map[int]Signal{0: buy("BTC/USD", 10), 1: buy("ETH/USD", 10), 2: buy("SOL/USD", 10)},
+			wantOrders: 2,
+		},
+		{
+			name:       "max exposure rejects an oversized first buy",
+			limits:     RiskLimits{MaxExposurePct: 5},
+			signals:    map[int]Signal{0: buy("BTC/USD", 10)},
+			wantOrders: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: tt.signals})
+			e.SetRiskLimits(tt.limits)
+
+			if err := e.Run(context.Background(), makeCandles(100, 100, 100)); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			if len(broker.orders) != tt.wantOrders {
+				t.Errorf("orders placed = %d, want %d", len(broker.orders), tt.wantOrders)
+			}
+		})
+	}
+}
+
+// symbolRecorder records the symbol of every candle it sees
+type symbolRecorder struct {
+	symbols []string
+}
+
+func (s *symbolRecorder) Name() string { return "symbol recorder" }
+
+func (s *symbolRecorder) OnCandle(candle Candle, account *Account) Signal {
+	s.symbols = append(s.symbols, candle.Symbol)
+	return Signal{Action: SignalActionHold}
+}
+
+func (s *symbolRecorder) OnTrade(trade *Trade) {}
+
+func TestSetSymbol(t *testing.T) {
+	strategy := &symbolRecorder{}
+	e := newTestEngine(newTestBroker(10000), strategy)
+	e.SetSymbol("ETHUSDT")
+
+	candles := makeCandles(100, 101)
+	candles[1].Symbol = "BTCUSDT"
+	if err := e.Run(context.Background(), candles); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	// Candles that already carry a symbol keep it
+	want := []string{"ETHUSDT", "BTCUSDT"}
+	for i, symbol := range strategy.symbols {
+		if symbol != want[i] {
+			t.Errorf("candle %d symbol = %q, want %q", i, symbol, want[i])
+		}
+	}
+}
+
+func TestTradeExcursions(t *testing.T) {
+	strategy := &scriptedStrategy{signals: map[int]Signal{
+		0: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 2, StopLoss: 95},
+		3: {Action: SignalActionSell, Symbol: "BTC/USD", Quantity: 2},
+	}}
+	broker := newTestBroker(10000)
+	e := newTestEngine(broker, strategy)
+
+	// Dips to a 95.04 low without touching the stop, then recovers to a
+	// 111.1 high on the exit candle
+	if err := e.Run(context.Background(), makeCandles(100, 96, 104, 110)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if len(broker.trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(broker.trades))
+	}
+	trade := broker.trades[0]
+
+	if math.Abs(trade.MAE-9.92) > 1e-9 {
+		t.Errorf("MAE = %.4f, want 9.92", trade.MAE)
+	}
+	if math.Abs(trade.MFE-22.2) > 1e-9 {
+		t.Errorf("MFE = %.4f, want 22.2", trade.MFE)
+	}
+	// 20 profit on 10 of initial risk
+	if math.Abs(trade.RMultiple-2) > 1e-9 {
+		t.Errorf("RMultiple = %.4f, want 2", trade.RMultiple)
+	}
+}
+
+func TestTradeExcursionsClampedToStopFill(t *testing.T) {
+	tests := []struct {
+		name    string
+		signal  Signal
+		exit    Candle
+		wantMAE float64
+		wantMFE float64
+	}{
+		{
+			// Fills at 95 although the candle trades down to 90
+			name:    "stop-loss",
+			signal:  Signal{StopLoss: 95},
+			exit:    Candle{Open: 99, High: 102, Low: 90, Close: 91},
+			wantMAE: 5,
+			wantMFE: 2,
+		},
+		{
+			// Fills at 110 although the candle trades up to 120
+			name:    "take-profit",
+			signal:  Signal{TakeProfit: 110},
+			exit:    Candle{Open: 101, High: 120, Low: 98, Close: 118},
+			wantMAE: 2,
+			wantMFE: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buy := tt.signal
+			buy.Action = SignalActionBuy
+			buy.Symbol = "BTC/USD"
+			buy.Quantity = 1
+
+			candles := []Candle{{Open: 100, High: 100, Low: 100, Close: 100}, tt.exit}
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := range candles {
+				candles[i].Timestamp = start.Add(time.Duration(i) * time.Hour)
+			}
+
+			broker := newTestBroker(10000)
+			e := newTestEngine(broker, &scriptedStrategy{signals: map[int]Signal{0: buy}})
+			if err := e.Run(context.Background(), candles); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			if len(broker.trades) != 1 {
+				t.Fatalf("trades = %d, want 1", len(broker.trades))
+			}
+			trade := broker.trades[0]
+			if math.Abs(trade.MAE-tt.wantMAE) > 1e-9 {
+				t.Errorf("MAE = %.4f, want %.4f", trade.MAE, tt.wantMAE)
+			}
+			if math.Abs(trade.MFE-tt.wantMFE) > 1e-9 {
+				t.Errorf("MFE = %.4f, want %.4f", trade.MFE, tt.wantMFE)
+			}
+		})
+	}
+}
+
+func TestTradeExcursionsWithoutStop(t *testing.T) {
+	strategy := &scriptedStrategy{signals: map[int]Signal{
+		0: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1},
+		1: {Action: SignalActionSell, Symbol: "BTC/USD", Quantity: 1},
+	}}
+	broker := newTestBroker(10000)
+	if err := newTestEngine(broker, strategy).Run(context.Background(), makeCandles(100, 105)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	// The exit candle never trades below entry
+	trade := broker.trades[0]
+	if trade.MAE != 0 || trade.RMultiple != 0 {
+		t.Errorf("MAE = %.4f, RMultiple = %.4f, want 0 for both", trade.MAE, trade.RMultiple)
+	}
+}
+
+func TestMaxHoldDuration(t *testing.T) {
+	strategy := &scriptedStrategy{signals: map[int]Signal{
+		0: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1},
+		4: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1},
+	}}
+	broker := newTestBroker(10000)
+	e := newTestEngine(broker, strategy)
+	e.SetMaxHoldDuration(2 * time.Hour)
+
+	// Opened at hour 0; hour 2 is at the limit and hour 3 is past it
+	if err := e.Run(context.Background(), makeCandles(100, 101, 102, 103, 104, 105)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if len(broker.trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(broker.trades))
+	}
+	trade := broker.trades[0]
+	if trade.Reason != "max hold duration exceeded" {
+		t.Errorf("reason = %q, want max hold duration exceeded", trade.Reason)
+	}
+	if trade.ExitPrice != 103 {
+		t.Errorf("exit price = %.2f, want the hour 3 close of 103", trade.ExitPrice)
+	}
+
+	// The re-entry at hour 4 is still within the limit
+	if position := broker.GetPosition("BTC/USD"); position == nil || position.EntryPrice != 104 {
+		t.Errorf("position = %+v, want the re-entry at 104 still open", position)
+	}
+}
+
+func TestCloseOnFinish(t *testing.T) {
+	strategy := &scriptedStrategy{signals: map[int]Signal{
+		0: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1},
+	}}
+	broker := newTestBroker(10000)
+	e := newTestEngine(broker, strategy)
+	e.SetCloseOnFinish(true)
+
+	if err := e.Run(context.Background(), makeCandles(100, 101, 105)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if len(broker.trades) != 1 {
+		t.Fatalf("trades = %d, want the open long realized", len(broker.trades))
+	}
+	trade := broker.trades[0]
+	if trade.Reason != "close on finish" {
+		t.Errorf("reason = %q, want close on finish", trade.Reason)
+	}
+	if trade.ExitPrice != 105 {
+		t.Errorf("exit price = %.2f, want the last close of 105", trade.ExitPrice)
+	}
+	if position := broker.GetPosition("BTC/USD"); position != nil && position.Quantity != 0 {
+		t.Errorf("position = %+v, want flat", position)
+	}
+}
+
+// recordingObserver logs every callback as a short event string
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnSignal(signal Signal, candle Candle) {
+	o.events = append(o.events, fmt.Sprintf("signal %s %.0f", signal.Action, candle.Close))
+}
+
+func (o *recordingObserver) OnOrderFilled(order *Order) {
+	o.events = append(o.events, fmt.Sprintf("filled %s %.0f", order.Side, order.FilledPrice))
+}
+
+func (o *recordingObserver) OnTrade(trade *Trade) {
+	o.events = append(o.events, fmt.Sprintf("trade %.0f", trade.NetPnL))
+}
+
+func TestObserverSequence(t *testing.T) {
+	strategy := &scriptedStrategy{signals: map[int]Signal{
+		0: {Action: SignalActionBuy, Symbol: "BTC/USD", Quantity: 1},
+		2: {Action: SignalActionSell, Symbol: "BTC/USD", Quantity: 1},
+	}}
+	broker := newTestBroker(10000)
+	observer := &recordingObserver{}
+	e := New(broker, strategy, nopStore{}, logger.New("error"), observer)
+
+	if err := e.Run(context.Background(), makeCandles(100, 103, 110)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	want := []string{
+		"signal buy 100",
+		"filled buy 100",
+		"signal hold 103",
+		"signal sell 110",
+		"filled sell 110",
+		"trade 10",
+	}
+	if !reflect.DeepEqual(observer.events, want) {
+		t.Errorf("events = %q, want %q", observer.events, want)
+	}
+}
@@ -0,0 +1,173 @@
+package engine
+
+import "fmt"
+
+// ExitRule decides when to close an open position independently of the
+// entry strategy. Pairing a strategy with an exit rule avoids writing a
+// single strategy that handles both entries and exits.
+type ExitRule interface {
+	// Name returns the rule name
+	Name() string
+
+	// OnCandle is called for every candle, whether or not a position is
+	// open, so the rule can keep its indicator state current
+	OnCandle(candle Candle)
+
+	// ShouldExit reports whether the position should be closed at the
+	// current candle, along with the reason recorded on the exit
+	ShouldExit(position *Position, candle Candle) (bool, string)
+}
+
+// EMAExitRule exits when the close falls below a trailing EMA of closes.
+// The EMA is tracked per Candle.Symbol so multi-symbol runs keep each
+// symbol's series apart.
+type EMAExitRule struct {
+	period int
+	states map[string]*emaState
+}
+
+// emaState is the EMA of one symbol's closes
+type emaState struct {
+	count int
+	sum   float64
+	ema   float64
+}
+
+// NewEMAExitRule creates a rule that exits on a close below the EMA of the
+// given period
+func NewEMAExitRule(period int) (*EMAExitRule, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("period must be positive")
+	}
+	return &EMAExitRule{period: period, states: make(map[string]*emaState)}, nil
+}
+
+// Name returns the rule name
+func (r *EMAExitRule) Name() string {
+	return fmt.Sprintf("ema_exit(%d)", r.period)
+}
+
+// OnCandle updates the candle symbol's EMA, seeding it with the SMA of the
+// first period closes
+func (r *EMAExitRule) OnCandle(candle Candle) {
+	st, ok := r.states[candle.Symbol]
+	if !ok {
+		st = &emaState{}
+		r.states[candle.Symbol] = st
+	}
+
+	st.count++
+	if st.count <= r.period {
+		st.sum += candle.Close
+		if st.count == r.period {
+			st.ema = st.sum / float64(r.period)
+		}
+		return
+	}
+
+	multiplier := 2.0 / float64(r.period+1)
+	st.ema = (candle.Close-st.ema)*multiplier + st.ema
+}
+
+// ShouldExit reports a close below the EMA once enough candles of the
+// symbol have been seen
+func (r *EMAExitRule) ShouldExit(position *Position, candle Candle) (bool, string) {
+	st, ok := r.states[candle.Symbol]
+	if !ok || st.count < r.period || candle.Close >= st.ema {
+		return false, ""
+	}
+	return true, fmt.Sprintf("close %.2f below EMA(%d) %.2f", candle.Close, r.period, st.ema)
+}
+
+// RSIExitRule exits when RSI crosses from at or above a level to below it.
+// The RSI is tracked per Candle.Symbol like EMAExitRule.
+type RSIExitRule struct {
+	period int
+	level  float64
+	states map[string]*rsiState
+}
+
+// rsiState is the Wilder-smoothed RSI of one symbol's closes
+type rsiState struct {
+	count     int
+	prevClose float64
+	avgGain   float64
+	avgLoss   float64
+	rsi       float64
+	prevRSI   float64
+}
+
+// NewRSIExitRule creates a rule that exits when RSI of the given period
+// crosses back below level (e.g. 14 and 50)
+func NewRSIExitRule(period int, level float64) (*RSIExitRule, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("period must be positive")
+	}
+	if level <= 0 || level >= 100 {
+		return nil, fmt.Errorf("level must be between 0 and 100")
+	}
+	return &RSIExitRule{period: period, level: level, states: make(map[string]*rsiState)}, nil
+}
+
+// Name returns the rule name
+func (r *RSIExitRule) Name() string {
+	return fmt.Sprintf("rsi_exit(%d,%.0f)", r.period, r.level)
+}
+
+// OnCandle updates the candle symbol's RSI using Wilder smoothing
+func (r *RSIExitRule) OnCandle(candle Candle) {
+	st, ok := r.states[candle.Symbol]
+	if !ok {
+		st = &rsiState{}
+		r.states[candle.Symbol] = st
+	}
+
+	st.count++
+	if st.count == 1 {
+		st.prevClose = candle.Close
+		return
+	}
+
+	change := candle.Close - st.prevClose
+	st.prevClose = candle.Close
+
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	// The first average covers period changes, i.e. period+1 closes
+	changes := st.count - 1
+	if changes <= r.period {
+		st.avgGain += gain / float64(r.period)
+		st.avgLoss += loss / float64(r.period)
+		if changes < r.period {
+			return
+		}
+	} else {
+		st.avgGain = (st.avgGain*float64(r.period-1) + gain) / float64(r.period)
+		st.avgLoss = (st.avgLoss*float64(r.period-1) + loss) / float64(r.period)
+	}
+
+	st.prevRSI = st.rsi
+	if st.avgLoss == 0 {
+		st.rsi = 100
+	} else {
+		st.rsi = 100 - 100/(1+st.avgGain/st.avgLoss)
+	}
+}
+
+// ShouldExit reports an RSI cross below the level on the current candle
+func (r *RSIExitRule) ShouldExit(position *Position, candle Candle) (bool, string) {
+	// A cross needs two RSI values, which takes period+2 closes
+	st, ok := r.states[candle.Symbol]
+	if !ok || st.count < r.period+2 {
+		return false, ""
+	}
+	if st.prevRSI >= r.level && st.rsi < r.level {
+		return true, fmt.Sprintf("RSI(%d) crossed below %.0f (%.2f)", r.period, r.level, st.rsi)
+	}
+	return false, ""
+}
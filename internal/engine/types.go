@@ -1,12 +1,16 @@
 package engine
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 )
 
 // Candle represents OHLCV candle data
 type Candle struct {
 	Timestamp time.Time
+	Symbol    string // Set for multi-symbol runs; empty applies to every position
 	Open      float64
 	High      float64
 	Low       float64
@@ -54,6 +58,8 @@ type Order struct {
 	FilledQty     float64     `json:"filled_qty"`     // Actual filled quantity
 	Fee           float64     `json:"fee"`
 	Slippage      float64     `json:"slippage"`       // Difference from expected price
+	Reason        string      `json:"reason,omitempty"` // Why the order was placed
+	RejectReason  string      `json:"reject_reason,omitempty"` // Why the broker rejected the order
 }
 
 // Position represents an open position
@@ -80,6 +86,16 @@ type Trade struct {
 	NetPnL      float64   `json:"net_pnl"`
 	OpenedAt    time.Time `json:"opened_at"`
 	ClosedAt    time.Time `json:"closed_at"`
+	Reason      string    `json:"reason,omitempty"` // Exit reason from the closing order
+
+	// MAE and MFE are the maximum adverse and favorable excursions while the
+	// position was open, in quote currency for Quantity. Set by the engine.
+	MAE float64 `json:"mae"`
+	MFE float64 `json:"mfe"`
+
+	// RMultiple is NetPnL divided by the initial risk, (entry - initial
+	// stop) * Quantity. Zero when the entry carried no stop-loss.
+	RMultiple float64 `json:"r_multiple"`
 }
 
 // Account represents the trading account state
@@ -98,6 +114,11 @@ type Signal struct {
 	Symbol   string
 	Quantity float64
 	Reason   string // For logging/debugging
+
+	// Optional protective exits for a BUY, as absolute prices. Zero means
+	// unset; a later BUY on the same position replaces any non-zero level.
+	StopLoss   float64
+	TakeProfit float64
 }
 
 // SignalAction represents the action to take
@@ -108,3 +129,47 @@ const (
 	SignalActionSell SignalAction = "sell"
 	SignalActionHold SignalAction = "hold"
 )
+
+// ErrInvalidCandle is returned when a candle carries impossible values
+var ErrInvalidCandle = errors.New("invalid candle")
+
+// Validate checks that the candle has finite, positive prices, a
+// non-negative volume, and open/close within the high/low range
+func (c Candle) Validate() error {
+	ts := c.Timestamp.UTC().Format(time.RFC3339)
+
+	for _, v := range []float64{c.Open, c.High, c.Low, c.Close, c.Volume} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w at %s: non-finite value", ErrInvalidCandle, ts)
+		}
+	}
+	if c.Open <= 0 || c.High <= 0 || c.Low <= 0 || c.Close <= 0 {
+		return fmt.Errorf("%w at %s: prices must be positive", ErrInvalidCandle, ts)
+	}
+	if c.Volume < 0 {
+		return fmt.Errorf("%w at %s: negative volume %.8f", ErrInvalidCandle, ts, c.Volume)
+	}
+	if c.High < c.Low {
+		return fmt.Errorf("%w at %s: high (%.2f) < low (%.2f)", ErrInvalidCandle, ts, c.High, c.Low)
+	}
+	if c.Open < c.Low || c.Open > c.High {
+		return fmt.Errorf("%w at %s: open (%.2f) outside [low, high] range", ErrInvalidCandle, ts, c.Open)
+	}
+	if c.Close < c.Low || c.Close > c.High {
+		return fmt.Errorf("%w at %s: close (%.2f) outside [low, high] range", ErrInvalidCandle, ts, c.Close)
+	}
+
+	return nil
+}
+
+// SanitizeCandles drops candles that fail validation and returns the
+// remaining candles along with the number rejected
+func SanitizeCandles(candles []Candle) ([]Candle, int) {
+	valid := make([]Candle, 0, len(candles))
+	for _, c := range candles {
+		if c.Validate() == nil {
+			valid = append(valid, c)
+		}
+	}
+	return valid, len(candles) - len(valid)
+}
@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ttlCache holds fetched candles per symbol and timeframe for a limited time
+type ttlCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+// cacheEntry is a cached candle series and when it was fetched
+type cacheEntry struct {
+	candles   []Candle
+	fetchedAt time.Time
+}
+
+// newTTLCache creates a cache that expires entries after one timeframe
+func newTTLCache() *ttlCache {
+	return &ttlCache{
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+// setTTL sets the entry lifetime. Zero uses the timeframe duration and a
+// negative value disables caching.
+func (c *ttlCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// get returns a copy of cached candles that have not yet expired, so
+// callers cannot modify the cached series
+func (c *ttlCache) get(symbol string, timeframe Timeframe) ([]Candle, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ttl := c.effectiveTTL(timeframe)
+	if ttl <= 0 {
+		return nil, false
+	}
+
+	entry, ok := c.entries[cacheKey(symbol, timeframe)]
+	if !ok || c.now().Sub(entry.fetchedAt) >= ttl {
+		return nil, false
+	}
+	candles := make([]Candle, len(entry.candles))
+	copy(candles, entry.candles)
+	return candles, true
+}
+
+// put stores freshly fetched candles
+func (c *ttlCache) put(symbol string, timeframe Timeframe, candles []Candle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.effectiveTTL(timeframe) <= 0 {
+		return
+	}
+	c.entries[cacheKey(symbol, timeframe)] = cacheEntry{candles: candles, fetchedAt: c.now()}
+}
+
+// clear drops all cached entries
+func (c *ttlCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+}
+
+// effectiveTTL resolves the configured TTL for a timeframe; callers hold mu
+func (c *ttlCache) effectiveTTL(timeframe Timeframe) time.Duration {
+	if c.ttl == 0 {
+		return timeframe.ToDuration()
+	}
+	return c.ttl
+}
+
+// cacheKey matches the symbol_timeframe key used by LocalFileProvider
+func cacheKey(symbol string, timeframe Timeframe) string {
+	return fmt.Sprintf("%s_%s", symbol, timeframe)
+}
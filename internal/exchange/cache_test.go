@@ -0,0 +1,66 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newTTLCache()
+	cache.now = func() time.Time { return now }
+
+	candles := []Candle{{Timestamp: now, Open: 1, High: 1, Low: 1, Close: 1}}
+	cache.put("bitcoin", Timeframe1h, candles)
+
+	// Default TTL is one timeframe
+	now = now.Add(59 * time.Minute)
+	if _, ok := cache.get("bitcoin", Timeframe1h); !ok {
+		t.Error("expected hit within default TTL")
+	}
+	if _, ok := cache.get("bitcoin", Timeframe4h); ok {
+		t.Error("expected miss for other timeframe")
+	}
+	now = now.Add(time.Minute)
+	if _, ok := cache.get("bitcoin", Timeframe1h); ok {
+		t.Error("expected miss after default TTL")
+	}
+
+	cache.setTTL(2 * time.Hour)
+	if _, ok := cache.get("bitcoin", Timeframe1h); !ok {
+		t.Error("expected hit within explicit TTL")
+	}
+
+	cache.clear()
+	if _, ok := cache.get("bitcoin", Timeframe1h); ok {
+		t.Error("expected miss after clear")
+	}
+
+	cache.setTTL(-1)
+	cache.put("bitcoin", Timeframe1h, candles)
+	if _, ok := cache.get("bitcoin", Timeframe1h); ok {
+		t.Error("expected miss with caching disabled")
+	}
+}
+
+func TestCachedCandlesAreCopies(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newTTLCache()
+	cache.now = func() time.Time { return now }
+	cache.put("bitcoin", Timeframe1h, []Candle{
+		{Timestamp: now, Close: 1},
+		{Timestamp: now.Add(time.Hour), Close: 2},
+	})
+
+	got, _ := cache.get("bitcoin", Timeframe1h)
+	got[0].Close = 99
+	if again, _ := cache.get("bitcoin", Timeframe1h); again[0].Close != 1 {
+		t.Errorf("cached close = %.0f after modifying a get result, want 1", again[0].Close)
+	}
+
+	limited := limitLive(got, 1)
+	limited[0].Close = 99
+	if got[1].Close != 2 {
+		t.Errorf("source close = %.0f after modifying a limitLive result, want 2", got[1].Close)
+	}
+}
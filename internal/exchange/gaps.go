@@ -0,0 +1,59 @@
+package exchange
+
+import (
+	"fmt"
+	"time"
+)
+
+// Gap describes a run of missing candles between two loaded candles
+type Gap struct {
+	Start   time.Time // Timestamp of the first missing candle
+	End     time.Time // Timestamp of the last missing candle
+	Missing int       // Number of missing candles
+}
+
+// DetectGaps returns every place where consecutive candles are spaced far
+// enough apart that at least one whole bar of expectedInterval is missing.
+// Candles must be sorted by time.
+func DetectGaps(candles []Candle, expectedInterval time.Duration) []Gap {
+	if expectedInterval <= 0 {
+		return nil
+	}
+
+	var gaps []Gap
+	for i := 1; i < len(candles); i++ {
+		prev, next := candles[i-1].Timestamp, candles[i].Timestamp
+		missing := int(next.Sub(prev)/expectedInterval) - 1
+		if missing < 1 {
+			continue
+		}
+
+		gaps = append(gaps, Gap{
+			Start:   prev.Add(expectedInterval),
+			End:     prev.Add(time.Duration(missing) * expectedInterval),
+			Missing: missing,
+		})
+	}
+
+	return gaps
+}
+
+// LoadWithGapCheck loads all candles for symbol and timeframe and reports
+// any gaps relative to expectedInterval. A zero expectedInterval uses the
+// timeframe duration. Gaps are reported, not filled, so callers can decide
+// whether to forward-fill or reject the dataset.
+func (p *LocalFileProvider) LoadWithGapCheck(symbol string, timeframe Timeframe, expectedInterval time.Duration) ([]Candle, []Gap, error) {
+	if expectedInterval < 0 {
+		return nil, nil, fmt.Errorf("expected interval must not be negative")
+	}
+	if expectedInterval == 0 {
+		expectedInterval = timeframe.ToDuration()
+	}
+
+	candles, err := p.GetCandles(symbol, timeframe, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return candles, DetectGaps(candles, expectedInterval), nil
+}
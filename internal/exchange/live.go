@@ -0,0 +1,343 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/fetcher"
+)
+
+const (
+	// liveRequestTimeout bounds a single live provider fetch, including retries
+	liveRequestTimeout = 2 * time.Minute
+
+	// binanceMaxLimit is the largest kline page Binance serves
+	binanceMaxLimit = 1000
+
+	// coingeckoDays selects CoinGecko's 4-hour OHLC granularity
+	coingeckoDays = 30
+)
+
+// DataSource names a candle source selectable at runtime
+type DataSource string
+
+const (
+	DataSourceLocal     DataSource = "local"
+	DataSourceCoinGecko DataSource = "coingecko"
+	DataSourceBinance   DataSource = "binance"
+)
+
+// NewProvider constructs the DataProvider for a data source. dataDir is only
+// used by the local source.
+func NewProvider(source DataSource, dataDir string) (DataProvider, error) {
+	switch source {
+	case DataSourceLocal, "":
+		return NewLocalFileProvider(dataDir), nil
+	case DataSourceCoinGecko:
+		return NewCoinGeckoProvider(), nil
+	case DataSourceBinance:
+		return NewBinanceProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown data source: %s", source)
+	}
+}
+
+// coingeckoSource fetches OHLC candles for a coin ID
+type coingeckoSource interface {
+	FetchCandles(ctx context.Context, coinID string, days int) ([]engine.Candle, error)
+}
+
+// CoinGeckoProvider serves live candles from the CoinGecko OHLC API.
+// CoinGecko picks candle granularity from the requested range, so only
+// 4-hour candles are offered.
+type CoinGeckoProvider struct {
+	fetcher coingeckoSource
+	cache   *ttlCache
+
+	// Serializes fetches so concurrent cache misses share one request
+	fetchMu sync.Mutex
+
+	// Cancelled by Close to end every stream
+	streams     context.Context
+	stopStreams context.CancelFunc
+}
+
+// NewCoinGeckoProvider creates a live CoinGecko provider
+func NewCoinGeckoProvider() *CoinGeckoProvider {
+	streams, stop := context.WithCancel(context.Background())
+	return &CoinGeckoProvider{
+		fetcher:     fetcher.NewCoinGeckoFetcher(),
+		cache:       newTTLCache(),
+		streams:     streams,
+		stopStreams: stop,
+	}
+}
+
+// Close ends every stream started by StreamCandles
+func (p *CoinGeckoProvider) Close() {
+	p.stopStreams()
+}
+
+// SetCacheTTL sets how long fetched candles are served from memory.
+// Zero (the default) caches for one timeframe; a negative TTL disables caching.
+func (p *CoinGeckoProvider) SetCacheTTL(ttl time.Duration) {
+	p.cache.setTTL(ttl)
+}
+
+// ClearCache drops all cached candles
+func (p *CoinGeckoProvider) ClearCache() {
+	p.cache.clear()
+}
+
+// GetCandles fetches the last 30 days of 4-hour candles for a coin ID
+func (p *CoinGeckoProvider) GetCandles(symbol string, timeframe Timeframe, limit int) ([]Candle, error) {
+	if timeframe != Timeframe4h {
+		return nil, fmt.Errorf("unsupported timeframe for coingecko: %s", timeframe)
+	}
+
+	coinID := symbol
+	if !fetcher.ValidateCoinID(coinID) {
+		coinID = fetcher.CoinIDFromSymbol(symbol)
+	}
+	if coinID == "" {
+		return nil, fmt.Errorf("unsupported coingecko symbol: %s", symbol)
+	}
+
+	if candles, ok := p.cache.get(coinID, timeframe); ok {
+		return limitLive(candles, limit), nil
+	}
+
+	// The fetcher's rate limit serializes requests anyway, so waiting here
+	// costs nothing and lets callers queued behind a fetch reuse its result
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+
+	if candles, ok := p.cache.get(coinID, timeframe); ok {
+		return limitLive(candles, limit), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), liveRequestTimeout)
+	defer cancel()
+
+	fetched, err := p.fetcher.FetchCandles(ctx, coinID, coingeckoDays)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := fromEngineCandles(fetched)
+	p.cache.put(coinID, timeframe, candles)
+
+	return limitLive(candles, limit), nil
+}
+
+// StreamCandles sends the current candles, then polls once per timeframe
+// and sends candles newer than the last one sent. The channel closes when
+// the provider is closed.
+func (p *CoinGeckoProvider) StreamCandles(symbol string, timeframe Timeframe) (<-chan Candle, error) {
+	return pollStream(p.streams, p, symbol, timeframe, timeframe.ToDuration())
+}
+
+// GetSupportedTimeframes returns the single granularity CoinGecko serves
+func (p *CoinGeckoProvider) GetSupportedTimeframes() []Timeframe {
+	return []Timeframe{Timeframe4h}
+}
+
+// GetSupportedSymbols returns the supported coin IDs
+func (p *CoinGeckoProvider) GetSupportedSymbols() []string {
+	return fetcher.SupportedCoins()
+}
+
+// BinanceProvider serves live candles from the Binance klines API
+type BinanceProvider struct {
+	fetcher *fetcher.BinanceFetcher
+	cache   *ttlCache
+
+	// Cancelled by Close to end every stream
+	streams     context.Context
+	stopStreams context.CancelFunc
+}
+
+// NewBinanceProvider creates a live Binance provider
+func NewBinanceProvider() *BinanceProvider {
+	streams, stop := context.WithCancel(context.Background())
+	return &BinanceProvider{
+		fetcher:     fetcher.NewBinanceFetcher(),
+		cache:       newTTLCache(),
+		streams:     streams,
+		stopStreams: stop,
+	}
+}
+
+// Close ends every stream started by StreamCandles
+func (p *BinanceProvider) Close() {
+	p.stopStreams()
+}
+
+// SetCacheTTL sets how long fetched candles are served from memory.
+// Zero (the default) caches for one timeframe; a negative TTL disables caching.
+func (p *BinanceProvider) SetCacheTTL(ttl time.Duration) {
+	p.cache.setTTL(ttl)
+}
+
+// ClearCache drops all cached candles
+func (p *BinanceProvider) ClearCache() {
+	p.cache.clear()
+}
+
+// GetCandles returns the most recent klines. symbol may be a Binance pair
+// (BTCUSDT) or a coin ID (bitcoin). The full page is fetched and cached so
+// any limit up to 1000 can be served from the cache.
+func (p *BinanceProvider) GetCandles(symbol string, timeframe Timeframe, limit int) ([]Candle, error) {
+	if !timeframe.IsValid() {
+		return nil, fmt.Errorf("unsupported timeframe: %s", timeframe)
+	}
+
+	pair := fetcher.BinanceSymbolFromCoinID(symbol)
+	if pair == "" {
+		pair = strings.ToUpper(symbol)
+	}
+
+	if candles, ok := p.cache.get(pair, timeframe); ok {
+		return limitLive(candles, limit), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), liveRequestTimeout)
+	defer cancel()
+
+	fetched, err := p.fetcher.FetchCandles(ctx, pair, string(timeframe), binanceMaxLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := fromEngineCandles(fetched)
+	p.cache.put(pair, timeframe, candles)
+
+	return limitLive(candles, limit), nil
+}
+
+// StreamCandles sends the current candles, then polls once per timeframe
+// and sends candles newer than the last one sent. The channel closes when
+// the provider is closed.
+func (p *BinanceProvider) StreamCandles(symbol string, timeframe Timeframe) (<-chan Candle, error) {
+	return pollStream(p.streams, p, symbol, timeframe, timeframe.ToDuration())
+}
+
+// GetSupportedTimeframes returns all timeframes, which map directly to
+// Binance kline intervals
+func (p *BinanceProvider) GetSupportedTimeframes() []Timeframe {
+	return []Timeframe{
+		Timeframe1m,
+		Timeframe5m,
+		Timeframe15m,
+		Timeframe1h,
+		Timeframe4h,
+		Timeframe1d,
+	}
+}
+
+// GetSupportedSymbols returns the coin IDs with a known Binance pair
+func (p *BinanceProvider) GetSupportedSymbols() []string {
+	return fetcher.SupportedCoins()
+}
+
+// streamSnapshot fetches candles once and replays them on a closed channel
+func streamSnapshot(p DataProvider, symbol string, timeframe Timeframe) (<-chan Candle, error) {
+	candles, err := p.GetCandles(symbol, timeframe, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Candle, len(candles))
+	for _, candle := range candles {
+		ch <- candle
+	}
+	close(ch)
+
+	return ch, nil
+}
+
+// pollStream sends the provider's current candles, then polls every interval
+// and sends candles newer than the last one sent until ctx is done. A failed
+// poll is retried on the next tick. Only the first fetch can fail the call.
+func pollStream(ctx context.Context, p DataProvider, symbol string, timeframe Timeframe, interval time.Duration) (<-chan Candle, error) {
+	candles, err := p.GetCandles(symbol, timeframe, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Candle, len(candles))
+	go func() {
+		defer close(ch)
+
+		var last time.Time
+		send := func(candles []Candle) bool {
+			for _, candle := range candles {
+				if !candle.Timestamp.After(last) {
+					continue
+				}
+				select {
+				case ch <- candle:
+					last = candle.Timestamp
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		if !send(candles) {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				candles, err := p.GetCandles(symbol, timeframe, 0)
+				if err != nil {
+					continue
+				}
+				if !send(candles) {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// fromEngineCandles converts fetcher output to exchange candles
+func fromEngineCandles(candles []engine.Candle) []Candle {
+	result := make([]Candle, len(candles))
+	for i, c := range candles {
+		result[i] = Candle{
+			Timestamp: c.Timestamp,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	return result
+}
+
+// limitLive returns a copy of the last limit candles, or of all of them
+// when limit is zero, so callers never share the provider's backing array
+func limitLive(candles []Candle, limit int) []Candle {
+	if limit > 0 && limit < len(candles) {
+		candles = candles[len(candles)-limit:]
+	}
+	result := make([]Candle, len(candles))
+	copy(result, candles)
+	return result
+}
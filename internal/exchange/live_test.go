@@ -0,0 +1,182 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		source   DataSource
+		wantType string
+		wantErr  bool
+	}{
+		{"", "*exchange.LocalFileProvider", false},
+		{DataSourceLocal, "*exchange.LocalFileProvider", false},
+		{DataSourceCoinGecko, "*exchange.CoinGeckoProvider", false},
+		{DataSourceBinance, "*exchange.BinanceProvider", false},
+		{"kraken", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.source), func(t *testing.T) {
+			provider, err := NewProvider(tt.source, t.TempDir())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewProvider(%q) expected error", tt.source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewProvider(%q) error: %v", tt.source, err)
+			}
+			if got := fmt.Sprintf("%T", provider); got != tt.wantType {
+				t.Errorf("provider = %s, want %s", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestCoinGeckoProviderRejectsUnsupportedTimeframe(t *testing.T) {
+	if _, err := NewCoinGeckoProvider().GetCandles("bitcoin", Timeframe1h, 0); err == nil {
+		t.Error("GetCandles() with 1h expected error")
+	}
+}
+
+// countingSource serves fixed candles and counts fetches
+type countingSource struct {
+	mu      sync.Mutex
+	fetches int
+	candles []engine.Candle
+}
+
+func (s *countingSource) FetchCandles(ctx context.Context, coinID string, days int) ([]engine.Candle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	return s.candles, nil
+}
+
+func (s *countingSource) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func TestCoinGeckoProviderCachesCandles(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &countingSource{}
+	for i := 0; i < 3; i++ {
+		source.candles = append(source.candles, engine.Candle{
+			Timestamp: start.Add(time.Duration(i) * 4 * time.Hour),
+			Open:      100, High: 101, Low: 99, Close: 100,
+		})
+	}
+	provider := NewCoinGeckoProvider()
+	provider.fetcher = source
+
+	if _, err := provider.GetCandles("bitcoin", Timeframe4h, 0); err != nil {
+		t.Fatalf("GetCandles() error: %v", err)
+	}
+	candles, err := provider.GetCandles("BTC", Timeframe4h, 2)
+	if err != nil {
+		t.Fatalf("GetCandles() error: %v", err)
+	}
+	if got := source.count(); got != 1 {
+		t.Errorf("fetches = %d, want 1 for a second call within TTL", got)
+	}
+	if len(candles) != 2 {
+		t.Errorf("cached candles = %d, want limit of 2", len(candles))
+	}
+
+	// Concurrent misses after a clear share one fetch
+	provider.ClearCache()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := provider.GetCandles("bitcoin", Timeframe4h, 0); err != nil {
+				t.Errorf("GetCandles() error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := source.count(); got != 2 {
+		t.Errorf("fetches = %d, want 2 after clear and concurrent calls", got)
+	}
+
+	provider.SetCacheTTL(-1)
+	provider.GetCandles("bitcoin", Timeframe4h, 0)
+	provider.GetCandles("bitcoin", Timeframe4h, 0)
+	if got := source.count(); got != 4 {
+		t.Errorf("fetches = %d, want 4 with caching disabled", got)
+	}
+}
+
+func TestPollStreamSendsOnlyNewerCandles(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candleAt := func(i int) Candle {
+		return Candle{Timestamp: start.Add(time.Duration(i) * time.Hour), Open: 100, High: 101, Low: 99, Close: 100}
+	}
+
+	provider := NewMemoryProvider(map[string][]Candle{
+		"bitcoin_1h": {candleAt(0), candleAt(1)},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := pollStream(ctx, provider, "bitcoin", Timeframe1h, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("pollStream() error: %v", err)
+	}
+
+	receive := func() Candle {
+		t.Helper()
+		select {
+		case candle, ok := <-ch:
+			if !ok {
+				t.Fatal("stream closed early")
+			}
+			return candle
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for candle")
+		}
+		return Candle{}
+	}
+
+	for i := 0; i < 2; i++ {
+		if got := receive(); !got.Timestamp.Equal(candleAt(i).Timestamp) {
+			t.Errorf("candle %d at %v, want %v", i, got.Timestamp, candleAt(i).Timestamp)
+		}
+	}
+
+	// A later poll sees the whole window again but only the new candle is sent
+	provider.SetCandles("bitcoin", Timeframe1h, []Candle{candleAt(1), candleAt(2)})
+	if got := receive(); !got.Timestamp.Equal(candleAt(2).Timestamp) {
+		t.Errorf("polled candle at %v, want %v", got.Timestamp, candleAt(2).Timestamp)
+	}
+
+	cancel()
+	select {
+	case candle, ok := <-ch:
+		if ok {
+			t.Errorf("received %v after cancel, want closed stream", candle.Timestamp)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed after cancel")
+	}
+}
+
+func TestPollStreamReturnsInitialError(t *testing.T) {
+	provider := NewMemoryProvider(nil)
+	if _, err := pollStream(context.Background(), provider, "bitcoin", Timeframe1h, time.Hour); err == nil {
+		t.Error("pollStream() for unknown symbol expected error")
+	}
+}
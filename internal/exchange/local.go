@@ -2,19 +2,23 @@ package exchange
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 // LocalFileProvider reads candle data from local CSV files
 type LocalFileProvider struct {
-	dataDir string
-	mu      sync.RWMutex
-	cache   map[string][]Candle // symbol_timeframe -> candles
+	dataDir      string
+	strictHeader bool
+	mu           sync.RWMutex
+	cache        map[string][]Candle // symbol_timeframe -> candles
 }
 
 // NewLocalFileProvider creates a provider that reads from local files
@@ -25,6 +29,15 @@ func NewLocalFileProvider(dataDir string) *LocalFileProvider {
 	}
 }
 
+// SetStrictHeader requires files to start with exactly
+// timestamp,open,high,low,close,volume instead of matching columns by name.
+// It applies to files loaded after the call; cached candles are kept.
+func (p *LocalFileProvider) SetStrictHeader(strict bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.strictHeader = strict
+}
+
 // GetCandles retrieves candles from CSV file
 func (p *LocalFileProvider) GetCandles(symbol string, timeframe Timeframe, limit int) ([]Candle, error) {
 	if !timeframe.IsValid() {
@@ -124,86 +137,216 @@ func (p *LocalFileProvider) loadFromFile(symbol string, timeframe Timeframe) ([]
 	filename := fmt.Sprintf("%s_%s.csv", symbol, timeframe)
 	filePath := filepath.Join(p.dataDir, filename)
 
+	p.mu.RLock()
+	strict := p.strictHeader
+	p.mu.RUnlock()
+
+	candles, rejected, err := readCandleCSV(filePath, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	// Malformed rows are skipped and impossible candles quarantined, but an
+	// unreadable timestamp means the file is not in the expected layout
+	invalid := 0
+	for _, row := range rejected {
+		if errors.Is(row.Err, errInvalidTimestamp) {
+			return nil, fmt.Errorf("invalid timestamp at line %d: %w", row.Line, row.Err)
+		}
+		if errors.Is(row.Err, ErrInvalidCandle) {
+			invalid++
+		}
+	}
+
+	if invalid > 0 {
+		log.Printf("Rejected %d invalid candles in %s", invalid, filename)
+	}
+
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no valid candles found in %s", filename)
+	}
+
+	return candles, nil
+}
+
+// errInvalidTimestamp marks rows whose timestamp is neither RFC 3339 nor a
+// Unix epoch
+var errInvalidTimestamp = errors.New("invalid timestamp")
+
+// RowError describes a CSV data row that could not be loaded
+type RowError struct {
+	Line int // 1-based line number in the file, counting the header
+	Err  error
+}
+
+// csvColumns lists the candle columns in field order, each with the header
+// names accepted for it. The first name is the canonical one.
+var csvColumns = [][]string{
+	{"timestamp", "date", "time", "datetime"},
+	{"open"},
+	{"high"},
+	{"low"},
+	{"close"},
+	{"volume", "vol"},
+}
+
+// mapCSVHeader returns the index of each candle column within header.
+// Names match case-insensitively, in any order and alongside extra columns;
+// strict instead requires exactly the canonical header.
+func mapCSVHeader(header []string, strict bool) ([]int, error) {
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	indexes := make([]int, len(csvColumns))
+	if strict {
+		for i, names := range csvColumns {
+			if len(header) != len(csvColumns) || header[i] != names[0] {
+				return nil, errors.New("header must be timestamp,open,high,low,close,volume")
+			}
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	var missing []string
+	for i, names := range csvColumns {
+		indexes[i] = -1
+		for f, field := range header {
+			if containsFold(names, strings.TrimSpace(field)) {
+				indexes[i] = f
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			missing = append(missing, names[0])
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	return indexes, nil
+}
+
+// containsFold reports whether names contains name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCandleCSV parses a candle CSV file, returning the loadable candles and
+// every data row that was skipped. Columns are matched by name unless
+// strict is set; see mapCSVHeader.
+func readCandleCSV(filePath string, strict bool) ([]Candle, []RowError, error) {
+	filename := filepath.Base(filePath)
+
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open %s: %w", filename, err)
+		return nil, nil, fmt.Errorf("failed to open %s: %w", filename, err)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
-	
+	reader.FieldsPerRecord = -1
+
 	// Read header
 	header, err := reader.Read()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
 	}
 
-	// Validate header
-	expectedHeader := []string{"timestamp", "open", "high", "low", "close", "volume"}
-	if len(header) != len(expectedHeader) {
-		return nil, fmt.Errorf("invalid CSV header in %s", filename)
+	columns, err := mapCSVHeader(header, strict)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV header in %s: %w", filename, err)
 	}
 
 	// Read all records
 	records, err := reader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV records: %w", err)
+		return nil, nil, fmt.Errorf("failed to read CSV records: %w", err)
 	}
 
 	// Parse candles
 	candles := make([]Candle, 0, len(records))
+	var rejected []RowError
 	for i, record := range records {
-		if len(record) != 6 {
-			continue // Skip malformed records
+		line := i + 2
+		if len(record) != len(header) {
+			rejected = append(rejected, RowError{Line: line, Err: fmt.Errorf("expected %d fields, got %d", len(header), len(record))})
+			continue
 		}
 
 		// Parse timestamp
-		timestamp, err := time.Parse(time.RFC3339, record[0])
+		timestamp, err := parseCandleTimestamp(record[columns[0]])
 		if err != nil {
-			return nil, fmt.Errorf("invalid timestamp at line %d: %w", i+2, err)
+			rejected = append(rejected, RowError{Line: line, Err: err})
+			continue
 		}
 
 		// Parse OHLCV
-		open, err := strconv.ParseFloat(record[1], 64)
-		if err != nil {
-			continue
+		var values [5]float64
+		parsed := true
+		for f, names := range csvColumns[1:] {
+			values[f], err = strconv.ParseFloat(record[columns[f+1]], 64)
+			if err != nil {
+				rejected = append(rejected, RowError{Line: line, Err: fmt.Errorf("invalid %s: %v", names[0], err)})
+				parsed = false
+				break
+			}
 		}
-
-		high, err := strconv.ParseFloat(record[2], 64)
-		if err != nil {
+		if !parsed {
 			continue
 		}
 
-		low, err := strconv.ParseFloat(record[3], 64)
-		if err != nil {
-			continue
+		candle := Candle{
+			Timestamp: timestamp,
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
 		}
 
-		close, err := strconv.ParseFloat(record[4], 64)
-		if err != nil {
+		// Quarantine candles with impossible values
+		if err := candle.Validate(); err != nil {
+			rejected = append(rejected, RowError{Line: line, Err: err})
 			continue
 		}
 
-		volume, err := strconv.ParseFloat(record[5], 64)
+		candles = append(candles, candle)
+	}
+
+	return candles, rejected, nil
+}
+
+// parseCandleTimestamp parses an RFC 3339 timestamp or an integer Unix
+// epoch. Epochs are read as seconds, milliseconds or microseconds by
+// magnitude, as found in raw exchange exports, and returned in UTC.
+func parseCandleTimestamp(value string) (time.Time, error) {
+	epoch, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		timestamp, err := time.Parse(time.RFC3339, value)
 		if err != nil {
-			continue
+			return time.Time{}, fmt.Errorf("%w: %v", errInvalidTimestamp, err)
 		}
-
-		candles = append(candles, Candle{
-			Timestamp: timestamp,
-			Open:      open,
-			High:      high,
-			Low:       low,
-			Close:     close,
-			Volume:    volume,
-		})
+		return timestamp, nil
 	}
 
-	if len(candles) == 0 {
-		return nil, fmt.Errorf("no valid candles found in %s", filename)
+	switch {
+	case epoch < 0:
+		return time.Time{}, fmt.Errorf("%w: negative epoch %d", errInvalidTimestamp, epoch)
+	case epoch >= 1e15:
+		return time.UnixMicro(epoch).UTC(), nil
+	case epoch >= 1e12:
+		return time.UnixMilli(epoch).UTC(), nil
+	default:
+		return time.Unix(epoch, 0).UTC(), nil
 	}
-
-	return candles, nil
 }
 
 // limitCandles returns the last N candles (most recent)
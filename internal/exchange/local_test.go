@@ -0,0 +1,197 @@
+package exchange
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCSV writes a candle file into dir and returns dir
+func writeCSV(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestLocalFileProviderQuarantinesInvalidCandles(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV(t, dir, "bitcoin_1d.csv", `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,110,95,105,1000
+2024-01-02T00:00:00Z,105,112,100,0,1000
+2024-01-03T00:00:00Z,NaN,112,100,108,1000
+2024-01-04T00:00:00Z,108,115,-1,110,1000
+2024-01-05T00:00:00Z,110,108,112,109,1000
+2024-01-06T00:00:00Z,110,120,108,118,1000
+`)
+
+	provider := NewLocalFileProvider(dir)
+	candles, err := provider.GetCandles("bitcoin", Timeframe1d, 0)
+	if err != nil {
+		t.Fatalf("GetCandles() error: %v", err)
+	}
+
+	if len(candles) != 2 {
+		t.Fatalf("loaded %d candles, want 2 valid candles", len(candles))
+	}
+	if candles[0].Close != 105 || candles[1].Close != 118 {
+		t.Errorf("unexpected candles kept: %+v", candles)
+	}
+}
+
+func TestLocalFileProviderRejectsAllInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV(t, dir, "bitcoin_1d.csv", `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,0,0,0,0,0
+`)
+
+	provider := NewLocalFileProvider(dir)
+	if _, err := provider.GetCandles("bitcoin", Timeframe1d, 0); err == nil {
+		t.Error("GetCandles() expected error when every candle is invalid")
+	}
+}
+
+func TestLoadWithGapCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV(t, dir, "bitcoin_1d.csv", `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,110,95,105,1000
+2024-01-02T00:00:00Z,105,112,100,108,1000
+2024-01-04T00:00:00Z,108,115,105,110,1000
+2024-01-05T00:00:00Z,110,118,108,115,1000
+2024-01-09T00:00:00Z,115,120,110,118,1000
+`)
+
+	provider := NewLocalFileProvider(dir)
+	candles, gaps, err := provider.LoadWithGapCheck("bitcoin", Timeframe1d, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("LoadWithGapCheck() error: %v", err)
+	}
+	if len(candles) != 5 {
+		t.Fatalf("loaded %d candles, want 5", len(candles))
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	want := []Gap{
+		{Start: day(3), End: day(3), Missing: 1},
+		{Start: day(6), End: day(8), Missing: 3},
+	}
+	if len(gaps) != len(want) {
+		t.Fatalf("gaps = %+v, want %+v", gaps, want)
+	}
+	for i := range want {
+		if !gaps[i].Start.Equal(want[i].Start) || !gaps[i].End.Equal(want[i].End) || gaps[i].Missing != want[i].Missing {
+			t.Errorf("gap %d = %+v, want %+v", i, gaps[i], want[i])
+		}
+	}
+
+	// Zero interval falls back to the timeframe duration
+	if _, gaps, _ := provider.LoadWithGapCheck("bitcoin", Timeframe1d, 0); len(gaps) != 2 {
+		t.Errorf("gaps with default interval = %d, want 2", len(gaps))
+	}
+}
+
+func TestLocalFileProviderMapsHeaderColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "capitalized",
+			content: `Date,Open,High,Low,Close,Volume
+2024-01-01T00:00:00Z,100,110,95,105,1000
+`,
+		},
+		{
+			name: "reordered with aliases and extra columns",
+			content: `close, VOL ,Time,low,Adj Close,high,open
+105,1000,2024-01-01T00:00:00Z,95,104,110,100
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeCSV(t, dir, "bitcoin_1d.csv", tt.content)
+
+			candles, err := NewLocalFileProvider(dir).GetCandles("bitcoin", Timeframe1d, 0)
+			if err != nil {
+				t.Fatalf("GetCandles() error: %v", err)
+			}
+
+			want := Candle{
+				Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Open:      100,
+				High:      110,
+				Low:       95,
+				Close:     105,
+				Volume:    1000,
+			}
+			if len(candles) != 1 || candles[0] != want {
+				t.Errorf("candles = %+v, want [%+v]", candles, want)
+			}
+		})
+	}
+}
+
+func TestLocalFileProviderHeaderErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV(t, dir, "bitcoin_1d.csv", `Date,Open,Close
+2024-01-01T00:00:00Z,100,105
+`)
+	writeCSV(t, dir, "ethereum_1d.csv", `Date,Open,High,Low,Close,Volume
+2024-01-01T00:00:00Z,100,110,95,105,1000
+`)
+
+	_, err := NewLocalFileProvider(dir).GetCandles("bitcoin", Timeframe1d, 0)
+	if err == nil || !strings.Contains(err.Error(), "missing required columns: high, low, volume") {
+		t.Errorf("GetCandles() error = %v, want the missing columns listed", err)
+	}
+
+	strict := NewLocalFileProvider(dir)
+	strict.SetStrictHeader(true)
+	if _, err := strict.GetCandles("ethereum", Timeframe1d, 0); err == nil {
+		t.Error("GetCandles() with a strict header expected an error for a capitalized header")
+	}
+}
+
+func TestLocalFileProviderParsesEpochTimestamps(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV(t, dir, "bitcoin_1h.csv", `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,110,95,105,1000
+1704070800,105,112,100,108,1000
+1704074400000,108,115,105,110,1000
+1704078000000000,110,118,108,115,1000
+-1,110,118,108,115,1000
+`)
+
+	provider := NewLocalFileProvider(dir)
+	if _, err := provider.GetCandles("bitcoin", Timeframe1h, 0); err == nil {
+		t.Fatal("GetCandles() expected an error for a negative epoch")
+	}
+
+	writeCSV(t, dir, "ethereum_1h.csv", `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,110,95,105,1000
+1704070800,105,112,100,108,1000
+1704074400000,108,115,105,110,1000
+1704078000000000,110,118,108,115,1000
+`)
+
+	candles, err := provider.GetCandles("ethereum", Timeframe1h, 0)
+	if err != nil {
+		t.Fatalf("GetCandles() error: %v", err)
+	}
+	if len(candles) != 4 {
+		t.Fatalf("loaded %d candles, want 4", len(candles))
+	}
+
+	// RFC 3339, seconds, milliseconds and microseconds for consecutive hours
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, c := range candles {
+		if want := start.Add(time.Duration(i) * time.Hour); !c.Timestamp.Equal(want) {
+			t.Errorf("candle %d timestamp = %s, want %s", i, c.Timestamp, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryProvider serves candles from an in-memory map, for tests and
+// synthetic runs that should not touch disk or network
+type MemoryProvider struct {
+	mu   sync.RWMutex
+	data map[string][]Candle
+}
+
+// NewMemoryProvider creates a provider from candles keyed by
+// symbol_timeframe (e.g. "bitcoin_1h"), each sorted oldest first
+func NewMemoryProvider(data map[string][]Candle) *MemoryProvider {
+	copied := make(map[string][]Candle, len(data))
+	for key, candles := range data {
+		copied[key] = candles
+	}
+	return &MemoryProvider{data: copied}
+}
+
+// SetCandles replaces the candles for a symbol and timeframe
+func (p *MemoryProvider) SetCandles(symbol string, timeframe Timeframe, candles []Candle) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.data[cacheKey(symbol, timeframe)] = candles
+}
+
+// GetCandles returns the last limit candles, or all when limit is zero
+func (p *MemoryProvider) GetCandles(symbol string, timeframe Timeframe, limit int) ([]Candle, error) {
+	if !timeframe.IsValid() {
+		return nil, fmt.Errorf("unsupported timeframe: %s", timeframe)
+	}
+
+	p.mu.RLock()
+	candles, ok := p.data[cacheKey(symbol, timeframe)]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no candles for %s %s", symbol, timeframe)
+	}
+
+	return limitLive(candles, limit), nil
+}
+
+// StreamCandles streams all candles and closes the channel once drained
+func (p *MemoryProvider) StreamCandles(symbol string, timeframe Timeframe) (<-chan Candle, error) {
+	return streamSnapshot(p, symbol, timeframe)
+}
+
+// GetSupportedTimeframes returns the timeframes present in the data
+func (p *MemoryProvider) GetSupportedTimeframes() []Timeframe {
+	seen := make(map[Timeframe]bool)
+	for _, key := range p.keys() {
+		if _, timeframe, ok := splitKey(key); ok {
+			seen[timeframe] = true
+		}
+	}
+
+	result := make([]Timeframe, 0, len(seen))
+	for timeframe := range seen {
+		result = append(result, timeframe)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ToMinutes() < result[j].ToMinutes() })
+	return result
+}
+
+// GetSupportedSymbols returns the symbols present in the data
+func (p *MemoryProvider) GetSupportedSymbols() []string {
+	seen := make(map[string]bool)
+	for _, key := range p.keys() {
+		if symbol, _, ok := splitKey(key); ok {
+			seen[symbol] = true
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for symbol := range seen {
+		result = append(result, symbol)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// keys returns the data keys
+func (p *MemoryProvider) keys() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	keys := make([]string, 0, len(p.data))
+	for key := range p.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// splitKey splits a symbol_timeframe key at its last underscore
+func splitKey(key string) (string, Timeframe, bool) {
+	i := strings.LastIndex(key, "_")
+	if i <= 0 {
+		return "", "", false
+	}
+	timeframe := Timeframe(key[i+1:])
+	if !timeframe.IsValid() {
+		return "", "", false
+	}
+	return key[:i], timeframe, true
+}
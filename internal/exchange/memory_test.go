@@ -0,0 +1,91 @@
+package exchange
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func memoryCandles(n int) []Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]Candle, n)
+	for i := range candles {
+		price := float64(100 + i)
+		candles[i] = Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price + 1,
+			Low:       price - 1,
+			Close:     price,
+			Volume:    10,
+		}
+	}
+	return candles
+}
+
+func TestMemoryProviderGetCandles(t *testing.T) {
+	p := NewMemoryProvider(map[string][]Candle{
+		"bitcoin_1h":     memoryCandles(5),
+		"wrapped_btc_4h": memoryCandles(2),
+	})
+
+	all, err := p.GetCandles("bitcoin", Timeframe1h, 0)
+	if err != nil {
+		t.Fatalf("GetCandles() error: %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("len = %d, want 5", len(all))
+	}
+
+	last, err := p.GetCandles("bitcoin", Timeframe1h, 2)
+	if err != nil {
+		t.Fatalf("GetCandles() error: %v", err)
+	}
+	if len(last) != 2 || last[0].Close != 103 || last[1].Close != 104 {
+		t.Errorf("limit 2 = %+v, want the closes 103 and 104", last)
+	}
+
+	if over, _ := p.GetCandles("bitcoin", Timeframe1h, 50); len(over) != 5 {
+		t.Errorf("limit above length returned %d candles, want 5", len(over))
+	}
+	if _, err := p.GetCandles("ethereum", Timeframe1h, 0); err == nil {
+		t.Error("expected error for missing symbol")
+	}
+	if _, err := p.GetCandles("bitcoin", Timeframe("2h"), 0); err == nil {
+		t.Error("expected error for invalid timeframe")
+	}
+
+	if got := p.GetSupportedSymbols(); !reflect.DeepEqual(got, []string{"bitcoin", "wrapped_btc"}) {
+		t.Errorf("symbols = %v, want [bitcoin wrapped_btc]", got)
+	}
+	if got := p.GetSupportedTimeframes(); !reflect.DeepEqual(got, []Timeframe{Timeframe1h, Timeframe4h}) {
+		t.Errorf("timeframes = %v, want [1h 4h]", got)
+	}
+}
+
+func TestMemoryProviderStreamCandles(t *testing.T) {
+	p := NewMemoryProvider(nil)
+	p.SetCandles("bitcoin", Timeframe1h, memoryCandles(3))
+
+	ch, err := p.StreamCandles("bitcoin", Timeframe1h)
+	if err != nil {
+		t.Fatalf("StreamCandles() error: %v", err)
+	}
+
+	var closes []float64
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case candle, ok := <-ch:
+			if !ok {
+				if !reflect.DeepEqual(closes, []float64{100, 101, 102}) {
+					t.Errorf("streamed closes = %v, want [100 101 102]", closes)
+				}
+				return
+			}
+			closes = append(closes, candle.Close)
+		case <-timeout:
+			t.Fatal("stream channel was not closed after draining")
+		}
+	}
+}
@@ -2,6 +2,8 @@ package exchange
 
 import (
 	"time"
+
+	"candlecore/internal/engine"
 )
 
 // Timeframe represents a candle interval
@@ -26,6 +28,42 @@ type Candle struct {
 	Volume    float64
 }
 
+// ErrInvalidCandle is returned when a candle carries impossible values. It
+// is engine.ErrInvalidCandle, so errors.Is matches either name.
+var ErrInvalidCandle = engine.ErrInvalidCandle
+
+// Validate checks that the candle has finite, positive prices, a
+// non-negative volume, and open/close within the high/low range.
+// The checks are engine.Candle.Validate's.
+func (c Candle) Validate() error {
+	return engine.Candle{
+		Timestamp: c.Timestamp,
+		Open:      c.Open,
+		High:      c.High,
+		Low:       c.Low,
+		Close:     c.Close,
+		Volume:    c.Volume,
+	}.Validate()
+}
+
+// ToEngineCandles converts candles for the backtest engine, tagging each
+// with symbol
+func ToEngineCandles(candles []Candle, symbol string) []engine.Candle {
+	result := make([]engine.Candle, len(candles))
+	for i, c := range candles {
+		result[i] = engine.Candle{
+			Symbol:    symbol,
+			Timestamp: c.Timestamp,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	return result
+}
+
 // DataProvider defines the interface for candle data sources
 type DataProvider interface {
 	// GetCandles retrieves candles for a symbol and timeframe
@@ -1,8 +1,11 @@
 package exchange
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"candlecore/internal/engine"
 )
 
 func TestTimeframe(t *testing.T) {
@@ -71,3 +74,28 @@ func TestCandle(t *testing.T) {
 		t.Errorf("Volume = %f, want 1000.0", candle.Volume)
 	}
 }
+
+func TestCandleValidate(t *testing.T) {
+	valid := Candle{Timestamp: time.Now(), Open: 100, High: 110, Low: 95, Close: 105, Volume: 1000}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error on valid candle: %v", err)
+	}
+
+	invalid := valid
+	invalid.High = 90
+	err := invalid.Validate()
+	if !errors.Is(err, ErrInvalidCandle) || !errors.Is(err, engine.ErrInvalidCandle) {
+		t.Errorf("Validate() = %v, want ErrInvalidCandle from both packages", err)
+	}
+}
+
+func TestToEngineCandles(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := []Candle{{Timestamp: ts, Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10}}
+
+	got := ToEngineCandles(candles, "bitcoin")
+	want := engine.Candle{Symbol: "bitcoin", Timestamp: ts, Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("ToEngineCandles() = %+v, want [%+v]", got, want)
+	}
+}
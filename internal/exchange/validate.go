@@ -0,0 +1,111 @@
+package exchange
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileReport summarizes the contents and problems of a candle CSV file
+type FileReport struct {
+	Path     string
+	Candles  int
+	First    time.Time
+	Last     time.Time
+	Interval time.Duration // Expected spacing between consecutive candles
+	Gaps     []Gap
+	Rejected []RowError // Rows that could not be parsed or failed validation
+	Unsorted []int      // Lines whose timestamp does not come after the previous row's
+}
+
+// MissingCandles returns the total number of candles missing across all gaps
+func (r *FileReport) MissingCandles() int {
+	missing := 0
+	for _, gap := range r.Gaps {
+		missing += gap.Missing
+	}
+	return missing
+}
+
+// OK reports whether the file loaded without rejected rows, ordering
+// problems or gaps
+func (r *FileReport) OK() bool {
+	return r.Candles > 0 && len(r.Rejected) == 0 && len(r.Unsorted) == 0 && len(r.Gaps) == 0
+}
+
+// InspectFile loads a candle CSV file the way LocalFileProvider does and
+// reports its date range, detected interval, gaps and every row that was
+// skipped. The interval comes from a {symbol}_{timeframe}.csv file name
+// when present, otherwise it is the most common spacing between candles.
+func InspectFile(path string) (*FileReport, error) {
+	candles, rejected, err := readCandleCSV(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FileReport{
+		Path:     path,
+		Candles:  len(candles),
+		Rejected: rejected,
+	}
+	if len(candles) == 0 {
+		return report, nil
+	}
+	report.First = candles[0].Timestamp
+	report.Last = candles[len(candles)-1].Timestamp
+
+	// Map candles back to file lines to locate ordering problems
+	lines := make([]int, 0, len(candles))
+	skipped := make(map[int]bool, len(rejected))
+	for _, row := range rejected {
+		skipped[row.Line] = true
+	}
+	for line := 2; len(lines) < len(candles); line++ {
+		if !skipped[line] {
+			lines = append(lines, line)
+		}
+	}
+
+	counts := make(map[time.Duration]int)
+	for i := 1; i < len(candles); i++ {
+		spacing := candles[i].Timestamp.Sub(candles[i-1].Timestamp)
+		if spacing <= 0 {
+			report.Unsorted = append(report.Unsorted, lines[i])
+			continue
+		}
+		counts[spacing]++
+	}
+
+	report.Interval = timeframeFromFilename(path).ToDuration()
+	if report.Interval == 0 {
+		// Most common spacing, preferring the shorter one on ties
+		for spacing, n := range counts {
+			best := counts[report.Interval]
+			if n > best || (n == best && spacing < report.Interval) {
+				report.Interval = spacing
+			}
+		}
+	}
+
+	// Gaps are only meaningful for a time-ordered series
+	if len(report.Unsorted) == 0 {
+		report.Gaps = DetectGaps(candles, report.Interval)
+	}
+
+	return report, nil
+}
+
+// timeframeFromFilename extracts the timeframe from a {symbol}_{timeframe}.csv
+// file name, returning "" when the name does not follow that layout
+func timeframeFromFilename(path string) Timeframe {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	i := strings.LastIndex(name, "_")
+	if i < 0 {
+		return ""
+	}
+	timeframe := Timeframe(name[i+1:])
+	if !timeframe.IsValid() {
+		return ""
+	}
+	return timeframe
+}
@@ -0,0 +1,33 @@
+package exchange
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInspectFileDetectsSpacingAndOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV(t, dir, "export.csv", `timestamp,open,high,low,close,volume
+2024-01-01T00:00:00Z,100,105,95,102,10
+2024-01-01T00:15:00Z,102,106,101,104,12
+2024-01-01T00:30:00Z,104,107,103,106,9
+2024-01-01T00:30:00Z,104,107,103,106,9
+2024-01-01T01:15:00Z,106,108,104,107,11
+`)
+
+	r, err := InspectFile(filepath.Join(dir, "export.csv"))
+	if err != nil {
+		t.Fatalf("InspectFile() error: %v", err)
+	}
+
+	if r.Interval != 15*time.Minute {
+		t.Errorf("Interval = %s, want the most common spacing 15m", r.Interval)
+	}
+	if len(r.Unsorted) != 1 || r.Unsorted[0] != 5 {
+		t.Errorf("Unsorted = %v, want line 5", r.Unsorted)
+	}
+	if r.OK() {
+		t.Error("OK() = true for a file with a duplicate timestamp")
+	}
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+// csvHeader is the column order written by ExportTradesCSV
+var csvHeader = []string{
+	"id", "symbol", "side", "entry_price", "exit_price", "quantity",
+	"pnl", "fee", "net_pnl", "opened_at", "closed_at",
+}
+
+// ExportTradesCSV writes one row per trade with a header row. Times are
+// RFC 3339 in UTC.
+func ExportTradesCSV(w io.Writer, trades []*engine.Trade) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, t := range trades {
+		row := []string{
+			t.ID,
+			t.Symbol,
+			string(t.Side),
+			formatFloat(t.EntryPrice),
+			formatFloat(t.ExitPrice),
+			formatFloat(t.Quantity),
+			formatFloat(t.PnL),
+			formatFloat(t.Fee),
+			formatFloat(t.NetPnL),
+			t.OpenedAt.UTC().Format(time.RFC3339),
+			t.ClosedAt.UTC().Format(time.RFC3339),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportTradesJSON writes the trades as an indented JSON array
+func ExportTradesJSON(w io.Writer, trades []*engine.Trade) error {
+	if trades == nil {
+		trades = []*engine.Trade{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(trades)
+}
+
+// WriteFile exports trades to path, choosing CSV or JSON by extension
+func WriteFile(path string, trades []*engine.Trade) error {
+	var export func(io.Writer, []*engine.Trade) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		export = ExportTradesCSV
+	case ".json":
+		export = ExportTradesJSON
+	default:
+		return fmt.Errorf("unsupported export format %q: use .csv or .json", filepath.Ext(path))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	if err := export(file, trades); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to export trades: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close export file: %w", err)
+	}
+
+	return nil
+}
+
+// formatFloat writes the shortest representation that round-trips
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+func sampleTrades() []*engine.Trade {
+	opened := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*engine.Trade{
+		{
+			ID: "t1", Symbol: "BTC/USD", Side: engine.OrderSideBuy,
+			EntryPrice: 100, ExitPrice: 110, Quantity: 0.5,
+			PnL: 5, Fee: 0.1, NetPnL: 4.9,
+			OpenedAt: opened, ClosedAt: opened.Add(4 * time.Hour),
+			Reason: "take-profit hit",
+		},
+		{
+			ID: "t2", Symbol: "BTC/USD", Side: engine.OrderSideBuy,
+			EntryPrice: 110, ExitPrice: 104.5, Quantity: 0.25,
+			PnL: -1.375, Fee: 0.05, NetPnL: -1.425,
+			OpenedAt: opened.Add(8 * time.Hour), ClosedAt: opened.Add(12 * time.Hour),
+		},
+	}
+}
+
+func TestExportTradesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportTradesCSV(&buf, sampleTrades()); err != nil {
+		t.Fatalf("ExportTradesCSV() error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want header plus 2 trades", len(rows))
+	}
+
+	wantHeader := []string{"id", "symbol", "side", "entry_price", "exit_price", "quantity",
+		"pnl", "fee", "net_pnl", "opened_at", "closed_at"}
+	if !reflect.DeepEqual(rows[0], wantHeader) {
+		t.Errorf("header = %v, want %v", rows[0], wantHeader)
+	}
+
+	wantRow := []string{"t1", "BTC/USD", "buy", "100", "110", "0.5", "5", "0.1", "4.9",
+		"2024-01-01T00:00:00Z", "2024-01-01T04:00:00Z"}
+	if !reflect.DeepEqual(rows[1], wantRow) {
+		t.Errorf("row = %v, want %v", rows[1], wantRow)
+	}
+}
+
+func TestExportTradesJSONRoundTrip(t *testing.T) {
+	trades := sampleTrades()
+
+	var buf bytes.Buffer
+	if err := ExportTradesJSON(&buf, trades); err != nil {
+		t.Fatalf("ExportTradesJSON() error: %v", err)
+	}
+
+	var decoded []*engine.Trade
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, trades) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", decoded, trades)
+	}
+
+	buf.Reset()
+	if err := ExportTradesJSON(&buf, nil); err != nil {
+		t.Fatalf("ExportTradesJSON(nil) error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty export = %q, want []", buf.String())
+	}
+}
+
+func TestWriteFileRejectsUnknownFormat(t *testing.T) {
+	if err := WriteFile(filepath.Join(t.TempDir(), "trades.xlsx"), sampleTrades()); err == nil {
+		t.Fatal("WriteFile() expected error for .xlsx")
+	}
+}
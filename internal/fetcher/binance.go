@@ -3,8 +3,10 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -17,12 +19,20 @@ const (
 	binanceBaseURL = "https://api.binance.com"
 	maxRetries     = 3
 	retryDelay     = time.Second * 2
+
+	// maxStreamBackfill caps how many missed candles a stream replays after
+	// an outage; older ones are skipped
+	maxStreamBackfill = 500
+
+	// binanceKlineLimit is the maximum number of klines Binance returns per request
+	binanceKlineLimit = 1000
 )
 
 // BinanceFetcher fetches live candle data from Binance public API
 type BinanceFetcher struct {
-	client  *http.Client
-	baseURL string
+	client     *http.Client
+	baseURL    string
+	retryDelay time.Duration
 }
 
 // NewBinanceFetcher creates a new Binance data fetcher
@@ -31,7 +41,8 @@ func NewBinanceFetcher() *BinanceFetcher {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		baseURL: binanceBaseURL,
+		baseURL:    binanceBaseURL,
+		retryDelay: retryDelay,
 	}
 }
 
@@ -67,7 +78,7 @@ func (f *BinanceFetcher) FetchCandles(ctx context.Context, symbol, interval stri
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
-			case <-time.After(retryDelay):
+			case <-time.After(f.retryDelay):
 				continue
 			}
 		}
@@ -77,16 +88,7 @@ func (f *BinanceFetcher) FetchCandles(ctx context.Context, symbol, interval stri
 		return nil, fmt.Errorf("failed to fetch candles after %d attempts: %w", maxRetries, err)
 	}
 
-	candles := make([]engine.Candle, 0, len(klines))
-	for _, k := range klines {
-		candle, err := f.parseKline(k)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse kline: %w", err)
-		}
-		candles = append(candles, candle)
-	}
-
-	return candles, nil
+	return f.parseKlines(klines, symbol)
 }
 
 // FetchLatestCandle fetches the most recent completed candle
@@ -105,11 +107,59 @@ func (f *BinanceFetcher) FetchLatestCandle(ctx context.Context, symbol, interval
 
 // FetchCandlesSince fetches candles since a specific timestamp
 func (f *BinanceFetcher) FetchCandlesSince(ctx context.Context, symbol, interval string, since time.Time) ([]engine.Candle, error) {
+	klines, err := f.fetchKlinesSince(ctx, symbol, interval, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.parseKlines(klines, symbol)
+}
+
+// FetchCandlesRange fetches every candle from start to now, paging through
+// binanceKlineLimit klines per request. wait, when not nil, is called
+// before each request so callers can share a rate limit. Paging follows the
+// raw klines returned, so candles rejected as invalid never end it early.
+func (f *BinanceFetcher) FetchCandlesRange(ctx context.Context, symbol, interval string, start time.Time, wait func(context.Context) error) ([]engine.Candle, error) {
+	var candles []engine.Candle
+	cursor := start
+	for {
+		if wait != nil {
+			if err := wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		klines, err := f.fetchKlinesSince(ctx, symbol, interval, cursor)
+		if err != nil {
+			return nil, err
+		}
+		batch, err := f.parseKlines(klines, symbol)
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, batch...)
+
+		if len(klines) < binanceKlineLimit {
+			return candles, nil
+		}
+
+		// Rejected klines still carry a valid open time
+		last, err := parseFloat(klines[len(klines)-1][klineOpenTime])
+		if err != nil {
+			return nil, fmt.Errorf("invalid open time format: %w", err)
+		}
+		cursor = time.UnixMilli(int64(last)).Add(time.Millisecond)
+	}
+}
+
+// fetchKlinesSince fetches up to binanceKlineLimit raw klines opening at or
+// after since
+func (f *BinanceFetcher) fetchKlinesSince(ctx context.Context, symbol, interval string, since time.Time) ([]binanceKline, error) {
 	params := url.Values{}
 	params.Add("symbol", symbol)
 	params.Add("interval", interval)
 	params.Add("startTime", strconv.FormatInt(since.UnixMilli(), 10))
-	params.Add("limit", "1000")
+	params.Add("limit", strconv.Itoa(binanceKlineLimit))
 
 	endpoint := fmt.Sprintf("%s/api/v3/klines?%s", f.baseURL, params.Encode())
 
@@ -126,7 +176,7 @@ func (f *BinanceFetcher) FetchCandlesSince(ctx context.Context, symbol, interval
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
-			case <-time.After(retryDelay):
+			case <-time.After(f.retryDelay):
 				continue
 			}
 		}
@@ -136,19 +186,14 @@ func (f *BinanceFetcher) FetchCandlesSince(ctx context.Context, symbol, interval
 		return nil, fmt.Errorf("failed to fetch candles: %w", err)
 	}
 
-	candles := make([]engine.Candle, 0, len(klines))
-	for _, k := range klines {
-		candle, err := f.parseKline(k)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse kline: %w", err)
-		}
-		candles = append(candles, candle)
-	}
-
-	return candles, nil
+	return klines, nil
 }
 
-// StreamCandles creates a channel that continuously fetches new candles
+// StreamCandles creates a channel that continuously fetches new candles.
+// Fetch failures are sent on the error channel. On the first successful
+// poll after a failure, candles that closed during the outage are fetched
+// with FetchCandlesSince and emitted in order before the latest candle, up
+// to the most recent maxStreamBackfill of them.
 func (f *BinanceFetcher) StreamCandles(ctx context.Context, symbol, interval string, pollInterval time.Duration) (<-chan engine.Candle, <-chan error) {
 	candleChan := make(chan engine.Candle, 10)
 	errChan := make(chan error, 1)
@@ -161,6 +206,25 @@ func (f *BinanceFetcher) StreamCandles(ctx context.Context, symbol, interval str
 		defer ticker.Stop()
 
 		var lastTimestamp time.Time
+		recovering := false
+
+		sendErr := func(err error) bool {
+			select {
+			case errChan <- err:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		sendCandle := func(candle engine.Candle) bool {
+			select {
+			case candleChan <- candle:
+				lastTimestamp = candle.Timestamp
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
 
 		for {
 			select {
@@ -169,13 +233,53 @@ func (f *BinanceFetcher) StreamCandles(ctx context.Context, symbol, interval str
 			case <-ticker.C:
 				candle, err := f.FetchLatestCandle(ctx, symbol, interval)
 				if err != nil {
-					errChan <- err
+					recovering = true
+					if !sendErr(err) {
+						return
+					}
 					continue
 				}
 
+				if recovering && !lastTimestamp.IsZero() {
+					// Start no earlier than the newest maxStreamBackfill candles
+					since := lastTimestamp.Add(time.Millisecond)
+					if d := intervalDuration(interval); d > 0 {
+						if earliest := candle.Timestamp.Add(-maxStreamBackfill * d); earliest.After(since) {
+							since = earliest
+						}
+					}
+
+					missed, err := f.FetchCandlesSince(ctx, symbol, interval, since)
+					if err != nil {
+						if !sendErr(fmt.Errorf("backfill after outage: %w", err)) {
+							return
+						}
+						continue
+					}
+
+					backfill := make([]engine.Candle, 0, len(missed))
+					for _, c := range missed {
+						if c.Timestamp.After(lastTimestamp) && c.Timestamp.Before(candle.Timestamp) {
+							backfill = append(backfill, c)
+						}
+					}
+					if len(backfill) > maxStreamBackfill {
+						log.Printf("Backfill for %s %s capped at %d of %d missed candles",
+							symbol, interval, maxStreamBackfill, len(backfill))
+						backfill = backfill[len(backfill)-maxStreamBackfill:]
+					}
+					for _, c := range backfill {
+						if !sendCandle(c) {
+							return
+						}
+					}
+				}
+				recovering = false
+
 				if candle.Timestamp.After(lastTimestamp) {
-					lastTimestamp = candle.Timestamp
-					candleChan <- *candle
+					if !sendCandle(*candle) {
+						return
+					}
 				}
 			}
 		}
@@ -212,50 +316,102 @@ func (f *BinanceFetcher) fetchWithRetry(ctx context.Context, endpoint string) ([
 	return klines, nil
 }
 
+// parseKlines converts a kline response into candles. Klines with an
+// unexpected structure fail the whole response; klines that parse but carry
+// impossible values are quarantined and reported.
+func (f *BinanceFetcher) parseKlines(klines []binanceKline, symbol string) ([]engine.Candle, error) {
+	candles := make([]engine.Candle, 0, len(klines))
+	rejected := 0
+	for _, k := range klines {
+		candle, err := f.parseKline(k)
+		if errors.Is(err, engine.ErrInvalidCandle) {
+			rejected++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse kline: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+
+	if rejected > 0 {
+		log.Printf("Rejected %d invalid Binance candles for %s", rejected, symbol)
+	}
+
+	return candles, nil
+}
+
+// Binance kline field positions. The REST API and the public data dumps
+// share this leading layout; newer responses append extra trailing fields
+// (close time, quote volume, trade count, taker volumes, ignore) which are
+// not needed to build a candle.
+const (
+	klineOpenTime = iota
+	klineOpen
+	klineHigh
+	klineLow
+	klineClose
+	klineVolume
+
+	klineMinFields
+)
+
 // parseKline converts Binance kline format to engine.Candle
+// Only the leading OHLCV fields are read, so trailing fields added by
+// Binance do not affect parsing. Candles that parse but carry impossible
+// values are rejected rather than returned as zero-value data.
 func (f *BinanceFetcher) parseKline(k binanceKline) (engine.Candle, error) {
-	if len(k) < 11 {
-		return engine.Candle{}, fmt.Errorf("invalid kline format: expected 11+ fields, got %d", len(k))
+	if len(k) < klineMinFields {
+		return engine.Candle{}, fmt.Errorf("invalid kline format: expected at least %d fields, got %d", klineMinFields, len(k))
 	}
 
-	openTime, ok := k[0].(float64)
-	if !ok {
-		return engine.Candle{}, fmt.Errorf("invalid open time format")
+	openTime, err := parseFloat(k[klineOpenTime])
+	if err != nil {
+		return engine.Candle{}, fmt.Errorf("invalid open time format: %w", err)
+	}
+	if openTime <= 0 {
+		return engine.Candle{}, fmt.Errorf("invalid open time: %.0f", openTime)
 	}
 
-	open, err := parseFloat(k[1])
+	open, err := parseFloat(k[klineOpen])
 	if err != nil {
 		return engine.Candle{}, fmt.Errorf("invalid open price: %w", err)
 	}
 
-	high, err := parseFloat(k[2])
+	high, err := parseFloat(k[klineHigh])
 	if err != nil {
 		return engine.Candle{}, fmt.Errorf("invalid high price: %w", err)
 	}
 
-	low, err := parseFloat(k[3])
+	low, err := parseFloat(k[klineLow])
 	if err != nil {
 		return engine.Candle{}, fmt.Errorf("invalid low price: %w", err)
 	}
 
-	close, err := parseFloat(k[4])
+	close, err := parseFloat(k[klineClose])
 	if err != nil {
 		return engine.Candle{}, fmt.Errorf("invalid close price: %w", err)
 	}
 
-	volume, err := parseFloat(k[5])
+	volume, err := parseFloat(k[klineVolume])
 	if err != nil {
 		return engine.Candle{}, fmt.Errorf("invalid volume: %w", err)
 	}
 
-	return engine.Candle{
+	candle := engine.Candle{
 		Timestamp: time.UnixMilli(int64(openTime)),
 		Open:      open,
 		High:      high,
 		Low:       low,
 		Close:     close,
 		Volume:    volume,
-	}, nil
+	}
+
+	if err := candle.Validate(); err != nil {
+		return engine.Candle{}, err
+	}
+
+	return candle, nil
 }
 
 // parseFloat safely converts interface{} to float64
@@ -270,13 +426,27 @@ func parseFloat(v interface{}) (float64, error) {
 	}
 }
 
-// ValidateSymbol checks if a symbol is supported
+// ValidateSymbol checks if a symbol is a supported Binance USDT pair
 func ValidateSymbol(symbol string) bool {
-	supportedSymbols := map[string]bool{
-		"BTCUSDT": true,
-		"ETHUSDT": true,
+	for _, c := range supportedCoins {
+		if c.ticker+"USDT" == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// intervalDuration returns the length of a kline interval, or 0 if unknown
+func intervalDuration(interval string) time.Duration {
+	durations := map[string]time.Duration{
+		"1m":  time.Minute,
+		"5m":  5 * time.Minute,
+		"15m": 15 * time.Minute,
+		"1h":  time.Hour,
+		"4h":  4 * time.Hour,
+		"1d":  24 * time.Hour,
 	}
-	return supportedSymbols[symbol]
+	return durations[interval]
 }
 
 // ValidateInterval checks if an interval is supported
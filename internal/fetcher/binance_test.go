@@ -0,0 +1,229 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+func TestParseKline(t *testing.T) {
+	f := NewBinanceFetcher()
+	openTime := float64(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli())
+
+	tests := []struct {
+		name    string
+		kline   binanceKline
+		wantErr bool
+	}{
+		{
+			name:  "standard 12 field response",
+			kline: binanceKline{openTime, "100.0", "110.0", "95.0", "105.0", "12.5", openTime + 59999, "1300.0", float64(42), "6.0", "630.0", "0"},
+		},
+		{
+			name:  "extra trailing fields",
+			kline: binanceKline{openTime, "100.0", "110.0", "95.0", "105.0", "12.5", openTime + 59999, "1300.0", float64(42), "6.0", "630.0", "0", "extra", float64(7)},
+		},
+		{
+			name:  "minimal OHLCV fields",
+			kline: binanceKline{openTime, "100.0", "110.0", "95.0", "105.0", "12.5"},
+		},
+		{
+			name:  "numeric price fields",
+			kline: binanceKline{openTime, 100.0, 110.0, 95.0, 105.0, 12.5},
+		},
+		{
+			name:    "too few fields",
+			kline:   binanceKline{openTime, "100.0", "110.0", "95.0", "105.0"},
+			wantErr: true,
+		},
+		{
+			name:    "zero close",
+			kline:   binanceKline{openTime, "100.0", "110.0", "95.0", "0", "12.5"},
+			wantErr: true,
+		},
+		{
+			name:    "high below low",
+			kline:   binanceKline{openTime, "100.0", "90.0", "95.0", "92.0", "12.5"},
+			wantErr: true,
+		},
+		{
+			name:    "NaN price",
+			kline:   binanceKline{openTime, "NaN", "110.0", "95.0", "105.0", "12.5"},
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric open time",
+			kline:   binanceKline{true, "100.0", "110.0", "95.0", "105.0", "12.5"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candle, err := f.parseKline(tt.kline)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseKline() expected error, got candle %+v", candle)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseKline() unexpected error: %v", err)
+			}
+			if !candle.Timestamp.Equal(time.UnixMilli(int64(openTime))) {
+				t.Errorf("Timestamp = %v, want %v", candle.Timestamp, time.UnixMilli(int64(openTime)))
+			}
+			if candle.Open != 100.0 || candle.High != 110.0 || candle.Low != 95.0 || candle.Close != 105.0 {
+				t.Errorf("OHLC = %.2f/%.2f/%.2f/%.2f, want 100/110/95/105", candle.Open, candle.High, candle.Low, candle.Close)
+			}
+			if candle.Volume != 12.5 {
+				t.Errorf("Volume = %f, want 12.5", candle.Volume)
+			}
+		})
+	}
+}
+
+// klineServer serves hourly klines up to a movable open candle and can
+// simulate an outage
+type klineServer struct {
+	mu    sync.Mutex
+	start time.Time
+	open  int  // index of the still-open candle
+	down  bool // respond with 500 while true
+}
+
+func (k *klineServer) set(open int, down bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.open, k.down = open, down
+}
+
+func (k *klineServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.down {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	first := k.open - 1
+	if startTime := r.URL.Query().Get("startTime"); startTime != "" {
+		ms, _ := strconv.ParseInt(startTime, 10, 64)
+		first = int(time.UnixMilli(ms).Sub(k.start) / time.Hour)
+		if time.UnixMilli(ms).After(k.start.Add(time.Duration(first) * time.Hour)) {
+			first++
+		}
+	}
+
+	var klines []binanceKline
+	for i := first; i <= k.open; i++ {
+		openTime := float64(k.start.Add(time.Duration(i) * time.Hour).UnixMilli())
+		price := strconv.Itoa(100 + i)
+		klines = append(klines, binanceKline{openTime, price, price, price, price, "1"})
+	}
+	json.NewEncoder(w).Encode(klines)
+}
+
+func TestStreamCandlesBackfillsAfterOutage(t *testing.T) {
+	klines := &klineServer{start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), open: 1}
+	server := httptest.NewServer(klines)
+	defer server.Close()
+
+	f := NewBinanceFetcher()
+	f.baseURL = server.URL
+	f.retryDelay = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	candleCh, errCh := f.StreamCandles(ctx, "BTCUSDT", "1h", 5*time.Millisecond)
+
+	next := func() engine.Candle {
+		t.Helper()
+		select {
+		case c := <-candleCh:
+			return c
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for a candle")
+		}
+		return engine.Candle{}
+	}
+
+	if c := next(); c.Close != 100 {
+		t.Fatalf("first candle close = %.0f, want 100", c.Close)
+	}
+
+	// Candles 1-3 close while the API is down
+	klines.set(1, true)
+	select {
+	case <-errCh:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the outage error")
+	}
+	go func() {
+		for range errCh {
+		}
+	}()
+	klines.set(5, false)
+
+	for _, want := range []float64{101, 102, 103, 104} {
+		if c := next(); c.Close != want {
+			t.Fatalf("candle close = %.0f, want %.0f", c.Close, want)
+		}
+	}
+}
+
+func TestFetchCandlesRangeSkipsInvalidKlines(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const total = 1500
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ms, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		first := int((time.UnixMilli(ms).Sub(start) + time.Hour - 1) / time.Hour)
+
+		klines := []binanceKline{}
+		for i := first; i < total && len(klines) < binanceKlineLimit; i++ {
+			openTime := float64(start.Add(time.Duration(i) * time.Hour).UnixMilli())
+			if i == 500 {
+				// High below low: rejected, leaving the full page one short
+				klines = append(klines, binanceKline{openTime, "100", "90", "110", "100", "1"})
+				continue
+			}
+			klines = append(klines, binanceKline{openTime, "100", "101", "99", "100", "1"})
+		}
+		json.NewEncoder(w).Encode(klines)
+	}))
+	defer server.Close()
+
+	f := NewBinanceFetcher()
+	f.baseURL = server.URL
+
+	waits := 0
+	wait := func(context.Context) error {
+		waits++
+		return nil
+	}
+
+	candles, err := f.FetchCandlesRange(context.Background(), "BTCUSDT", "1h", start, wait)
+	if err != nil {
+		t.Fatalf("FetchCandlesRange() error: %v", err)
+	}
+	if len(candles) != total-1 {
+		t.Errorf("candles = %d, want %d", len(candles), total-1)
+	}
+	if last := candles[len(candles)-1].Timestamp; !last.Equal(start.Add((total - 1) * time.Hour)) {
+		t.Errorf("last candle = %s, want the end of the range", last)
+	}
+	if requests != 2 || waits != 2 {
+		t.Errorf("requests = %d, waits = %d, want 2 each", requests, waits)
+	}
+}
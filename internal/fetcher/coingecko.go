@@ -3,10 +3,14 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
@@ -17,21 +21,34 @@ const (
 	coingeckoBaseURL = "https://api.coingecko.com/api/v3"
 	cgMaxRetries     = 3
 	cgRetryDelay     = time.Second * 3
+
+	// DefaultCoinGeckoRPM keeps requests within the free-tier rate limit
+	DefaultCoinGeckoRPM = 10
 )
 
 // CoinGeckoFetcher fetches live candle data from CoinGecko public API
 type CoinGeckoFetcher struct {
 	client  *http.Client
 	baseURL string
+	limiter *tokenBucket
 }
 
-// NewCoinGeckoFetcher creates a new CoinGecko data fetcher
+// NewCoinGeckoFetcher creates a new CoinGecko data fetcher limited to
+// DefaultCoinGeckoRPM requests per minute
 func NewCoinGeckoFetcher() *CoinGeckoFetcher {
+	return NewCoinGeckoFetcherWithRate(DefaultCoinGeckoRPM)
+}
+
+// NewCoinGeckoFetcherWithRate creates a CoinGecko fetcher that spaces its
+// requests to at most rpm per minute. All calls on the fetcher share the
+// limit. rpm <= 0 disables throttling.
+func NewCoinGeckoFetcherWithRate(rpm int) *CoinGeckoFetcher {
 	return &CoinGeckoFetcher{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 		baseURL: coingeckoBaseURL,
+		limiter: newTokenBucket(rpm),
 	}
 }
 
@@ -42,7 +59,17 @@ type coingeckoOHLC []float64
 // FetchCandles fetches historical OHLC data from CoinGecko
 // coinID: "bitcoin", "ethereum"
 // days: number of days of historical data (1, 7, 14, 30, 90, 180, 365, max)
+// The OHLC endpoint coarsens with the range (4-day candles beyond 90 days),
+// so when a multi-day request comes back coarser than daily the candles
+// are rebuilt per day from the market_chart price series instead.
 func (f *CoinGeckoFetcher) FetchCandles(ctx context.Context, coinID string, days int) ([]engine.Candle, error) {
+	candles, _, err := f.fetchCandles(ctx, coinID, days)
+	return candles, err
+}
+
+// fetchCandles implements FetchCandles and also returns the market_chart
+// response when the daily fallback fetched one, so callers can reuse it
+func (f *CoinGeckoFetcher) fetchCandles(ctx context.Context, coinID string, days int) ([]engine.Candle, *coingeckoMarketChart, error) {
 	params := url.Values{}
 	params.Add("vs_currency", "usd")
 	params.Add("days", strconv.Itoa(days))
@@ -61,33 +88,238 @@ func (f *CoinGeckoFetcher) FetchCandles(ctx context.Context, coinID string, days
 		if attempt < cgMaxRetries-1 {
 			select {
 			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(cgRetryDelay):
+				return nil, nil, ctx.Err()
+			case <-time.After(cgRetryDelayFor(err)):
 				continue
 			}
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch candles after %d attempts: %w", cgMaxRetries, err)
+		return nil, nil, fmt.Errorf("failed to fetch candles after %d attempts: %w", cgMaxRetries, err)
 	}
 
 	candles := make([]engine.Candle, 0, len(ohlcData))
+	rejected := 0
 	for _, ohlc := range ohlcData {
 		candle, err := f.parseOHLC(ohlc)
+		if errors.Is(err, engine.ErrInvalidCandle) {
+			rejected++
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse OHLC: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse OHLC: %w", err)
 		}
 		candles = append(candles, candle)
 	}
 
+	if rejected > 0 {
+		log.Printf("Rejected %d invalid CoinGecko candles for %s", rejected, coinID)
+	}
+
 	if len(candles) == 0 {
-		return nil, fmt.Errorf("no candle data returned from CoinGecko")
+		return nil, nil, fmt.Errorf("no candle data returned from CoinGecko")
+	}
+
+	if days > 1 && (len(candles) < 2 || candleSpacing(candles) > 24*time.Hour) {
+		log.Printf("CoinGecko OHLC for %s over %d days returned %d candles coarser than daily, falling back to market_chart",
+			coinID, days, len(candles))
+		chart, err := f.fetchMarketChart(ctx, coinID, days)
+		if err != nil {
+			return nil, nil, err
+		}
+		candles, err := dailyCandles(chart)
+		if err != nil {
+			return nil, nil, err
+		}
+		return candles, chart, nil
+	}
+
+	return candles, nil, nil
+}
+
+// dailyCandles builds one candle per UTC day from the market_chart price
+// samples. Each day opens at the previous day's last sample, closes at its
+// own last sample and spans both along with the samples in between, so the
+// one-sample-per-day series CoinGecko returns beyond 90 days still yields
+// real ranges. The first day opens at its first sample. Volume is the
+// day's last rolling 24h volume sample.
+func dailyCandles(chart *coingeckoMarketChart) ([]engine.Candle, error) {
+	var candles []engine.Candle
+	for _, sample := range chart.Prices {
+		if len(sample) < 2 || sample[1] <= 0 {
+			continue
+		}
+		at := time.UnixMilli(int64(sample[0])).UTC()
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+		price := sample[1]
+
+		if n := len(candles); n > 0 && candles[n-1].Timestamp.Equal(day) {
+			last := &candles[n-1]
+			last.High = math.Max(last.High, price)
+			last.Low = math.Min(last.Low, price)
+			last.Close = price
+			continue
+		}
+
+		open := price
+		if n := len(candles); n > 0 {
+			open = candles[n-1].Close
+		}
+		candles = append(candles, engine.Candle{
+			Timestamp: day,
+			Open:      open,
+			High:      math.Max(open, price),
+			Low:       math.Min(open, price),
+			Close:     price,
+		})
+	}
+
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no price data returned from CoinGecko market_chart")
+	}
+
+	for _, sample := range chart.TotalVolumes {
+		if len(sample) < 2 || sample[1] < 0 {
+			continue
+		}
+		at := time.UnixMilli(int64(sample[0])).UTC()
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+		i := sort.Search(len(candles), func(i int) bool { return !candles[i].Timestamp.Before(day) })
+		if i < len(candles) && candles[i].Timestamp.Equal(day) {
+			candles[i].Volume = sample[1]
+		}
 	}
 
 	return candles, nil
 }
 
+// coingeckoMarketChart represents the market_chart response; each series
+// entry is [timestamp_ms, value]
+type coingeckoMarketChart struct {
+	Prices       [][]float64 `json:"prices"`
+	MarketCaps   [][]float64 `json:"market_caps"`
+	TotalVolumes [][]float64 `json:"total_volumes"`
+}
+
+// FetchCandlesWithVolume fetches OHLC candles like FetchCandles and fills in
+// Volume from the market_chart endpoint. CoinGecko reports rolling 24h
+// volume, so each candle takes the sample nearest its timestamp, provided
+// it lies within one candle interval. Candles without a nearby sample keep
+// zero volume.
+func (f *CoinGeckoFetcher) FetchCandlesWithVolume(ctx context.Context, coinID string, days int) ([]engine.Candle, error) {
+	candles, chart, err := f.fetchCandles(ctx, coinID, days)
+	if err != nil {
+		return nil, err
+	}
+
+	// The daily fallback already fetched the chart
+	if chart == nil {
+		chart, err = f.fetchMarketChart(ctx, coinID, days)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mergeVolumes(candles, chart.TotalVolumes)
+	return candles, nil
+}
+
+// fetchMarketChart fetches the price, market cap and volume series over the
+// given number of days, retrying like FetchCandles
+func (f *CoinGeckoFetcher) fetchMarketChart(ctx context.Context, coinID string, days int) (*coingeckoMarketChart, error) {
+	params := url.Values{}
+	params.Add("vs_currency", "usd")
+	params.Add("days", strconv.Itoa(days))
+
+	endpoint := fmt.Sprintf("%s/coins/%s/market_chart?%s", f.baseURL, coinID, params.Encode())
+
+	var chart coingeckoMarketChart
+	var err error
+	for attempt := 0; attempt < cgMaxRetries; attempt++ {
+		err = f.getJSON(ctx, endpoint, &chart)
+		if err == nil {
+			break
+		}
+
+		if attempt < cgMaxRetries-1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(cgRetryDelayFor(err)):
+				continue
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market chart after %d attempts: %w", cgMaxRetries, err)
+	}
+
+	return &chart, nil
+}
+
+// mergeVolumes assigns each candle the nearest volume sample within one
+// candle interval. Both inputs must be sorted by time. Malformed samples
+// with fewer than two values are skipped.
+func mergeVolumes(candles []engine.Candle, samples [][]float64) {
+	if len(candles) == 0 {
+		return
+	}
+
+	volumes := make([][]float64, 0, len(samples))
+	for _, sample := range samples {
+		if len(sample) >= 2 {
+			volumes = append(volumes, sample)
+		}
+	}
+
+	interval := candleSpacing(candles)
+	j := 0
+	for i := range candles {
+		ts := candles[i].Timestamp
+
+		// Advance to the last sample at or before the candle
+		for j+1 < len(volumes) && !time.UnixMilli(int64(volumes[j+1][0])).After(ts) {
+			j++
+		}
+
+		best, bestDiff := -1, interval+1
+		for _, k := range []int{j, j + 1} {
+			if k >= len(volumes) {
+				continue
+			}
+			diff := time.UnixMilli(int64(volumes[k][0])).Sub(ts)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff <= interval && diff < bestDiff {
+				best, bestDiff = k, diff
+			}
+		}
+
+		if best >= 0 && volumes[best][1] >= 0 {
+			candles[i].Volume = volumes[best][1]
+		}
+	}
+}
+
+// candleSpacing returns the smallest gap between consecutive candles, or one
+// day for a single candle
+func candleSpacing(candles []engine.Candle) time.Duration {
+	spacing := time.Duration(0)
+	for i := 1; i < len(candles); i++ {
+		gap := candles[i].Timestamp.Sub(candles[i-1].Timestamp)
+		if gap > 0 && (spacing == 0 || gap < spacing) {
+			spacing = gap
+		}
+	}
+	if spacing == 0 {
+		spacing = 24 * time.Hour
+	}
+	return spacing
+}
+
 // FetchLatestCandles fetches recent candles (last 1 day)
 func (f *CoinGeckoFetcher) FetchLatestCandles(ctx context.Context, coinID string) ([]engine.Candle, error) {
 	return f.FetchCandles(ctx, coinID, 1)
@@ -120,9 +352,34 @@ func (f *CoinGeckoFetcher) FetchCandlesSince(ctx context.Context, coinID string,
 
 // fetchWithRetry performs HTTP request with error handling
 func (f *CoinGeckoFetcher) fetchWithRetry(ctx context.Context, endpoint string) ([]coingeckoOHLC, error) {
+	var ohlcData []coingeckoOHLC
+	if err := f.getJSON(ctx, endpoint, &ohlcData); err != nil {
+		return nil, err
+	}
+
+	return ohlcData, nil
+}
+
+// cgRetryDelayFor returns how long to wait before retrying after err,
+// honoring a Retry-After header from a 429 response
+func cgRetryDelayFor(err error) time.Duration {
+	var rateErr *rateLimitError
+	if errors.As(err, &rateErr) && rateErr.retryAfter > 0 {
+		return rateErr.retryAfter
+	}
+	return cgRetryDelay
+}
+
+// getJSON waits for the rate limiter, performs a GET request and decodes
+// the JSON response into v
+func (f *CoinGeckoFetcher) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	if err := f.limiter.wait(ctx); err != nil {
+		return err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "Candlecore/1.0")
@@ -130,25 +387,24 @@ func (f *CoinGeckoFetcher) fetchWithRetry(ctx context.Context, endpoint string)
 
 	resp, err := f.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return nil, fmt.Errorf("rate limit exceeded, retry after some time")
+		return &rateLimitError{retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(body))
 	}
 
-	var ohlcData []coingeckoOHLC
-	if err := json.NewDecoder(resp.Body).Decode(&ohlcData); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return ohlcData, nil
+	return nil
 }
 
 // parseOHLC converts CoinGecko OHLC format to engine.Candle
@@ -158,53 +414,18 @@ func (f *CoinGeckoFetcher) parseOHLC(ohlc coingeckoOHLC) (engine.Candle, error)
 		return engine.Candle{}, fmt.Errorf("invalid OHLC format: expected 5 fields, got %d", len(ohlc))
 	}
 
-	timestamp := time.UnixMilli(int64(ohlc[0]))
-	open := ohlc[1]
-	high := ohlc[2]
-	low := ohlc[3]
-	close := ohlc[4]
-
-	if high < low {
-		return engine.Candle{}, fmt.Errorf("invalid candle: high (%.2f) < low (%.2f)", high, low)
-	}
-	if open < low || open > high {
-		return engine.Candle{}, fmt.Errorf("invalid candle: open (%.2f) outside [low, high] range", open)
-	}
-	if close < low || close > high {
-		return engine.Candle{}, fmt.Errorf("invalid candle: close (%.2f) outside [low, high] range", close)
-	}
-
-	return engine.Candle{
-		Timestamp: timestamp,
-		Open:      open,
-		High:      high,
-		Low:       low,
-		Close:     close,
+	candle := engine.Candle{
+		Timestamp: time.UnixMilli(int64(ohlc[0])),
+		Open:      ohlc[1],
+		High:      ohlc[2],
+		Low:       ohlc[3],
+		Close:     ohlc[4],
 		Volume:    0,
-	}, nil
-}
-
-// ValidateCoinID checks if a coin ID is supported
-func ValidateCoinID(coinID string) bool {
-	supportedCoins := map[string]bool{
-		"bitcoin":  true,
-		"ethereum": true,
 	}
-	return supportedCoins[coinID]
-}
 
-// CoinIDFromSymbol converts trading symbol to CoinGecko coin ID
-func CoinIDFromSymbol(symbol string) string {
-	symbolToCoinID := map[string]string{
-		"BTCUSDT": "bitcoin",
-		"ETHUSDT": "ethereum",
-		"BTC/USD": "bitcoin",
-		"ETH/USD": "ethereum",
-	}
-	
-	if coinID, exists := symbolToCoinID[symbol]; exists {
-		return coinID
+	if err := candle.Validate(); err != nil {
+		return engine.Candle{}, err
 	}
-	
-	return ""
+
+	return candle, nil
 }
@@ -0,0 +1,335 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+func TestFetchCandlesWithVolume(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) float64 { return float64(base.Add(d).UnixMilli()) }
+
+	ohlc := [][]float64{
+		{ms(0), 100, 110, 90, 105},
+		{ms(4 * time.Hour), 105, 115, 95, 110},
+		{ms(8 * time.Hour), 110, 120, 100, 115},
+	}
+	chart := map[string][][]float64{
+		"prices": {},
+		// Samples are offset from candle times; the last candle has none within 4h
+		"total_volumes": {
+			{ms(5 * time.Minute), 1000},
+			{ms(3*time.Hour + 55*time.Minute), 2000},
+			{ms(20 * time.Hour), 3000},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/coins/bitcoin/ohlc"):
+			json.NewEncoder(w).Encode(ohlc)
+		case strings.HasSuffix(r.URL.Path, "/coins/bitcoin/market_chart"):
+			json.NewEncoder(w).Encode(chart)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	f := NewCoinGeckoFetcherWithRate(0)
+	f.baseURL = server.URL
+
+	candles, err := f.FetchCandlesWithVolume(context.Background(), "bitcoin", 1)
+	if err != nil {
+		t.Fatalf("FetchCandlesWithVolume() error: %v", err)
+	}
+
+	want := []float64{1000, 2000, 0}
+	if len(candles) != len(want) {
+		t.Fatalf("candles = %d, want %d", len(candles), len(want))
+	}
+	for i, c := range candles {
+		if c.Volume != want[i] {
+			t.Errorf("candle %d volume = %.0f, want %.0f", i, c.Volume, want[i])
+		}
+	}
+}
+
+func TestMergeVolumesSkipsShortSamples(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) float64 { return float64(base.Add(d).UnixMilli()) }
+
+	candles := []engine.Candle{
+		{Timestamp: base},
+		{Timestamp: base.Add(4 * time.Hour)},
+	}
+	volumes := [][]float64{
+		{ms(0), 1000},
+		{},
+		{ms(2 * time.Hour)},
+		{ms(4 * time.Hour), 2000},
+	}
+
+	mergeVolumes(candles, volumes)
+	if candles[0].Volume != 1000 || candles[1].Volume != 2000 {
+		t.Errorf("volumes = %.0f, %.0f, want 1000, 2000", candles[0].Volume, candles[1].Volume)
+	}
+}
+
+func TestFetchCandlesFallsBackToMarketChart(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) float64 { return float64(base.Add(d).UnixMilli()) }
+	day := 24 * time.Hour
+
+	// 4-day candles, as the OHLC endpoint returns for long ranges
+	ohlc := [][]float64{
+		{ms(0), 100, 110, 90, 105},
+		{ms(4 * day), 105, 115, 95, 110},
+	}
+	chart := map[string][][]float64{
+		"prices": {
+			{ms(0), 100},
+			{ms(6 * time.Hour), 104},
+			{ms(12 * time.Hour), 98},
+			{ms(18 * time.Hour), 101},
+			{ms(day), 102},
+			{ms(day + 12*time.Hour), 107},
+		},
+		"total_volumes": {
+			{ms(0), 500},
+			{ms(18 * time.Hour), 800},
+			{ms(day + 12*time.Hour), 900},
+		},
+	}
+
+	chartCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/coins/bitcoin/ohlc"):
+			json.NewEncoder(w).Encode(ohlc)
+		case strings.HasSuffix(r.URL.Path, "/coins/bitcoin/market_chart"):
+			chartCalls++
+			json.NewEncoder(w).Encode(chart)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	f := NewCoinGeckoFetcherWithRate(0)
+	f.baseURL = server.URL
+
+	candles, err := f.FetchCandles(context.Background(), "bitcoin", 180)
+	if err != nil {
+		t.Fatalf("FetchCandles() error: %v", err)
+	}
+	if chartCalls != 1 {
+		t.Fatalf("market_chart calls = %d, want 1", chartCalls)
+	}
+
+	want := []struct {
+		at                             time.Time
+		open, high, low, close, volume float64
+	}{
+		{base, 100, 104, 98, 101, 800},
+		// Opens at the previous day's close
+		{base.Add(day), 101, 107, 101, 107, 900},
+	}
+	if len(candles) != len(want) {
+		t.Fatalf("candles = %d, want %d daily candles", len(candles), len(want))
+	}
+	for i, w := range want {
+		c := candles[i]
+		if !c.Timestamp.Equal(w.at) || c.Open != w.open || c.High != w.high || c.Low != w.low || c.Close != w.close || c.Volume != w.volume {
+			t.Errorf("candle %d = %+v, want %+v", i, c, w)
+		}
+	}
+}
+
+func TestFetchCandlesWithVolumeReusesDailyChart(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) float64 { return float64(base.Add(d).UnixMilli()) }
+	day := 24 * time.Hour
+
+	// Beyond 90 days market_chart returns one sample per day
+	ohlc := [][]float64{
+		{ms(0), 100, 110, 90, 105},
+		{ms(4 * day), 105, 115, 95, 110},
+	}
+	chart := map[string][][]float64{
+		"prices": {
+			{ms(0), 100},
+			{ms(day), 104},
+			{ms(2 * day), 99},
+		},
+		"total_volumes": {
+			{ms(0), 500},
+			{ms(day), 600},
+			{ms(2 * day), 700},
+		},
+	}
+
+	chartCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/coins/bitcoin/ohlc"):
+			json.NewEncoder(w).Encode(ohlc)
+		case strings.HasSuffix(r.URL.Path, "/coins/bitcoin/market_chart"):
+			chartCalls++
+			json.NewEncoder(w).Encode(chart)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	f := NewCoinGeckoFetcherWithRate(0)
+	f.baseURL = server.URL
+
+	candles, err := f.FetchCandlesWithVolume(context.Background(), "bitcoin", 365)
+	if err != nil {
+		t.Fatalf("FetchCandlesWithVolume() error: %v", err)
+	}
+	if chartCalls != 1 {
+		t.Errorf("market_chart calls = %d, want 1", chartCalls)
+	}
+
+	want := []struct {
+		open, high, low, close, volume float64
+	}{
+		{100, 100, 100, 100, 500},
+		{100, 104, 100, 104, 600},
+		{104, 104, 99, 99, 700},
+	}
+	if len(candles) != len(want) {
+		t.Fatalf("candles = %d, want %d daily candles", len(candles), len(want))
+	}
+	for i, w := range want {
+		c := candles[i]
+		if c.Open != w.open || c.High != w.high || c.Low != w.low || c.Close != w.close || c.Volume != w.volume {
+			t.Errorf("candle %d = %+v, want %+v", i, c, w)
+		}
+	}
+}
+
+func TestCoinGeckoRateLimitSpacesCalls(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		json.NewEncoder(w).Encode([][]float64{{1704067200000, 100, 110, 90, 105}})
+	}))
+	defer server.Close()
+
+	// 600 requests per minute spaces calls 100ms apart
+	f := NewCoinGeckoFetcherWithRate(600)
+	f.baseURL = server.URL
+
+	for i := 0; i < 3; i++ {
+		if _, err := f.FetchCandles(context.Background(), "bitcoin", 1); err != nil {
+			t.Fatalf("FetchCandles() error: %v", err)
+		}
+	}
+
+	if len(times) != 3 {
+		t.Fatalf("requests = %d, want 3", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 90*time.Millisecond {
+			t.Errorf("gap between requests %d and %d = %v, want at least 100ms", i-1, i, gap)
+		}
+	}
+}
+
+func TestCoinGeckoRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	f := NewCoinGeckoFetcherWithRate(0)
+	f.baseURL = server.URL
+
+	var data []coingeckoOHLC
+	err := f.getJSON(context.Background(), server.URL, &data)
+	if err == nil {
+		t.Fatal("getJSON() expected rate limit error")
+	}
+	if got := cgRetryDelayFor(err); got != 7*time.Second {
+		t.Errorf("retry delay = %v, want 7s from Retry-After", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-5", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	if got := cgRetryDelayFor(&rateLimitError{}); got != cgRetryDelay {
+		t.Errorf("retry delay without Retry-After = %v, want %v", got, cgRetryDelay)
+	}
+}
+
+func TestCoinSymbolMapping(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"BTCUSDT", "bitcoin"},
+		{"ETH/USD", "ethereum"},
+		{"SOLUSDT", "solana"},
+		{"ADA/USDT", "cardano"},
+		{"DOTUSD", "polkadot"},
+		{"doge/usd", "dogecoin"},
+		{"LINKUSDT", "chainlink"},
+		{"SHIBUSDT", ""},
+		{"USDT", ""},
+	}
+	for _, tt := range tests {
+		if got := CoinIDFromSymbol(tt.symbol); got != tt.want {
+			t.Errorf("CoinIDFromSymbol(%q) = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+
+	coins := SupportedCoins()
+	if len(coins) < 10 {
+		t.Fatalf("SupportedCoins() = %d coins, want at least 10", len(coins))
+	}
+	for _, id := range coins {
+		if !ValidateCoinID(id) {
+			t.Errorf("ValidateCoinID(%q) = false for a supported coin", id)
+		}
+		pair := BinanceSymbolFromCoinID(id)
+		if !ValidateSymbol(pair) {
+			t.Errorf("ValidateSymbol(%q) = false for %s", pair, id)
+		}
+		if got := CoinIDFromSymbol(pair); got != id {
+			t.Errorf("CoinIDFromSymbol(%q) = %q, want %q", pair, got, id)
+		}
+	}
+	if ValidateCoinID("shiba-inu") {
+		t.Error("ValidateCoinID accepted an unsupported coin")
+	}
+}
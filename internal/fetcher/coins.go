@@ -0,0 +1,76 @@
+package fetcher
+
+import "strings"
+
+// coin pairs a CoinGecko coin ID with its exchange ticker
+type coin struct {
+	id     string
+	ticker string
+}
+
+// supportedCoins is the canonical list of tradable coins shared by the
+// CoinGecko and Binance helpers, the providers and the scraper
+var supportedCoins = []coin{
+	{"bitcoin", "BTC"},
+	{"ethereum", "ETH"},
+	{"binancecoin", "BNB"},
+	{"solana", "SOL"},
+	{"ripple", "XRP"},
+	{"cardano", "ADA"},
+	{"dogecoin", "DOGE"},
+	{"polkadot", "DOT"},
+	{"chainlink", "LINK"},
+	{"litecoin", "LTC"},
+}
+
+// quoteSuffixes are the quote currencies accepted when parsing symbols
+var quoteSuffixes = []string{"USDT", "USD"}
+
+// SupportedCoins returns the supported CoinGecko coin IDs
+func SupportedCoins() []string {
+	ids := make([]string, len(supportedCoins))
+	for i, c := range supportedCoins {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// ValidateCoinID checks if a coin ID is supported
+func ValidateCoinID(coinID string) bool {
+	for _, c := range supportedCoins {
+		if c.id == coinID {
+			return true
+		}
+	}
+	return false
+}
+
+// CoinIDFromSymbol converts a trading symbol to a CoinGecko coin ID.
+// Symbols may quote in USDT or USD, with or without a slash
+// (BTCUSDT, BTCUSD, BTC/USDT, BTC/USD). Unknown symbols return "".
+func CoinIDFromSymbol(symbol string) string {
+	base := strings.ToUpper(strings.ReplaceAll(symbol, "/", ""))
+	for _, quote := range quoteSuffixes {
+		if strings.HasSuffix(base, quote) {
+			base = strings.TrimSuffix(base, quote)
+			break
+		}
+	}
+
+	for _, c := range supportedCoins {
+		if c.ticker == base {
+			return c.id
+		}
+	}
+	return ""
+}
+
+// BinanceSymbolFromCoinID converts a CoinGecko coin ID to a Binance USDT pair
+func BinanceSymbolFromCoinID(coinID string) string {
+	for _, c := range supportedCoins {
+		if c.id == coinID {
+			return c.ticker + "USDT"
+		}
+	}
+	return ""
+}
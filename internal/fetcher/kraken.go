@@ -0,0 +1,244 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+const (
+	krakenBaseURL = "https://api.kraken.com"
+
+	// krakenMaxCandles is the most OHLC entries Kraken returns per request
+	krakenMaxCandles = 720
+)
+
+// krakenIntervals maps candle intervals to Kraken's interval in minutes
+var krakenIntervals = map[string]int{
+	"1m":  1,
+	"5m":  5,
+	"15m": 15,
+	"1h":  60,
+	"4h":  240,
+	"1d":  1440,
+}
+
+// KrakenFetcher fetches candle data from the Kraken public API
+type KrakenFetcher struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewKrakenFetcher creates a new Kraken data fetcher
+func NewKrakenFetcher() *KrakenFetcher {
+	return &KrakenFetcher{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: krakenBaseURL,
+	}
+}
+
+// krakenOHLCResponse is the /0/public/OHLC envelope. Result holds one entry
+// keyed by Kraken's internal pair name (e.g. XXBTZUSD) plus a "last" cursor.
+type krakenOHLCResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// krakenOHLC is one OHLC entry:
+// [time, open, high, low, close, vwap, volume, count]
+type krakenOHLC []interface{}
+
+// Kraken OHLC field positions
+const (
+	krakenTime = iota
+	krakenOpen
+	krakenHigh
+	krakenLow
+	krakenClose
+	krakenVWAP
+	krakenVolume
+
+	krakenMinFields
+)
+
+// FetchCandles fetches the most recent candles from Kraken
+// pair: e.g., "XBTUSD", "ETHUSD"
+// interval: "1m", "5m", "15m", "1h", "4h", "1d"
+// limit: number of candles to return (max 720); 0 returns all
+func (f *KrakenFetcher) FetchCandles(ctx context.Context, pair, interval string, limit int) ([]engine.Candle, error) {
+	if !ValidateKrakenPair(pair) {
+		return nil, fmt.Errorf("unsupported kraken pair: %s", pair)
+	}
+	minutes, ok := krakenIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	if limit > krakenMaxCandles {
+		limit = krakenMaxCandles
+	}
+
+	params := url.Values{}
+	params.Add("pair", pair)
+	params.Add("interval", strconv.Itoa(minutes))
+
+	endpoint := fmt.Sprintf("%s/0/public/OHLC?%s", f.baseURL, params.Encode())
+
+	var entries []krakenOHLC
+	var err error
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		entries, err = f.fetchWithRetry(ctx, endpoint)
+		if err == nil {
+			break
+		}
+
+		if attempt < maxRetries-1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryDelay):
+				continue
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candles after %d attempts: %w", maxRetries, err)
+	}
+
+	candles, err := f.parseOHLC(entries, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && limit < len(candles) {
+		candles = candles[len(candles)-limit:]
+	}
+
+	return candles, nil
+}
+
+// fetchWithRetry performs HTTP request with error handling
+func (f *KrakenFetcher) fetchWithRetry(ctx context.Context, endpoint string) ([]krakenOHLC, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Candlecore/1.0")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var envelope krakenOHLCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(envelope.Error) > 0 {
+		return nil, fmt.Errorf("API error: %s", strings.Join(envelope.Error, "; "))
+	}
+
+	for key, raw := range envelope.Result {
+		if key == "last" {
+			continue
+		}
+
+		var entries []krakenOHLC
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode OHLC data: %w", err)
+		}
+		return entries, nil
+	}
+
+	return nil, fmt.Errorf("response contained no OHLC data")
+}
+
+// parseOHLC converts Kraken OHLC entries into candles. Malformed entries
+// fail the whole response; entries with impossible values are quarantined.
+func (f *KrakenFetcher) parseOHLC(entries []krakenOHLC, pair string) ([]engine.Candle, error) {
+	candles := make([]engine.Candle, 0, len(entries))
+	rejected := 0
+	for _, entry := range entries {
+		candle, err := f.parseEntry(entry)
+		if errors.Is(err, engine.ErrInvalidCandle) {
+			rejected++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OHLC entry: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+
+	if rejected > 0 {
+		log.Printf("Rejected %d invalid Kraken candles for %s", rejected, pair)
+	}
+
+	return candles, nil
+}
+
+// parseEntry converts one Kraken OHLC entry to engine.Candle. Kraken
+// reports time in seconds and prices and volume as strings.
+func (f *KrakenFetcher) parseEntry(entry krakenOHLC) (engine.Candle, error) {
+	if len(entry) < krakenMinFields {
+		return engine.Candle{}, fmt.Errorf("invalid OHLC format: expected at least %d fields, got %d", krakenMinFields, len(entry))
+	}
+
+	fields := make([]float64, krakenMinFields)
+	names := []string{"time", "open", "high", "low", "close", "vwap", "volume"}
+	for i := range fields {
+		v, err := parseFloat(entry[i])
+		if err != nil {
+			return engine.Candle{}, fmt.Errorf("invalid %s: %w", names[i], err)
+		}
+		fields[i] = v
+	}
+	if fields[krakenTime] <= 0 {
+		return engine.Candle{}, fmt.Errorf("invalid time: %.0f", fields[krakenTime])
+	}
+
+	candle := engine.Candle{
+		Timestamp: time.Unix(int64(fields[krakenTime]), 0),
+		Open:      fields[krakenOpen],
+		High:      fields[krakenHigh],
+		Low:       fields[krakenLow],
+		Close:     fields[krakenClose],
+		Volume:    fields[krakenVolume],
+	}
+
+	if err := candle.Validate(); err != nil {
+		return engine.Candle{}, err
+	}
+
+	return candle, nil
+}
+
+// ValidateKrakenPair checks if a Kraken pair is supported
+func ValidateKrakenPair(pair string) bool {
+	supportedPairs := map[string]bool{
+		"XBTUSD": true,
+		"ETHUSD": true,
+	}
+	return supportedPairs[pair]
+}
@@ -0,0 +1,106 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const krakenOHLCFixture = `{
+	"error": [],
+	"result": {
+		"XXBTZUSD": [
+			[1704067200, "42000.0", "42500.5", "41800.0", "42400.0", "42200.1", "12.5", 310],
+			[1704070800, "42400.0", "42600.0", "42100.0", "42150.0", "42300.0", "8.25", 220],
+			[1704074400, "42150.0", "42000.0", "42200.0", "42100.0", "42100.0", "3.0", 90]
+		],
+		"last": 1704070800
+	}
+}`
+
+func newKrakenTestServer(t *testing.T, body string) (*KrakenFetcher, *http.Request) {
+	t.Helper()
+
+	var captured http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = *r
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	f := NewKrakenFetcher()
+	f.baseURL = server.URL
+	return f, &captured
+}
+
+func TestKrakenFetchCandles(t *testing.T) {
+	f, req := newKrakenTestServer(t, krakenOHLCFixture)
+
+	candles, err := f.FetchCandles(context.Background(), "XBTUSD", "1h", 0)
+	if err != nil {
+		t.Fatalf("FetchCandles() error: %v", err)
+	}
+
+	if req.URL.Path != "/0/public/OHLC" {
+		t.Errorf("path = %s, want /0/public/OHLC", req.URL.Path)
+	}
+	if got := req.URL.Query().Get("interval"); got != "60" {
+		t.Errorf("interval = %s, want 60", got)
+	}
+	if got := req.URL.Query().Get("pair"); got != "XBTUSD" {
+		t.Errorf("pair = %s, want XBTUSD", got)
+	}
+
+	// The third entry has high below low and is quarantined
+	if len(candles) != 2 {
+		t.Fatalf("candles = %d, want 2", len(candles))
+	}
+	first := candles[0]
+	if !first.Timestamp.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("timestamp = %v, want 2024-01-01T00:00:00Z", first.Timestamp.UTC())
+	}
+	if first.Open != 42000 || first.High != 42500.5 || first.Low != 41800 || first.Close != 42400 {
+		t.Errorf("OHLC = %.1f/%.1f/%.1f/%.1f, want 42000/42500.5/41800/42400",
+			first.Open, first.High, first.Low, first.Close)
+	}
+	if first.Volume != 12.5 {
+		t.Errorf("volume = %.2f, want 12.5", first.Volume)
+	}
+}
+
+func TestKrakenFetchCandlesLimit(t *testing.T) {
+	f, _ := newKrakenTestServer(t, krakenOHLCFixture)
+
+	candles, err := f.FetchCandles(context.Background(), "XBTUSD", "1h", 1)
+	if err != nil {
+		t.Fatalf("FetchCandles() error: %v", err)
+	}
+	if len(candles) != 1 || candles[0].Close != 42150 {
+		t.Fatalf("candles = %+v, want only the most recent valid candle", candles)
+	}
+}
+
+func TestKrakenFetchCandlesValidation(t *testing.T) {
+	f := NewKrakenFetcher()
+	f.baseURL = "http://127.0.0.1:0"
+
+	if _, err := f.FetchCandles(context.Background(), "DOGEUSD", "1h", 0); err == nil {
+		t.Error("expected error for unsupported pair")
+	}
+	if _, err := f.FetchCandles(context.Background(), "ETHUSD", "2h", 0); err == nil {
+		t.Error("expected error for unsupported interval")
+	}
+}
+
+func TestKrakenParseEntry(t *testing.T) {
+	f := NewKrakenFetcher()
+
+	if _, err := f.parseEntry(krakenOHLC{float64(1704067200), "1", "2", "0.5"}); err == nil {
+		t.Error("expected error for too few fields")
+	}
+	if _, err := f.parseEntry(krakenOHLC{float64(1704067200), "x", "2", "0.5", "1", "1", "1", float64(1)}); err == nil {
+		t.Error("expected error for non-numeric open")
+	}
+}
@@ -221,3 +221,254 @@ func BollingerBands(values []float64, period int, stdDev float64) (*BollingerBan
 		Lower:  lower,
 	}, nil
 }
+
+// ATR calculates Average True Range using Wilder's smoothing
+// True range is max(high-low, |high-prevClose|, |low-prevClose|)
+func ATR(highs, lows, closes []float64, period int) ([]float64, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("period must be positive")
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) {
+		return nil, fmt.Errorf("highs, lows, and closes must have equal length")
+	}
+	if len(closes) < period+1 {
+		return nil, fmt.Errorf("insufficient data: need %d, got %d", period+1, len(closes))
+	}
+
+	// True range needs the previous close, so it starts at the second candle
+	trueRanges := make([]float64, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		trueRanges[i-1] = math.Max(highs[i]-lows[i],
+			math.Max(math.Abs(highs[i]-closes[i-1]), math.Abs(lows[i]-closes[i-1])))
+	}
+
+	result := make([]float64, len(trueRanges)-period+1)
+
+	// First ATR is the simple average of the first period true ranges
+	sum := 0.0
+	for i := 0; i < period; i++ {
+		sum += trueRanges[i]
+	}
+	result[0] = sum / float64(period)
+
+	// Wilder's smoothing for the rest
+	for i := period; i < len(trueRanges); i++ {
+		result[i-period+1] = (result[i-period]*float64(period-1) + trueRanges[i]) / float64(period)
+	}
+
+	return result, nil
+}
+
+// ADX calculates the Average Directional Index with the +DI and -DI lines
+// using Wilder's smoothing. plusDI and minusDI start at candle period and
+// adx at candle 2*period-1, so all three end on the last candle.
+// Windows with zero true range yield DI and DX values of 0.
+func ADX(highs, lows, closes []float64, period int) (adx, plusDI, minusDI []float64, err error) {
+	if period <= 0 {
+		return nil, nil, nil, fmt.Errorf("period must be positive")
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) {
+		return nil, nil, nil, fmt.Errorf("highs, lows, and closes must have equal length")
+	}
+	if len(closes) < 2*period {
+		return nil, nil, nil, fmt.Errorf("insufficient data: need %d, got %d", 2*period, len(closes))
+	}
+
+	// Directional movement and true range start at the second candle
+	n := len(closes) - 1
+	trueRanges := make([]float64, n)
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	for i := 1; i < len(closes); i++ {
+		trueRanges[i-1] = math.Max(highs[i]-lows[i],
+			math.Max(math.Abs(highs[i]-closes[i-1]), math.Abs(lows[i]-closes[i-1])))
+
+		up := highs[i] - highs[i-1]
+		down := lows[i-1] - lows[i]
+		if up > down && up > 0 {
+			plusDM[i-1] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i-1] = down
+		}
+	}
+
+	// First smoothed values are simple averages of the first period moves
+	smoothTR, smoothPlus, smoothMinus := 0.0, 0.0, 0.0
+	for i := 0; i < period; i++ {
+		smoothTR += trueRanges[i]
+		smoothPlus += plusDM[i]
+		smoothMinus += minusDM[i]
+	}
+	smoothTR /= float64(period)
+	smoothPlus /= float64(period)
+	smoothMinus /= float64(period)
+
+	plusDI = make([]float64, n-period+1)
+	minusDI = make([]float64, n-period+1)
+	dx := make([]float64, n-period+1)
+	for i := period - 1; i < n; i++ {
+		if i >= period {
+			smoothTR = (smoothTR*float64(period-1) + trueRanges[i]) / float64(period)
+			smoothPlus = (smoothPlus*float64(period-1) + plusDM[i]) / float64(period)
+			smoothMinus = (smoothMinus*float64(period-1) + minusDM[i]) / float64(period)
+		}
+
+		j := i - period + 1
+		if smoothTR > 0 {
+			plusDI[j] = 100 * smoothPlus / smoothTR
+			minusDI[j] = 100 * smoothMinus / smoothTR
+		}
+		if sum := plusDI[j] + minusDI[j]; sum > 0 {
+			dx[j] = 100 * math.Abs(plusDI[j]-minusDI[j]) / sum
+		}
+	}
+
+	// ADX is the Wilder-smoothed DX, seeded with the average of the first period
+	adx = make([]float64, len(dx)-period+1)
+	sum := 0.0
+	for i := 0; i < period; i++ {
+		sum += dx[i]
+	}
+	adx[0] = sum / float64(period)
+	for i := period; i < len(dx); i++ {
+		adx[i-period+1] = (adx[i-period]*float64(period-1) + dx[i]) / float64(period)
+	}
+
+	return adx, plusDI, minusDI, nil
+}
+
+// VWAP calculates the Volume Weighted Average Price cumulatively from the
+// first candle: sum(typical price * volume) / sum(volume), where typical
+// price is (high+low+close)/3. The result has one value per candle.
+func VWAP(highs, lows, closes, volumes []float64) ([]float64, error) {
+	return SessionVWAP(highs, lows, closes, volumes, 0)
+}
+
+// SessionVWAP calculates VWAP, restarting the accumulation every
+// sessionLength candles. A sessionLength of zero never resets. While a
+// session has no volume yet, VWAP equals the typical price.
+func SessionVWAP(highs, lows, closes, volumes []float64, sessionLength int) ([]float64, error) {
+	if sessionLength < 0 {
+		return nil, fmt.Errorf("session length must not be negative")
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) || len(highs) != len(volumes) {
+		return nil, fmt.Errorf("highs, lows, closes, and volumes must have equal length")
+	}
+	if len(closes) == 0 {
+		return nil, fmt.Errorf("insufficient data: need 1, got 0")
+	}
+
+	result := make([]float64, len(closes))
+	priceVolume, totalVolume := 0.0, 0.0
+	for i := range closes {
+		if sessionLength > 0 && i%sessionLength == 0 {
+			priceVolume, totalVolume = 0, 0
+		}
+		if volumes[i] < 0 {
+			return nil, fmt.Errorf("volume must not be negative at index %d", i)
+		}
+
+		typical := (highs[i] + lows[i] + closes[i]) / 3
+		priceVolume += typical * volumes[i]
+		totalVolume += volumes[i]
+
+		if totalVolume == 0 {
+			result[i] = typical
+		} else {
+			result[i] = priceVolume / totalVolume
+		}
+	}
+
+	return result, nil
+}
+
+// Ichimoku calculates the Ichimoku Cloud lines. Like the other indicators,
+// each slice is aligned to the candle it is calculated on and ends on the
+// last candle, except where a line is shifted:
+//
+//   - tenkan[i] is the conversion-period high/low midpoint ending at candle
+//     i+conversion-1.
+//   - kijun[i] is the base-period high/low midpoint ending at candle i+base-1.
+//   - senkouA[i] is (tenkan+kijun)/2 calculated at candle
+//     i+max(conversion, base)-1 and plotted base candles later, so the last
+//     base values project past the last candle.
+//   - senkouB[i] is the spanB-period midpoint calculated at candle
+//     i+spanB-1 and plotted base candles later.
+//   - chikou[i] is the close of candle i+base plotted at candle i, so it
+//     starts on the first candle and stops base candles before the last.
+func Ichimoku(highs, lows, closes []float64, conversion, base, spanB int) (tenkan, kijun, senkouA, senkouB, chikou []float64, err error) {
+	if conversion <= 0 || base <= 0 || spanB <= 0 {
+		return nil, nil, nil, nil, nil, fmt.Errorf("periods must be positive")
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) {
+		return nil, nil, nil, nil, nil, fmt.Errorf("highs, lows, and closes must have equal length")
+	}
+	need := int(math.Max(float64(base+1), math.Max(float64(conversion), float64(spanB))))
+	if len(closes) < need {
+		return nil, nil, nil, nil, nil, fmt.Errorf("insufficient data: need %d, got %d", need, len(closes))
+	}
+
+	tenkan = midpoints(highs, lows, conversion)
+	kijun = midpoints(highs, lows, base)
+	senkouB = midpoints(highs, lows, spanB)
+
+	// Senkou A needs both lines, which start at the longer of the two periods
+	start := conversion
+	if base > start {
+		start = base
+	}
+	senkouA = make([]float64, len(closes)-start+1)
+	for i := range senkouA {
+		candle := i + start - 1
+		senkouA[i] = (tenkan[candle-conversion+1] + kijun[candle-base+1]) / 2
+	}
+
+	chikou = make([]float64, len(closes)-base)
+	copy(chikou, closes[base:])
+
+	return tenkan, kijun, senkouA, senkouB, chikou, nil
+}
+
+// midpoints returns (highest high + lowest low) / 2 over each period window
+func midpoints(highs, lows []float64, period int) []float64 {
+	upper, lower := channel(highs, lows, period)
+	result := make([]float64, len(upper))
+	for i := range result {
+		result[i] = (upper[i] + lower[i]) / 2
+	}
+	return result
+}
+
+// DonchianChannels calculates the highest high and lowest low over each
+// period window. Like SMA, upper[i] and lower[i] cover candles i through
+// i+period-1, so both end on the last candle and include it.
+func DonchianChannels(highs, lows []float64, period int) (upper, lower []float64, err error) {
+	if period <= 0 {
+		return nil, nil, fmt.Errorf("period must be positive")
+	}
+	if len(highs) != len(lows) {
+		return nil, nil, fmt.Errorf("highs and lows must have equal length")
+	}
+	if len(highs) < period {
+		return nil, nil, fmt.Errorf("insufficient data: need %d, got %d", period, len(highs))
+	}
+
+	upper, lower = channel(highs, lows, period)
+	return upper, lower, nil
+}
+
+// channel returns the highest high and lowest low over each period window
+func channel(highs, lows []float64, period int) (upper, lower []float64) {
+	upper = make([]float64, len(highs)-period+1)
+	lower = make([]float64, len(highs)-period+1)
+	for i := range upper {
+		high, low := highs[i], lows[i]
+		for j := i + 1; j < i+period; j++ {
+			high = math.Max(high, highs[j])
+			low = math.Min(low, lows[j])
+		}
+		upper[i], lower[i] = high, low
+	}
+	return upper, lower
+}
@@ -0,0 +1,266 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestATR(t *testing.T) {
+	highs := []float64{10, 12, 13, 12, 15}
+	lows := []float64{8, 9, 11, 10, 11}
+	closes := []float64{9, 11, 12, 11, 14}
+
+	// True ranges: 3 (12-9), 2 (13-11), 2 (12-10), 4 (15-11)
+	// First ATR(2) = 2.5, then (2.5+2)/2 = 2.25, then (2.25+4)/2 = 3.125
+	got, err := ATR(highs, lows, closes, 2)
+	if err != nil {
+		t.Fatalf("ATR() error: %v", err)
+	}
+
+	want := []float64{2.5, 2.25, 3.125}
+	if len(got) != len(want) {
+		t.Fatalf("len(ATR) = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("ATR[%d] = %.4f, want %.4f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestATRGapUsesPreviousClose(t *testing.T) {
+	// A gap up makes |high-prevClose| the true range
+	got, err := ATR([]float64{10, 21}, []float64{9, 20}, []float64{10, 20.5}, 1)
+	if err != nil {
+		t.Fatalf("ATR() error: %v", err)
+	}
+	if got[0] != 11 {
+		t.Errorf("ATR = %.2f, want 11", got[0])
+	}
+}
+
+func TestATRErrors(t *testing.T) {
+	if _, err := ATR([]float64{1, 2}, []float64{1}, []float64{1, 2}, 1); err == nil {
+		t.Error("expected error for unequal lengths")
+	}
+	if _, err := ATR([]float64{1, 2}, []float64{1, 2}, []float64{1, 2}, 2); err == nil {
+		t.Error("expected error for insufficient data")
+	}
+	if _, err := ATR([]float64{1, 2}, []float64{1, 2}, []float64{1, 2}, 0); err == nil {
+		t.Error("expected error for non-positive period")
+	}
+}
+
+func TestADXRisingTrend(t *testing.T) {
+	// A choppy range followed by a steady advance
+	var highs, lows, closes []float64
+	for i := 0; i < 10; i++ {
+		base := 100.0
+		if i%2 == 1 {
+			base = 98
+		}
+		highs = append(highs, base+2)
+		lows = append(lows, base-2)
+		closes = append(closes, base)
+	}
+	for i := 1; i <= 30; i++ {
+		c := 100 + 2*float64(i)
+		highs = append(highs, c+1)
+		lows = append(lows, c-1)
+		closes = append(closes, c)
+	}
+
+	period := 5
+	adx, plusDI, minusDI, err := ADX(highs, lows, closes, period)
+	if err != nil {
+		t.Fatalf("ADX() error: %v", err)
+	}
+	if len(plusDI) != len(closes)-period || len(minusDI) != len(plusDI) {
+		t.Fatalf("len(DI) = %d/%d, want %d", len(plusDI), len(minusDI), len(closes)-period)
+	}
+	if len(adx) != len(closes)-2*period+1 {
+		t.Fatalf("len(ADX) = %d, want %d", len(adx), len(closes)-2*period+1)
+	}
+
+	last := len(plusDI) - 1
+	if plusDI[last] <= minusDI[last] {
+		t.Errorf("+DI = %.2f, -DI = %.2f, want +DI to dominate", plusDI[last], minusDI[last])
+	}
+
+	// Over the trending leg ADX should rise on every candle
+	trendStart := len(adx) - 20
+	for i := trendStart + 1; i < len(adx); i++ {
+		if adx[i] <= adx[i-1] {
+			t.Fatalf("ADX[%d] = %.2f did not rise from %.2f", i, adx[i], adx[i-1])
+		}
+	}
+	if adx[len(adx)-1] < 50 {
+		t.Errorf("final ADX = %.2f, want a strong trend reading", adx[len(adx)-1])
+	}
+}
+
+func TestADXFlatSeries(t *testing.T) {
+	flat := []float64{10, 10, 10, 10, 10, 10}
+	adx, plusDI, minusDI, err := ADX(flat, flat, flat, 2)
+	if err != nil {
+		t.Fatalf("ADX() error: %v", err)
+	}
+	for i := range plusDI {
+		if plusDI[i] != 0 || minusDI[i] != 0 {
+			t.Errorf("DI[%d] = %.2f/%.2f, want 0 for zero true range", i, plusDI[i], minusDI[i])
+		}
+	}
+	for i, v := range adx {
+		if v != 0 || math.IsNaN(v) {
+			t.Errorf("ADX[%d] = %.2f, want 0", i, v)
+		}
+	}
+}
+
+func TestADXErrors(t *testing.T) {
+	if _, _, _, err := ADX([]float64{1, 2}, []float64{1}, []float64{1, 2}, 1); err == nil {
+		t.Error("expected error for unequal lengths")
+	}
+	if _, _, _, err := ADX([]float64{1, 2, 3}, []float64{1, 2, 3}, []float64{1, 2, 3}, 2); err == nil {
+		t.Error("expected error for insufficient data")
+	}
+	if _, _, _, err := ADX([]float64{1, 2}, []float64{1, 2}, []float64{1, 2}, 0); err == nil {
+		t.Error("expected error for non-positive period")
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	// Typical prices 10, 20, 30 with volumes 1, 3, 0
+	highs := []float64{11, 21, 31}
+	lows := []float64{9, 19, 29}
+	closes := []float64{10, 20, 30}
+	volumes := []float64{1, 3, 0}
+
+	got, err := VWAP(highs, lows, closes, volumes)
+	if err != nil {
+		t.Fatalf("VWAP() error: %v", err)
+	}
+
+	// (10*1)/1 = 10, (10+60)/4 = 17.5, zero volume leaves it at 17.5
+	want := []float64{10, 17.5, 17.5}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("VWAP[%d] = %.4f, want %.4f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSessionVWAPResets(t *testing.T) {
+	closes := []float64{10, 20, 30, 40}
+	volumes := []float64{1, 1, 2, 2}
+
+	got, err := SessionVWAP(closes, closes, closes, volumes, 2)
+	if err != nil {
+		t.Fatalf("SessionVWAP() error: %v", err)
+	}
+
+	// Sessions [10, 20] and [30, 40]
+	want := []float64{10, 15, 30, 35}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("SessionVWAP[%d] = %.4f, want %.4f", i, got[i], want[i])
+		}
+	}
+
+	// A session opening without volume reports the typical price
+	got, err = SessionVWAP([]float64{12}, []float64{8}, []float64{10}, []float64{0}, 1)
+	if err != nil {
+		t.Fatalf("SessionVWAP() error: %v", err)
+	}
+	if got[0] != 10 {
+		t.Errorf("zero-volume VWAP = %.2f, want typical price 10", got[0])
+	}
+}
+
+func TestVWAPErrors(t *testing.T) {
+	if _, err := VWAP([]float64{1}, []float64{1}, []float64{1}, nil); err == nil {
+		t.Error("expected error for unequal lengths")
+	}
+	if _, err := VWAP(nil, nil, nil, nil); err == nil {
+		t.Error("expected error for empty input")
+	}
+	if _, err := VWAP([]float64{1}, []float64{1}, []float64{1}, []float64{-1}); err == nil {
+		t.Error("expected error for negative volume")
+	}
+	if _, err := SessionVWAP([]float64{1}, []float64{1}, []float64{1}, []float64{1}, -1); err == nil {
+		t.Error("expected error for negative session length")
+	}
+}
+
+func TestIchimoku(t *testing.T) {
+	highs := []float64{10, 12, 11, 15, 14, 13, 16, 18}
+	lows := []float64{8, 9, 7, 10, 12, 11, 12, 15}
+	closes := []float64{9, 11, 10, 14, 13, 12, 15, 17}
+
+	tenkan, kijun, senkouA, senkouB, chikou, err := Ichimoku(highs, lows, closes, 2, 3, 4)
+	if err != nil {
+		t.Fatalf("Ichimoku() error: %v", err)
+	}
+
+	// Tenkan is the midpoint of each two-candle window, e.g. (12+8)/2 = 10
+	assertSeries(t, "tenkan", tenkan, []float64{10, 9.5, 11, 12.5, 12.5, 13.5, 15})
+	assertSeries(t, "kijun", kijun, []float64{9.5, 11, 11, 12.5, 13.5, 14.5})
+
+	// Senkou A starts at candle 2, the first with a kijun value
+	assertSeries(t, "senkouA", senkouA, []float64{9.5, 11, 11.75, 12.5, 13.5, 14.75})
+	assertSeries(t, "senkouB", senkouB, []float64{11, 11, 11, 13, 14.5})
+
+	// Chikou is the close shifted back three candles
+	assertSeries(t, "chikou", chikou, []float64{14, 13, 12, 15, 17})
+}
+
+func assertSeries(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("len(%s) = %d, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("%s[%d] = %.4f, want %.4f", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestIchimokuErrors(t *testing.T) {
+	values := []float64{1, 2, 3, 4}
+	if _, _, _, _, _, err := Ichimoku(values, values[:3], values, 1, 2, 3); err == nil {
+		t.Error("expected error for unequal lengths")
+	}
+	if _, _, _, _, _, err := Ichimoku(values, values, values, 1, 2, 5); err == nil {
+		t.Error("expected error for insufficient data")
+	}
+	if _, _, _, _, _, err := Ichimoku(values, values, values, 0, 2, 3); err == nil {
+		t.Error("expected error for non-positive period")
+	}
+}
+
+func TestDonchianChannels(t *testing.T) {
+	highs := []float64{10, 12, 11, 15, 14, 13}
+	lows := []float64{8, 9, 7, 10, 12, 11}
+
+	upper, lower, err := DonchianChannels(highs, lows, 3)
+	if err != nil {
+		t.Fatalf("DonchianChannels() error: %v", err)
+	}
+
+	assertSeries(t, "upper", upper, []float64{12, 15, 15, 15})
+	assertSeries(t, "lower", lower, []float64{7, 7, 7, 10})
+}
+
+func TestDonchianChannelsErrors(t *testing.T) {
+	values := []float64{1, 2, 3}
+	if _, _, err := DonchianChannels(values, values[:2], 2); err == nil {
+		t.Error("expected error for unequal lengths")
+	}
+	if _, _, err := DonchianChannels(values, values, 4); err == nil {
+		t.Error("expected error for insufficient data")
+	}
+	if _, _, err := DonchianChannels(values, values, 0); err == nil {
+		t.Error("expected error for non-positive period")
+	}
+}
@@ -0,0 +1,153 @@
+package indicators
+
+// StreamingSMA maintains a Simple Moving Average one value at a time in
+// O(1) per update, matching SMA over the same series
+type StreamingSMA struct {
+	period int
+	window []float64
+	next   int
+	count  int
+	sum    float64
+}
+
+// NewStreamingSMA creates a streaming SMA; periods below 1 are treated as 1
+func NewStreamingSMA(period int) *StreamingSMA {
+	if period < 1 {
+		period = 1
+	}
+	return &StreamingSMA{period: period, window: make([]float64, period)}
+}
+
+// Update adds a value and returns the current average, or 0 until Ready
+func (s *StreamingSMA) Update(value float64) float64 {
+	if s.count == s.period {
+		s.sum -= s.window[s.next]
+	} else {
+		s.count++
+	}
+	s.window[s.next] = value
+	s.next = (s.next + 1) % s.period
+	s.sum += value
+
+	return s.Value()
+}
+
+// Value returns the current average, or 0 until Ready
+func (s *StreamingSMA) Value() float64 {
+	if !s.Ready() {
+		return 0
+	}
+	return s.sum / float64(s.period)
+}
+
+// Ready reports whether a full period of values has been seen
+func (s *StreamingSMA) Ready() bool {
+	return s.count == s.period
+}
+
+// StreamingEMA maintains an Exponential Moving Average one value at a time,
+// seeded with the SMA of the first period values like EMA
+type StreamingEMA struct {
+	seed       *StreamingSMA
+	multiplier float64
+	value      float64
+}
+
+// NewStreamingEMA creates a streaming EMA; periods below 1 are treated as 1
+func NewStreamingEMA(period int) *StreamingEMA {
+	seed := NewStreamingSMA(period)
+	return &StreamingEMA{
+		seed:       seed,
+		multiplier: 2.0 / float64(seed.period+1),
+	}
+}
+
+// Update adds a value and returns the current EMA, or 0 until Ready
+func (e *StreamingEMA) Update(value float64) float64 {
+	if !e.seed.Ready() {
+		e.value = e.seed.Update(value)
+		return e.value
+	}
+
+	e.value = (value-e.value)*e.multiplier + e.value
+	return e.value
+}
+
+// Value returns the current EMA, or 0 until Ready
+func (e *StreamingEMA) Value() float64 {
+	return e.value
+}
+
+// Ready reports whether a full period of values has been seen
+func (e *StreamingEMA) Ready() bool {
+	return e.seed.Ready()
+}
+
+// StreamingRSI maintains a Relative Strength Index one value at a time with
+// Wilder smoothing, matching RSI over the same series
+type StreamingRSI struct {
+	period  int
+	seen    int
+	prev    float64
+	avgGain float64
+	avgLoss float64
+	value   float64
+}
+
+// NewStreamingRSI creates a streaming RSI; periods below 1 are treated as 1
+func NewStreamingRSI(period int) *StreamingRSI {
+	if period < 1 {
+		period = 1
+	}
+	return &StreamingRSI{period: period}
+}
+
+// Update adds a value and returns the current RSI, or 0 until Ready, which
+// takes period+1 values
+func (r *StreamingRSI) Update(value float64) float64 {
+	r.seen++
+	change := value - r.prev
+	r.prev = value
+	if r.seen == 1 {
+		return 0
+	}
+
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	period := float64(r.period)
+	switch changes := r.seen - 1; {
+	case changes < r.period:
+		// Accumulate toward the first averages
+		r.avgGain += gain
+		r.avgLoss += loss
+		return 0
+	case changes == r.period:
+		r.avgGain = (r.avgGain + gain) / period
+		r.avgLoss = (r.avgLoss + loss) / period
+	default:
+		r.avgGain = (r.avgGain*(period-1) + gain) / period
+		r.avgLoss = (r.avgLoss*(period-1) + loss) / period
+	}
+
+	if r.avgLoss == 0 {
+		r.value = 100
+	} else {
+		r.value = 100 - (100 / (1 + r.avgGain/r.avgLoss))
+	}
+	return r.value
+}
+
+// Value returns the current RSI, or 0 until Ready
+func (r *StreamingRSI) Value() float64 {
+	return r.value
+}
+
+// Ready reports whether period price changes have been seen
+func (r *StreamingRSI) Ready() bool {
+	return r.seen > r.period
+}
@@ -0,0 +1,76 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// streamingSeries is a choppy series with flat stretches and reversals
+func streamingSeries() []float64 {
+	values := make([]float64, 120)
+	for i := range values {
+		values[i] = 100 + 10*math.Sin(float64(i)/5) + float64(i%7) - 3
+		if i >= 40 && i < 50 {
+			values[i] = 105
+		}
+	}
+	return values
+}
+
+// streamingIndicator is the method set shared by the streaming types
+type streamingIndicator interface {
+	Update(value float64) float64
+	Ready() bool
+}
+
+func TestStreamingMatchesBatch(t *testing.T) {
+	values := streamingSeries()
+
+	tests := []struct {
+		name   string
+		stream streamingIndicator
+		batch  func([]float64) ([]float64, error)
+		warmup int // values needed before the first output
+	}{
+		{"SMA", NewStreamingSMA(10), func(v []float64) ([]float64, error) { return SMA(v, 10) }, 10},
+		{"EMA", NewStreamingEMA(12), func(v []float64) ([]float64, error) { return EMA(v, 12) }, 12},
+		{"RSI", NewStreamingRSI(14), func(v []float64) ([]float64, error) { return RSI(v, 14) }, 15},
+		{"SMA period 1", NewStreamingSMA(1), func(v []float64) ([]float64, error) { return SMA(v, 1) }, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, v := range values {
+				got := tt.stream.Update(v)
+				if ready := tt.stream.Ready(); ready != (i+1 >= tt.warmup) {
+					t.Fatalf("step %d: Ready() = %v", i, ready)
+				}
+				if !tt.stream.Ready() {
+					if got != 0 {
+						t.Fatalf("step %d: warming up returned %.4f, want 0", i, got)
+					}
+					continue
+				}
+
+				// The last batch value over the series so far is the streaming value
+				batch, err := tt.batch(values[:i+1])
+				if err != nil {
+					t.Fatalf("step %d: batch error: %v", i, err)
+				}
+				if want := batch[len(batch)-1]; math.Abs(got-want) > 1e-9 {
+					t.Fatalf("step %d: streaming = %.10f, batch = %.10f", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamingRSIAllGains(t *testing.T) {
+	rsi := NewStreamingRSI(3)
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		rsi.Update(v)
+	}
+	if got := rsi.Value(); got != 100 {
+		t.Errorf("RSI of a rising series = %.2f, want 100", got)
+	}
+}
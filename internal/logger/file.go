@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// maxLogBackups is how many rotated files (path.1 .. path.N) are kept
+const maxLogBackups = 5
+
+// NewWithFile creates a logger that writes to stdout and to the file at
+// path. Once the file would exceed maxSizeMB megabytes it is rotated to
+// path.1, shifting older backups up and keeping at most five. The returned
+// logger also implements io.Closer to release the file.
+func NewWithFile(levelStr, path string, maxSizeMB int) (Logger, error) {
+	if maxSizeMB <= 0 {
+		return nil, fmt.Errorf("max log size must be positive, got %d MB", maxSizeMB)
+	}
+
+	file, err := newRotatingFile(path, int64(maxSizeMB)*1024*1024, maxLogBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StandardLogger{
+		level:  parseLevel(levelStr),
+		logger: log.New(io.MultiWriter(os.Stdout, file), "", 0),
+		closer: file,
+	}, nil
+}
+
+// Close releases the log file, if any
+func (l *StandardLogger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// rotatingFile is an io.WriteCloser that rotates by size. Writes are
+// serialized so concurrent goroutines never interleave or race a rotation.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	backups  int
+	file     *os.File
+	size     int64
+	openFile func(name string, flag int, perm os.FileMode) (*os.File, error)
+}
+
+// newRotatingFile opens path for appending, continuing from its current size
+func newRotatingFile(path string, maxBytes int64, backups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes, backups: backups, openFile: os.OpenFile}
+	file, size, err := r.open()
+	if err != nil {
+		return nil, err
+	}
+	r.file, r.size = file, size
+	return r, nil
+}
+
+// Write appends p, rotating first if p would push the file past maxBytes.
+// A single write larger than maxBytes still goes to a fresh file whole.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open opens or creates the active log file and returns it with its size
+func (r *rotatingFile) open() (*os.File, int64, error) {
+	file, err := r.openFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	return file, info.Size(), nil
+}
+
+// rotate shifts path.N-1 to path.N down to path to path.1, dropping the
+// oldest backup, and reopens an empty file; callers hold mu. The current
+// file stays open until the new one is, so a failed rotation leaves the
+// logger writing to the old file rather than closed.
+func (r *rotatingFile) rotate() error {
+	if r.backups > 0 {
+		os.Remove(r.backupPath(r.backups))
+		for i := r.backups - 1; i >= 1; i-- {
+			if err := os.Rename(r.backupPath(i), r.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate log file: %w", err)
+			}
+		}
+		// The active file is already gone if an earlier reopen failed
+		if err := os.Rename(r.path, r.backupPath(1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	} else if err := os.Truncate(r.path, 0); err != nil {
+		return fmt.Errorf("failed to truncate log file: %w", err)
+	}
+
+	file, size, err := r.open()
+	if err != nil {
+		return err
+	}
+
+	old := r.file
+	r.file, r.size = file, size
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("failed to close rotated log file: %w", err)
+	}
+	return nil
+}
+
+// backupPath returns the path of the nth backup
+func (r *rotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", r.path, n)
+}
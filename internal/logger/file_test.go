@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRotatingFileRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candlecore.log")
+	file, err := newRotatingFile(path, 100, 2)
+	if err != nil {
+		t.Fatalf("newRotatingFile() error: %v", err)
+	}
+	defer file.Close()
+
+	// Each line is 40 bytes, so every third write rotates
+	for i := 0; i < 9; i++ {
+		line := fmt.Sprintf("line %02d %s\n", i, strings.Repeat("x", 31))
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+
+	read := func(p string) string {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("read %s: %v", filepath.Base(p), err)
+		}
+		return string(data)
+	}
+
+	if got := read(path); !strings.HasPrefix(got, "line 08") {
+		t.Errorf("active file starts %q, want line 08", got[:7])
+	}
+	if got := read(path + ".1"); !strings.HasPrefix(got, "line 06") {
+		t.Errorf("backup 1 starts %q, want line 06", got[:7])
+	}
+	if got := read(path + ".2"); !strings.HasPrefix(got, "line 04") {
+		t.Errorf("backup 2 starts %q, want line 04", got[:7])
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("backup 3 exists, want at most 2 backups")
+	}
+}
+
+func TestRotatingFileKeepsWritingWhenReopenFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candlecore.log")
+	file, err := newRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("newRotatingFile() error: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	file.openFile = func(string, int, os.FileMode) (*os.File, error) {
+		return nil, os.ErrPermission
+	}
+	if _, err := file.Write([]byte("second\n")); err == nil {
+		t.Fatal("Write() expected error when the new file cannot be opened")
+	}
+
+	// Once opening works again the next write rotates and succeeds
+	file.openFile = os.OpenFile
+	if _, err := file.Write([]byte("third\n")); err != nil {
+		t.Fatalf("Write() after failed rotation error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if string(data) != "third\n" {
+		t.Errorf("active file = %q, want %q", data, "third\n")
+	}
+}
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candlecore.log")
+	file, err := newRotatingFile(path, 1024, 50)
+	if err != nil {
+		t.Fatalf("newRotatingFile() error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				file.Write([]byte(fmt.Sprintf("goroutine %d line %02d\n", g, i)))
+			}
+		}(g)
+	}
+	wg.Wait()
+	file.Close()
+
+	// Every line must land intact in exactly one file
+	files, _ := filepath.Glob(path + "*")
+	lines := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("read %s: %v", f, err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if !strings.HasPrefix(line, "goroutine ") {
+				t.Fatalf("corrupted line %q", line)
+			}
+			lines++
+		}
+	}
+	if lines != 400 {
+		t.Errorf("lines = %d, want 400", lines)
+	}
+}
+
+func TestNewWithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candlecore.log")
+	log, err := NewWithFile("warn", path, 1)
+	if err != nil {
+		t.Fatalf("NewWithFile() error: %v", err)
+	}
+
+	log.Info("hidden")
+	log.Warn("order rejected", "symbol", "BTC/USD")
+	if err := log.(*StandardLogger).Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if !strings.Contains(string(data), "WARN: order rejected symbol=BTC/USD") || strings.Contains(string(data), "hidden") {
+		t.Errorf("log file = %q, want only the warning", data)
+	}
+
+	if _, err := NewWithFile("info", path, 0); err == nil {
+		t.Error("expected error for non-positive size")
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -30,6 +31,7 @@ const (
 type StandardLogger struct {
 	level  Level
 	logger *log.Logger
+	closer io.Closer // log file opened by NewWithFile, nil for stdout only
 }
 
 // New creates a new logger with the specified level
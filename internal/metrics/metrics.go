@@ -0,0 +1,326 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+// TradeReturns returns each trade's net P&L as a fraction of its entry value
+func TradeReturns(trades []*engine.Trade) []float64 {
+	returns := make([]float64, 0, len(trades))
+	for _, t := range trades {
+		entryValue := t.EntryPrice * t.Quantity
+		if entryValue <= 0 {
+			continue
+		}
+		returns = append(returns, t.NetPnL/entryValue)
+	}
+	return returns
+}
+
+// SharpeRatio computes the per-trade Sharpe ratio: the mean excess return
+// over riskFreeRate divided by the sample standard deviation of returns.
+// riskFreeRate is per trade (use 0 for none). Fewer than two trades or zero
+// variance yields 0 rather than NaN or infinity.
+func SharpeRatio(trades []*engine.Trade, riskFreeRate float64) float64 {
+	returns := TradeReturns(trades)
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean := average(returns)
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	if stdDev == 0 {
+		return 0
+	}
+
+	return (mean - riskFreeRate) / stdDev
+}
+
+// SortinoRatio computes the per-trade Sortino ratio: the mean excess return
+// over riskFreeRate divided by the downside deviation, which only counts
+// returns below riskFreeRate. Fewer than two trades or no downside yields 0.
+func SortinoRatio(trades []*engine.Trade, riskFreeRate float64) float64 {
+	returns := TradeReturns(trades)
+	if len(returns) < 2 {
+		return 0
+	}
+
+	downside := 0.0
+	for _, r := range returns {
+		if r < riskFreeRate {
+			downside += (r - riskFreeRate) * (r - riskFreeRate)
+		}
+	}
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+	if downsideDev == 0 {
+		return 0
+	}
+
+	return (average(returns) - riskFreeRate) / downsideDev
+}
+
+// average returns the arithmetic mean of values
+func average(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Drawdown describes peak-to-trough declines of the trade equity curve
+type Drawdown struct {
+	// Absolute is the largest decline from a peak, in quote currency
+	Absolute float64
+
+	// Percent is the largest decline relative to its peak (e.g. 12.5 for 12.5%)
+	Percent float64
+
+	// LongestTrades is the most consecutive trades spent below a prior peak,
+	// including an unrecovered drawdown at the end of the series
+	LongestTrades int
+}
+
+// EquityCurve returns the balance after each trade, starting with initialBalance
+func EquityCurve(initialBalance float64, trades []*engine.Trade) []float64 {
+	curve := make([]float64, 0, len(trades)+1)
+	equity := initialBalance
+	curve = append(curve, equity)
+	for _, t := range trades {
+		equity += t.NetPnL
+		curve = append(curve, equity)
+	}
+	return curve
+}
+
+// MaxDrawdown computes drawdown statistics over the equity curve built from
+// trades in the order given
+func MaxDrawdown(initialBalance float64, trades []*engine.Trade) Drawdown {
+	var dd Drawdown
+
+	peak := initialBalance
+	underwater := 0
+	for _, equity := range EquityCurve(initialBalance, trades)[1:] {
+		if equity >= peak {
+			peak = equity
+			underwater = 0
+			continue
+		}
+
+		underwater++
+		if underwater > dd.LongestTrades {
+			dd.LongestTrades = underwater
+		}
+
+		decline := peak - equity
+		if decline > dd.Absolute {
+			dd.Absolute = decline
+		}
+		if peak > 0 && decline/peak*100 > dd.Percent {
+			dd.Percent = decline / peak * 100
+		}
+	}
+
+	return dd
+}
+
+// FeeSummary breaks down how much of a strategy's edge goes to fees
+type FeeSummary struct {
+	// TotalFees is the sum of fees over all trades
+	TotalFees float64
+
+	// AverageFee is the mean fee per trade
+	AverageFee float64
+
+	// PercentOfGrossPnL is TotalFees relative to gross P&L (e.g. 25 for 25%).
+	// It is 0 when gross P&L is not positive, where the ratio has no meaning.
+	PercentOfGrossPnL float64
+
+	// FlippedByFees counts trades that were profitable before fees and
+	// unprofitable after them
+	FlippedByFees int
+}
+
+// FeeReport summarizes the fees paid across trades
+func FeeReport(trades []*engine.Trade) FeeSummary {
+	var report FeeSummary
+	if len(trades) == 0 {
+		return report
+	}
+
+	grossPnL := 0.0
+	for _, t := range trades {
+		report.TotalFees += t.Fee
+		grossPnL += t.PnL
+		if t.PnL > 0 && t.NetPnL <= 0 {
+			report.FlippedByFees++
+		}
+	}
+
+	report.AverageFee = report.TotalFees / float64(len(trades))
+	if grossPnL > 0 {
+		report.PercentOfGrossPnL = report.TotalFees / grossPnL * 100
+	}
+
+	return report
+}
+
+// DayCount is the number of trades closed on one UTC day
+type DayCount struct {
+	Day    time.Time
+	Trades int
+}
+
+// Activity describes how often and how clustered trading was
+type Activity struct {
+	// Days holds every UTC day from the first to the last trade close in
+	// order, including days without trades so gaps and clusters show
+	Days []DayCount
+
+	// ActiveDays counts the days with at least one trade
+	ActiveDays int
+
+	// AveragePerActiveDay is the mean number of trades on active days
+	AveragePerActiveDay float64
+
+	// LongestLosingStreak is the most consecutive trades, in the order
+	// given, that did not make a net profit
+	LongestLosingStreak int
+}
+
+// TradeActivity buckets trades by the UTC day they closed and measures
+// trading frequency and loss clustering
+func TradeActivity(trades []*engine.Trade) Activity {
+	var activity Activity
+	if len(trades) == 0 {
+		return activity
+	}
+
+	counts := make(map[time.Time]int)
+	var first, last time.Time
+	streak := 0
+	for i, t := range trades {
+		day := utcDay(t.ClosedAt)
+		counts[day]++
+		if i == 0 || day.Before(first) {
+			first = day
+		}
+		if i == 0 || day.After(last) {
+			last = day
+		}
+
+		if t.NetPnL > 0 {
+			streak = 0
+			continue
+		}
+		streak++
+		if streak > activity.LongestLosingStreak {
+			activity.LongestLosingStreak = streak
+		}
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		activity.Days = append(activity.Days, DayCount{Day: day, Trades: counts[day]})
+	}
+	activity.ActiveDays = len(counts)
+	activity.AveragePerActiveDay = float64(len(trades)) / float64(activity.ActiveDays)
+
+	return activity
+}
+
+// utcDay returns midnight UTC of the day t falls on
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Correlation returns the Pearson correlation of two equally long series,
+// such as equity curves sampled on the same candles. It returns 0 when the
+// lengths differ, there are fewer than two points, or either series is flat.
+func Correlation(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) < 2 {
+		return 0
+	}
+
+	meanA, meanB := average(a), average(b)
+	covariance, varianceA, varianceB := 0.0, 0.0, 0.0
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		covariance += da * db
+		varianceA += da * da
+		varianceB += db * db
+	}
+	if varianceA == 0 || varianceB == 0 {
+		return 0
+	}
+
+	return covariance / math.Sqrt(varianceA*varianceB)
+}
+
+// CorrelationMatrix returns the Pearson correlation of log close-to-close
+// returns for every pair of symbols, keyed both ways. Each pair is aligned
+// on the timestamps both series share, so differing lengths and gaps only
+// reduce the sample. Pairs with fewer than two shared returns are 0 and
+// every symbol correlates 1 with itself.
+func CorrelationMatrix(series map[string][]engine.Candle) map[string]map[string]float64 {
+	matrix := make(map[string]map[string]float64, len(series))
+	for symbol := range series {
+		matrix[symbol] = map[string]float64{symbol: 1}
+	}
+
+	symbols := make([]string, 0, len(series))
+	for symbol := range series {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for i, a := range symbols {
+		for _, b := range symbols[i+1:] {
+			returnsA, returnsB := alignedLogReturns(series[a], series[b])
+			corr := Correlation(returnsA, returnsB)
+			matrix[a][b] = corr
+			matrix[b][a] = corr
+		}
+	}
+	return matrix
+}
+
+// alignedLogReturns returns the log returns of a and b between consecutive
+// timestamps present in both, skipping steps with a non-positive close
+func alignedLogReturns(a, b []engine.Candle) ([]float64, []float64) {
+	closesB := make(map[int64]float64, len(b))
+	for _, c := range b {
+		closesB[c.Timestamp.UnixNano()] = c.Close
+	}
+
+	type pair struct {
+		at   int64
+		a, b float64
+	}
+	var shared []pair
+	for _, c := range a {
+		if closeB, ok := closesB[c.Timestamp.UnixNano()]; ok {
+			shared = append(shared, pair{c.Timestamp.UnixNano(), c.Close, closeB})
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool { return shared[i].at < shared[j].at })
+
+	var returnsA, returnsB []float64
+	for i := 1; i < len(shared); i++ {
+		prev, cur := shared[i-1], shared[i]
+		if prev.a <= 0 || prev.b <= 0 || cur.a <= 0 || cur.b <= 0 {
+			continue
+		}
+		returnsA = append(returnsA, math.Log(cur.a/prev.a))
+		returnsB = append(returnsB, math.Log(cur.b/prev.b))
+	}
+	return returnsA, returnsB
+}
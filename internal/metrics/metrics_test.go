@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+)
+
+// tradesFromPnL builds trades with a 100 entry value so NetPnL maps to percent
+func tradesFromPnL(pnls ...float64) []*engine.Trade {
+	trades := make([]*engine.Trade, len(pnls))
+	for i, pnl := range pnls {
+		trades[i] = &engine.Trade{EntryPrice: 100, Quantity: 1, NetPnL: pnl}
+	}
+	return trades
+}
+
+func TestSharpeAndSortino(t *testing.T) {
+	// Returns 10%, -5%, 5%, 0%: mean 2.5%, sample stdev sqrt(0.0125/3),
+	// downside deviation sqrt(0.0025/4) = 2.5%
+	trades := tradesFromPnL(10, -5, 5, 0)
+
+	if got, want := SharpeRatio(trades, 0), 0.025/math.Sqrt(0.0125/3); math.Abs(got-want) > 1e-9 {
+		t.Errorf("SharpeRatio() = %.6f, want %.6f", got, want)
+	}
+	if got := SortinoRatio(trades, 0); math.Abs(got-1) > 1e-9 {
+		t.Errorf("SortinoRatio() = %.6f, want 1", got)
+	}
+
+	// A 1% per-trade risk-free rate lowers both ratios
+	if got, want := SharpeRatio(trades, 0.01), 0.015/math.Sqrt(0.0125/3); math.Abs(got-want) > 1e-9 {
+		t.Errorf("SharpeRatio(rf=0.01) = %.6f, want %.6f", got, want)
+	}
+}
+
+func TestRatiosDegenerateCases(t *testing.T) {
+	tests := []struct {
+		name   string
+		trades []*engine.Trade
+	}{
+		{"no trades", nil},
+		{"single trade", tradesFromPnL(5)},
+		{"zero variance", tradesFromPnL(2, 2, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SharpeRatio(tt.trades, 0); got != 0 {
+				t.Errorf("SharpeRatio() = %v, want 0", got)
+			}
+			if got := SortinoRatio(tt.trades, 0); got != 0 {
+				t.Errorf("SortinoRatio() = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	// Equity: 1000 -> 1100 -> 1000 -> 900 -> 1150 -> 1100
+	trades := tradesFromPnL(100, -100, -100, 250, -50)
+
+	dd := MaxDrawdown(1000, trades)
+	if dd.Absolute != 200 {
+		t.Errorf("Absolute = %.2f, want 200", dd.Absolute)
+	}
+	if want := 200.0 / 1100 * 100; math.Abs(dd.Percent-want) > 1e-9 {
+		t.Errorf("Percent = %.4f, want %.4f", dd.Percent, want)
+	}
+	if dd.LongestTrades != 2 {
+		t.Errorf("LongestTrades = %d, want 2", dd.LongestTrades)
+	}
+}
+
+func TestMaxDrawdownFromInitialBalance(t *testing.T) {
+	// Losing from the start and never recovering
+	dd := MaxDrawdown(1000, tradesFromPnL(-100, -50, 20))
+	if dd.Absolute != 150 {
+		t.Errorf("Absolute = %.2f, want 150", dd.Absolute)
+	}
+	if dd.Percent != 15 {
+		t.Errorf("Percent = %.2f, want 15", dd.Percent)
+	}
+	if dd.LongestTrades != 3 {
+		t.Errorf("LongestTrades = %d, want 3", dd.LongestTrades)
+	}
+
+	if dd := MaxDrawdown(1000, tradesFromPnL(10, 20)); dd != (Drawdown{}) {
+		t.Errorf("MaxDrawdown() on rising curve = %+v, want zero", dd)
+	}
+}
+
+func TestFeeReport(t *testing.T) {
+	trades := []*engine.Trade{
+		{PnL: 100, Fee: 10, NetPnL: 90},
+		{PnL: 5, Fee: 8, NetPnL: -3}, // flipped to a loss by fees
+		{PnL: 6, Fee: 6, NetPnL: 0},  // flipped to break-even by fees
+		{PnL: -20, Fee: 4, NetPnL: -24},
+	}
+
+	got := FeeReport(trades)
+	if got.TotalFees != 28 {
+		t.Errorf("TotalFees = %.2f, want 28", got.TotalFees)
+	}
+	if got.AverageFee != 7 {
+		t.Errorf("AverageFee = %.2f, want 7", got.AverageFee)
+	}
+	// Gross P&L is 91
+	if want := 28.0 / 91 * 100; math.Abs(got.PercentOfGrossPnL-want) > 1e-9 {
+		t.Errorf("PercentOfGrossPnL = %.4f, want %.4f", got.PercentOfGrossPnL, want)
+	}
+	if got.FlippedByFees != 2 {
+		t.Errorf("FlippedByFees = %d, want 2", got.FlippedByFees)
+	}
+}
+
+func TestFeeReportDegenerateCases(t *testing.T) {
+	if got := FeeReport(nil); got != (FeeSummary{}) {
+		t.Errorf("FeeReport(nil) = %+v, want zero", got)
+	}
+
+	losing := FeeReport([]*engine.Trade{{PnL: -10, Fee: 1, NetPnL: -11}})
+	if losing.PercentOfGrossPnL != 0 || losing.FlippedByFees != 0 {
+		t.Errorf("losing FeeReport = %+v, want no percentage and no flips", losing)
+	}
+}
+
+func TestTradeActivity(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	trade := func(days int, hour int, pnl float64) *engine.Trade {
+		return &engine.Trade{ClosedAt: day.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour), NetPnL: pnl}
+	}
+	trades := []*engine.Trade{
+		trade(0, 9, 50),
+		trade(0, 15, -10),
+		trade(0, 23, -5),
+		trade(3, 1, -20), // losing streak of four across the idle gap
+		trade(3, 2, 0),   // break-even counts toward the streak
+		trade(3, 4, 30),
+		trade(4, 12, -8),
+	}
+
+	got := TradeActivity(trades)
+
+	wantCounts := []int{3, 0, 0, 3, 1}
+	if len(got.Days) != len(wantCounts) {
+		t.Fatalf("Days = %d, want %d including idle days", len(got.Days), len(wantCounts))
+	}
+	for i, want := range wantCounts {
+		if !got.Days[i].Day.Equal(day.AddDate(0, 0, i)) {
+			t.Errorf("Days[%d].Day = %s, want %s", i, got.Days[i].Day, day.AddDate(0, 0, i))
+		}
+		if got.Days[i].Trades != want {
+			t.Errorf("Days[%d].Trades = %d, want %d", i, got.Days[i].Trades, want)
+		}
+	}
+	if got.ActiveDays != 3 {
+		t.Errorf("ActiveDays = %d, want 3", got.ActiveDays)
+	}
+	if want := 7.0 / 3; math.Abs(got.AveragePerActiveDay-want) > 1e-9 {
+		t.Errorf("AveragePerActiveDay = %.4f, want %.4f", got.AveragePerActiveDay, want)
+	}
+	if got.LongestLosingStreak != 4 {
+		t.Errorf("LongestLosingStreak = %d, want 4", got.LongestLosingStreak)
+	}
+
+	if empty := TradeActivity(nil); empty.Days != nil || empty.ActiveDays != 0 || empty.LongestLosingStreak != 0 {
+		t.Errorf("TradeActivity(nil) = %+v, want zero", empty)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{"identical", []float64{1, 2, 3, 4}, []float64{1, 2, 3, 4}, 1},
+		{"scaled", []float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1},
+		{"inverse", []float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1},
+		{"partial", []float64{1, 2, 3}, []float64{1, 3, 2}, 0.5},
+		{"flat", []float64{1, 2, 3}, []float64{5, 5, 5}, 0},
+		{"unequal lengths", []float64{1, 2, 3}, []float64{1, 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Correlation(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Correlation() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+// candleSeries builds hourly candles from closes, starting offset hours in
+func candleSeries(offset int, closes ...float64) []engine.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]engine.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = engine.Candle{Timestamp: start.Add(time.Duration(offset+i) * time.Hour), Close: c}
+	}
+	return candles
+}
+
+func TestCorrelationMatrix(t *testing.T) {
+	btc := candleSeries(0, 100, 110, 99, 104, 120, 114)
+	// ETH doubles BTC's price over a shorter, later window
+	eth := candleSeries(1, 220, 198, 208, 240, 228)
+	// Inverting each step's return mirrors BTC
+	inverse := []float64{100}
+	for i := 1; i < len(btc); i++ {
+		inverse = append(inverse, inverse[i-1]*btc[i-1].Close/btc[i].Close)
+	}
+	short := candleSeries(0, inverse...)
+
+	m := CorrelationMatrix(map[string][]engine.Candle{"BTC": btc, "ETH": eth, "SHORT": short})
+
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"BTC", "BTC", 1},
+		{"BTC", "ETH", 1},
+		{"ETH", "BTC", 1},
+		{"BTC", "SHORT", -1},
+		{"ETH", "SHORT", -1},
+	}
+	for _, tt := range tests {
+		if got := m[tt.a][tt.b]; math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("m[%s][%s] = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// Series without two shared returns do not correlate
+	m = CorrelationMatrix(map[string][]engine.Candle{"BTC": btc, "LATE": candleSeries(5, 1, 2, 3)})
+	if got := m["BTC"]["LATE"]; got != 0 {
+		t.Errorf("m[BTC][LATE] = %.4f, want 0 with one shared timestamp", got)
+	}
+}
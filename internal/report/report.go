@@ -0,0 +1,411 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/metrics"
+)
+
+// Param is a named backtest parameter shown in the report
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Report holds everything needed to render a backtest report
+type Report struct {
+	Title          string
+	Parameters     []Param
+	InitialBalance float64
+	FinalBalance   float64
+	Trades         []*engine.Trade
+	GeneratedAt    time.Time
+}
+
+// summary holds the metrics table values derived from the trade list
+type summary struct {
+	totalTrades  int
+	wins         int
+	losses       int
+	winRate      float64
+	grossProfit  float64
+	grossLoss    float64
+	profitFactor float64
+	netPnL       float64
+	totalFees    float64
+	fees         metrics.FeeSummary
+	returnPct    float64
+	drawdown     metrics.Drawdown
+	sharpe       float64
+	sortino      float64
+	activity     metrics.Activity
+}
+
+const (
+	// histogramWidth is the bar length of the busiest row in the activity
+	// histogram
+	histogramWidth = 40
+
+	// histogramMaxRows is the most rows the histogram draws at one
+	// granularity: spans longer than this many days are drawn per week,
+	// and longer than this many weeks per month
+	histogramMaxRows = 60
+)
+
+// Write renders the report to path, choosing HTML or Markdown by extension
+func Write(path string, r Report) error {
+	var render func(io.Writer, Report) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		render = WriteHTML
+	case ".md", ".markdown":
+		render = WriteMarkdown
+	default:
+		return fmt.Errorf("unsupported report format %q: use .html or .md", filepath.Ext(path))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+
+	if err := render(file, r); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close report file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMarkdown renders the report as Markdown with an ASCII equity sparkline
+func WriteMarkdown(w io.Writer, r Report) error {
+	s := summarize(r)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", r.title())
+	fmt.Fprintf(&b, "Generated %s\n\n", r.generatedAt().Format(time.RFC3339))
+
+	if len(r.Parameters) > 0 {
+		b.WriteString("## Parameters\n\n| Parameter | Value |\n|---|---|\n")
+		for _, p := range r.Parameters {
+			fmt.Fprintf(&b, "| %s | %s |\n", escapeMarkdown(p.Name), escapeMarkdown(p.Value))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Metrics\n\n| Metric | Value |\n|---|---|\n")
+	for _, row := range s.rows(r) {
+		fmt.Fprintf(&b, "| %s | %s |\n", row[0], row[1])
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Equity Curve\n\n```\n")
+	b.WriteString(sparkline(equityCurve(r)))
+	b.WriteString("\n```\n\n")
+
+	if len(s.activity.Days) > 0 {
+		b.WriteString("## Trading Activity\n\n```\n")
+		b.WriteString(histogram(s.activity))
+		b.WriteString("```\n\n")
+	}
+
+	b.WriteString("## Trades\n\n")
+	if len(r.Trades) == 0 {
+		b.WriteString("No trades were executed.\n")
+	} else {
+		b.WriteString("| # | Symbol | Side | Opened | Closed | Entry | Exit | Quantity | PnL | Fee | Net PnL |\n")
+		b.WriteString("|---|---|---|---|---|---|---|---|---|---|---|\n")
+		for i, t := range r.Trades {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %.2f | %.2f | %.6f | %.2f | %.2f | %.2f |\n",
+				i+1, escapeMarkdown(t.Symbol), t.Side,
+				t.OpenedAt.Format(time.RFC3339), t.ClosedAt.Format(time.RFC3339),
+				t.EntryPrice, t.ExitPrice, t.Quantity, t.PnL, t.Fee, t.NetPnL)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML renders the report as a standalone HTML page with an SVG equity chart
+func WriteHTML(w io.Writer, r Report) error {
+	s := summarize(r)
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(r.title()))
+	b.WriteString("<style>body{font-family:sans-serif;margin:2em;color:#222}table{border-collapse:collapse;margin-bottom:1.5em}" +
+		"th,td{border:1px solid #ccc;padding:4px 8px;text-align:right}th{background:#f2f2f2}td:first-child,th:first-child{text-align:left}" +
+		".pos{color:#1a7f37}.neg{color:#cf222e}</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p>Generated %s</p>\n", html.EscapeString(r.title()), r.generatedAt().Format(time.RFC3339))
+
+	if len(r.Parameters) > 0 {
+		b.WriteString("<h2>Parameters</h2>\n<table>\n<tr><th>Parameter</th><th>Value</th></tr>\n")
+		for _, p := range r.Parameters {
+			fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td></tr>\n", html.EscapeString(p.Name), html.EscapeString(p.Value))
+		}
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("<h2>Metrics</h2>\n<table>\n<tr><th>Metric</th><th>Value</th></tr>\n")
+	for _, row := range s.rows(r) {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td></tr>\n", row[0], row[1])
+	}
+	b.WriteString("</table>\n")
+
+	b.WriteString("<h2>Equity Curve</h2>\n")
+	b.WriteString(svgChart(equityCurve(r), 800, 240))
+	b.WriteString("\n")
+
+	if len(s.activity.Days) > 0 {
+		b.WriteString("<h2>Trading Activity</h2>\n<pre>")
+		b.WriteString(html.EscapeString(histogram(s.activity)))
+		b.WriteString("</pre>\n")
+	}
+
+	b.WriteString("<h2>Trades</h2>\n")
+	if len(r.Trades) == 0 {
+		b.WriteString("<p>No trades were executed.</p>\n")
+	} else {
+		b.WriteString("<table>\n<tr><th>#</th><th>Symbol</th><th>Side</th><th>Opened</th><th>Closed</th>" +
+			"<th>Entry</th><th>Exit</th><th>Quantity</th><th>PnL</th><th>Fee</th><th>Net PnL</th></tr>\n")
+		for i, t := range r.Trades {
+			class := "pos"
+			if t.NetPnL < 0 {
+				class = "neg"
+			}
+			fmt.Fprintf(&b, "<tr><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td>"+
+				"<td>%.2f</td><td>%.2f</td><td>%.6f</td><td>%.2f</td><td>%.2f</td><td class=\"%s\">%.2f</td></tr>\n",
+				i+1, html.EscapeString(t.Symbol), t.Side,
+				t.OpenedAt.Format(time.RFC3339), t.ClosedAt.Format(time.RFC3339),
+				t.EntryPrice, t.ExitPrice, t.Quantity, t.PnL, t.Fee, class, t.NetPnL)
+		}
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// title returns the report title or a default
+func (r Report) title() string {
+	if r.Title == "" {
+		return "Backtest Report"
+	}
+	return r.Title
+}
+
+// generatedAt returns the generation time or now
+func (r Report) generatedAt() time.Time {
+	if r.GeneratedAt.IsZero() {
+		return time.Now()
+	}
+	return r.GeneratedAt
+}
+
+// summarize computes the metrics table from the trade list
+func summarize(r Report) summary {
+	s := summary{totalTrades: len(r.Trades)}
+
+	for _, t := range r.Trades {
+		s.netPnL += t.NetPnL
+		s.totalFees += t.Fee
+		if t.NetPnL > 0 {
+			s.wins++
+			s.grossProfit += t.NetPnL
+		} else {
+			s.losses++
+			s.grossLoss += -t.NetPnL
+		}
+	}
+
+	if s.totalTrades > 0 {
+		s.winRate = float64(s.wins) / float64(s.totalTrades) * 100
+	}
+	if s.grossLoss > 0 {
+		s.profitFactor = s.grossProfit / s.grossLoss
+	}
+	if r.InitialBalance > 0 {
+		s.returnPct = (r.FinalBalance - r.InitialBalance) / r.InitialBalance * 100
+	}
+	s.drawdown = metrics.MaxDrawdown(r.InitialBalance, r.Trades)
+	s.fees = metrics.FeeReport(r.Trades)
+	s.sharpe = metrics.SharpeRatio(r.Trades, 0)
+	s.sortino = metrics.SortinoRatio(r.Trades, 0)
+	s.activity = metrics.TradeActivity(r.Trades)
+
+	return s
+}
+
+// rows formats the metrics table
+func (s summary) rows(r Report) [][2]string {
+	profitFactor := "n/a"
+	if s.grossLoss > 0 {
+		profitFactor = fmt.Sprintf("%.2f", s.profitFactor)
+	}
+
+	return [][2]string{
+		{"Initial Balance", fmt.Sprintf("%.2f", r.InitialBalance)},
+		{"Final Balance", fmt.Sprintf("%.2f", r.FinalBalance)},
+		{"Return", fmt.Sprintf("%.2f%%", s.returnPct)},
+		{"Net PnL", fmt.Sprintf("%.2f", s.netPnL)},
+		{"Total Fees", fmt.Sprintf("%.2f", s.totalFees)},
+		{"Average Fee", fmt.Sprintf("%.2f", s.fees.AverageFee)},
+		{"Fees / Gross PnL", fmt.Sprintf("%.2f%%", s.fees.PercentOfGrossPnL)},
+		{"Trades Flipped by Fees", fmt.Sprintf("%d", s.fees.FlippedByFees)},
+		{"Total Trades", fmt.Sprintf("%d", s.totalTrades)},
+		{"Winning Trades", fmt.Sprintf("%d", s.wins)},
+		{"Losing Trades", fmt.Sprintf("%d", s.losses)},
+		{"Win Rate", fmt.Sprintf("%.2f%%", s.winRate)},
+		{"Profit Factor", profitFactor},
+		{"Sharpe Ratio", fmt.Sprintf("%.2f", s.sharpe)},
+		{"Sortino Ratio", fmt.Sprintf("%.2f", s.sortino)},
+		{"Max Drawdown", fmt.Sprintf("%.2f (%.2f%%)", s.drawdown.Absolute, s.drawdown.Percent)},
+		{"Longest Drawdown", fmt.Sprintf("%d trades", s.drawdown.LongestTrades)},
+		{"Active Days", fmt.Sprintf("%d", s.activity.ActiveDays)},
+		{"Trades / Active Day", fmt.Sprintf("%.2f", s.activity.AveragePerActiveDay)},
+		{"Longest Losing Streak", fmt.Sprintf("%d trades", s.activity.LongestLosingStreak)},
+	}
+}
+
+// equityCurve returns the balance after each trade, starting at the initial balance
+func equityCurve(r Report) []float64 {
+	return metrics.EquityCurve(r.InitialBalance, r.Trades)
+}
+
+// sparkline renders values as a single line of block characters
+func sparkline(values []float64) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	min, max := bounds(values)
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if max > min {
+			idx = int(math.Round((v - min) / (max - min) * float64(len(levels)-1)))
+		}
+		b.WriteRune(levels[idx])
+	}
+	fmt.Fprintf(&b, "  %.2f -> %.2f (min %.2f, max %.2f)", values[0], values[len(values)-1], min, max)
+	return b.String()
+}
+
+// histogram renders one line per day with a bar scaled to the busiest row,
+// so idle stretches and clusters of trades stand out. Long spans are drawn
+// per week (labelled with the Monday) or per month to keep the row count
+// near histogramMaxRows.
+func histogram(activity metrics.Activity) string {
+	rows, layout := bucketDays(activity.Days)
+
+	busiest := 0
+	for _, r := range rows {
+		if r.Trades > busiest {
+			busiest = r.Trades
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range rows {
+		bar := 0
+		if busiest > 0 {
+			bar = int(math.Ceil(float64(r.Trades) / float64(busiest) * histogramWidth))
+		}
+		fmt.Fprintf(&b, "%-10s %s%s %d\n", r.Day.Format(layout),
+			strings.Repeat("█", bar), strings.Repeat(" ", histogramWidth-bar), r.Trades)
+	}
+	return b.String()
+}
+
+// bucketDays merges consecutive days into weeks or months when there are
+// more than histogramMaxRows of them, returning the rows and the label
+// layout for their start dates
+func bucketDays(days []metrics.DayCount) ([]metrics.DayCount, string) {
+	if len(days) <= histogramMaxRows {
+		return days, "2006-01-02"
+	}
+
+	start := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	layout := "2006-01"
+	if len(days) <= histogramMaxRows*7 {
+		start = func(day time.Time) time.Time {
+			offset := (int(day.Weekday()) + 6) % 7
+			return day.AddDate(0, 0, -offset)
+		}
+		layout = "2006-01-02"
+	}
+
+	var rows []metrics.DayCount
+	for _, d := range days {
+		bucket := start(d.Day)
+		if n := len(rows); n > 0 && rows[n-1].Day.Equal(bucket) {
+			rows[n-1].Trades += d.Trades
+			continue
+		}
+		rows = append(rows, metrics.DayCount{Day: bucket, Trades: d.Trades})
+	}
+	return rows, layout
+}
+
+// svgChart renders values as an inline SVG line chart
+func svgChart(values []float64, width, height int) string {
+	min, max := bounds(values)
+	pad := 10.0
+	plotW := float64(width) - 2*pad
+	plotH := float64(height) - 2*pad
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := pad
+		if len(values) > 1 {
+			x += float64(i) / float64(len(values)-1) * plotW
+		}
+		y := pad + plotH/2
+		if max > min {
+			y = pad + (1-(v-min)/(max-min))*plotH
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	return fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">"+
+		"<rect width=\"100%%\" height=\"100%%\" fill=\"#fafafa\" stroke=\"#ccc\"/>"+
+		"<polyline fill=\"none\" stroke=\"#0969da\" stroke-width=\"2\" points=\"%s\"/>"+
+		"<text x=\"%.0f\" y=\"%.0f\" font-size=\"11\">%.2f</text>"+
+		"<text x=\"%.0f\" y=\"%.0f\" font-size=\"11\">%.2f</text></svg>",
+		width, height, width, height, strings.Join(points, " "),
+		pad+2, pad+10, max, pad+2, float64(height)-pad-2, min)
+}
+
+// bounds returns the minimum and maximum of values
+func bounds(values []float64) (float64, float64) {
+	min, max := values[0], values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
+
+// escapeMarkdown escapes characters that would break a Markdown table cell
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/metrics"
+)
+
+func sampleReport() Report {
+	opened := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Report{
+		Title:          "MA Crossover",
+		Parameters:     []Param{{Name: "fast", Value: "10"}, {Name: "slow", Value: "30"}},
+		InitialBalance: 10000,
+		FinalBalance:   10150,
+		GeneratedAt:    opened,
+		Trades: []*engine.Trade{
+			{ID: "t1", Symbol: "BTC/USD", Side: engine.OrderSideBuy, EntryPrice: 100, ExitPrice: 110, Quantity: 20, PnL: 200, Fee: 4, NetPnL: 196, OpenedAt: opened, ClosedAt: opened.Add(time.Hour)},
+			{ID: "t2", Symbol: "BTC/USD", Side: engine.OrderSideBuy, EntryPrice: 110, ExitPrice: 108, Quantity: 20, PnL: -40, Fee: 6, NetPnL: -46, OpenedAt: opened.Add(2 * time.Hour), ClosedAt: opened.Add(3 * time.Hour)},
+		},
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		file     string
+		contains []string
+	}{
+		{"report.md", []string{"# MA Crossover", "| fast | 10 |", "| Win Rate | 50.00% |", "| Fees / Gross PnL | 6.25% |", "| Max Drawdown | 46.00", "| 2 | BTC/USD | buy |", "| Longest Losing Streak | 1 trades |", "| Sharpe Ratio | 0.46 |", "| Sortino Ratio | 2.61 |", "## Trading Activity", "2024-01-01 ████"}},
+		{"report.html", []string{"<title>MA Crossover</title>", "<svg", "<td>Profit Factor</td><td>4.26</td>", "class=\"neg\">-46.00", "<td>Trades / Active Day</td><td>2.00</td>", "<td>Sharpe Ratio</td><td>0.46</td>", "<pre>2024-01-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := Write(path, sampleReport()); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("report missing %q", want)
+				}
+			}
+		})
+	}
+
+	if err := Write(filepath.Join(dir, "report.pdf"), sampleReport()); err == nil {
+		t.Error("Write() with unsupported extension expected error")
+	}
+}
+
+func TestHistogramBucketsLongSpans(t *testing.T) {
+	// 2024-01-01 is a Monday
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tradesOver := func(days ...int) []*engine.Trade {
+		var trades []*engine.Trade
+		for _, d := range days {
+			closed := start.AddDate(0, 0, d)
+			trades = append(trades, &engine.Trade{OpenedAt: closed, ClosedAt: closed})
+		}
+		return trades
+	}
+
+	tests := []struct {
+		name      string
+		trades    []*engine.Trade
+		wantRows  int
+		wantFirst string
+	}{
+		{"daily", tradesOver(0, 1, 59), 60, "2024-01-01 " + strings.Repeat("█", histogramWidth) + " 1"},
+		{"weekly", tradesOver(0, 3, 7, 99), 15, "2024-01-01 " + strings.Repeat("█", histogramWidth) + " 2"},
+		{"monthly", tradesOver(0, 10, 40, 730), 24, "2024-01    " + strings.Repeat("█", histogramWidth) + " 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := strings.Split(strings.TrimSuffix(histogram(metrics.TradeActivity(tt.trades)), "\n"), "\n")
+			if len(rows) != tt.wantRows {
+				t.Errorf("rows = %d, want %d", len(rows), tt.wantRows)
+			}
+			if rows[0] != tt.wantFirst {
+				t.Errorf("first row = %q, want %q", rows[0], tt.wantFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package resample
+
+import (
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+)
+
+// CandleAggregator builds candles from a stream of individual trades so a
+// tick feed can drive the engine. Buckets are aligned the same way as
+// Resample, to multiples of the timeframe since the Unix epoch (UTC).
+type CandleAggregator struct {
+	duration time.Duration
+	bucket   engine.Candle
+	first    time.Time // earliest trade in the current bucket
+	last     time.Time // latest trade in the current bucket
+	open     bool      // whether a bucket has received any trade
+}
+
+// NewCandleAggregator creates an aggregator for a valid timeframe
+func NewCandleAggregator(tf exchange.Timeframe) *CandleAggregator {
+	return &CandleAggregator{duration: tf.ToDuration()}
+}
+
+// AddTrade adds a trade to the current bucket. When the trade falls in a
+// later bucket, the completed candle is returned with true and the trade
+// starts the next bucket. Trades may arrive out of order within a bucket:
+// they extend the high and low, and only move the open or close when they
+// are earlier or later than every trade seen so far. Trades belonging to an
+// already completed bucket are dropped.
+func (a *CandleAggregator) AddTrade(price, volume float64, ts time.Time) (*engine.Candle, bool) {
+	start := ts.UTC().Truncate(a.duration)
+
+	if !a.open {
+		a.start(price, volume, ts, start)
+		return nil, false
+	}
+
+	switch {
+	case start.After(a.bucket.Timestamp):
+		completed := a.bucket
+		a.start(price, volume, ts, start)
+		return &completed, true
+	case start.Before(a.bucket.Timestamp):
+		return nil, false
+	}
+
+	if price > a.bucket.High {
+		a.bucket.High = price
+	}
+	if price < a.bucket.Low {
+		a.bucket.Low = price
+	}
+	if ts.Before(a.first) {
+		a.first = ts
+		a.bucket.Open = price
+	}
+	if !ts.Before(a.last) {
+		a.last = ts
+		a.bucket.Close = price
+	}
+	a.bucket.Volume += volume
+
+	return nil, false
+}
+
+// start begins a new bucket with a single trade
+func (a *CandleAggregator) start(price, volume float64, ts, bucketStart time.Time) {
+	a.bucket = engine.Candle{
+		Timestamp: bucketStart,
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+		Volume:    volume,
+	}
+	a.first, a.last = ts, ts
+	a.open = true
+}
@@ -0,0 +1,59 @@
+package resample
+
+import (
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+)
+
+func TestCandleAggregator(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	agg := NewCandleAggregator(exchange.Timeframe1m)
+	trades := []struct {
+		price, volume float64
+		ts            time.Time
+	}{
+		{100, 1, at(5)},
+		{103, 2, at(20)},
+		{98, 1, at(40)},
+		// Out of order: earlier than every trade so far, so it becomes the open
+		{101, 0.5, at(1)},
+		// Out of order but not the latest, so it only extends the high
+		{105, 1, at(30)},
+		{102, 1.5, at(59)},
+	}
+	for _, tr := range trades {
+		if candle, done := agg.AddTrade(tr.price, tr.volume, tr.ts); done || candle != nil {
+			t.Fatalf("AddTrade at %s completed a candle early", tr.ts)
+		}
+	}
+
+	// A trade from a completed bucket is dropped
+	if _, done := agg.AddTrade(200, 10, at(-1)); done {
+		t.Fatal("late trade from a previous bucket completed a candle")
+	}
+
+	candle, done := agg.AddTrade(104, 3, at(61))
+	if !done || candle == nil {
+		t.Fatal("expected the first trade of the next minute to complete the candle")
+	}
+
+	want := engine.Candle{Timestamp: start, Open: 101, High: 105, Low: 98, Close: 102, Volume: 7}
+	if *candle != want {
+		t.Errorf("candle = %+v, want %+v", *candle, want)
+	}
+
+	// The crossing trade opened the next bucket, which skips the empty minute
+	candle, done = agg.AddTrade(106, 1, at(185))
+	if !done {
+		t.Fatal("expected the next bucket to complete")
+	}
+	want = engine.Candle{Timestamp: start.Add(time.Minute), Open: 104, High: 104, Low: 104, Close: 104, Volume: 3}
+	if *candle != want {
+		t.Errorf("candle = %+v, want %+v", *candle, want)
+	}
+}
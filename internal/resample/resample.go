@@ -0,0 +1,111 @@
+package resample
+
+import (
+	"fmt"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+)
+
+// Resample aggregates candles of timeframe from into timeframe to. Each
+// output candle takes the open of its first bar, the close of its last,
+// the extreme high and low, and the summed volume. Buckets are aligned to
+// multiples of the target duration since the Unix epoch (UTC), and buckets
+// missing any source bar, including a partial trailing bucket, are dropped.
+func Resample(candles []engine.Candle, from, to exchange.Timeframe) ([]engine.Candle, error) {
+	if !from.IsValid() || !to.IsValid() {
+		return nil, fmt.Errorf("unsupported timeframe: %s -> %s", from, to)
+	}
+
+	fromDur, toDur := from.ToDuration(), to.ToDuration()
+	if toDur <= fromDur || toDur%fromDur != 0 {
+		return nil, fmt.Errorf("%s is not a higher multiple of %s", to, from)
+	}
+	perBucket := int(toDur / fromDur)
+
+	for i := 1; i < len(candles); i++ {
+		if !candles[i].Timestamp.After(candles[i-1].Timestamp) {
+			return nil, fmt.Errorf("candles are not sorted at index %d", i)
+		}
+	}
+
+	result := make([]engine.Candle, 0, len(candles)/perBucket)
+
+	var bucket engine.Candle
+	var bucketStart time.Time
+	count := 0
+
+	flush := func() {
+		if count == perBucket {
+			result = append(result, bucket)
+		}
+	}
+
+	for _, c := range candles {
+		start := c.Timestamp.UTC().Truncate(toDur)
+		if count == 0 || !start.Equal(bucketStart) {
+			flush()
+			bucketStart = start
+			bucket = engine.Candle{
+				Timestamp: start,
+				Open:      c.Open,
+				High:      c.High,
+				Low:       c.Low,
+				Close:     c.Close,
+				Volume:    c.Volume,
+			}
+			count = 1
+			continue
+		}
+
+		if c.High > bucket.High {
+			bucket.High = c.High
+		}
+		if c.Low < bucket.Low {
+			bucket.Low = c.Low
+		}
+		bucket.Close = c.Close
+		bucket.Volume += c.Volume
+		count++
+	}
+	flush()
+
+	return result, nil
+}
+
+// ForwardFill inserts a flat, zero-volume candle at the previous close for
+// every missing bar of the given timeframe. Candles must be sorted by time.
+func ForwardFill(candles []engine.Candle, timeframe exchange.Timeframe) ([]engine.Candle, error) {
+	if !timeframe.IsValid() {
+		return nil, fmt.Errorf("unsupported timeframe: %s", timeframe)
+	}
+	interval := timeframe.ToDuration()
+
+	if len(candles) == 0 {
+		return candles, nil
+	}
+
+	result := make([]engine.Candle, 0, len(candles))
+	result = append(result, candles[0])
+
+	for i := 1; i < len(candles); i++ {
+		prev := result[len(result)-1]
+		if !candles[i].Timestamp.After(prev.Timestamp) {
+			return nil, fmt.Errorf("candles are not sorted at index %d", i)
+		}
+
+		for ts := prev.Timestamp.Add(interval); ts.Before(candles[i].Timestamp); ts = ts.Add(interval) {
+			result = append(result, engine.Candle{
+				Timestamp: ts,
+				Open:      prev.Close,
+				High:      prev.Close,
+				Low:       prev.Close,
+				Close:     prev.Close,
+			})
+		}
+		result = append(result, candles[i])
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package resample
+
+import (
+	"testing"
+	"time"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+)
+
+// hourly builds consecutive 1h candles starting at start
+func hourly(start time.Time, bars ...[5]float64) []engine.Candle {
+	candles := make([]engine.Candle, len(bars))
+	for i, b := range bars {
+		candles[i] = engine.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      b[0],
+			High:      b[1],
+			Low:       b[2],
+			Close:     b[3],
+			Volume:    b[4],
+		}
+	}
+	return candles
+}
+
+func TestResampleFourHourly(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := hourly(start,
+		[5]float64{100, 105, 99, 104, 10},
+		[5]float64{104, 110, 103, 108, 20},
+		[5]float64{108, 109, 95, 97, 30},
+		[5]float64{97, 102, 96, 101, 40},
+		// Partial trailing bucket is dropped
+		[5]float64{101, 103, 100, 102, 50},
+	)
+
+	got, err := Resample(candles, exchange.Timeframe1h, exchange.Timeframe4h)
+	if err != nil {
+		t.Fatalf("Resample() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Resample() returned %d candles, want 1", len(got))
+	}
+
+	want := engine.Candle{Timestamp: start, Open: 100, High: 110, Low: 95, Close: 101, Volume: 100}
+	if got[0] != want {
+		t.Errorf("Resample() = %+v, want %+v", got[0], want)
+	}
+}
+
+func TestResampleDropsIncompleteBuckets(t *testing.T) {
+	// Starting mid-bucket leaves the first bucket with only two bars
+	start := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	bars := make([][5]float64, 6)
+	for i := range bars {
+		bars[i] = [5]float64{100, 101, 99, 100, 1}
+	}
+
+	got, err := Resample(hourly(start, bars...), exchange.Timeframe1h, exchange.Timeframe4h)
+	if err != nil {
+		t.Fatalf("Resample() error: %v", err)
+	}
+	if len(got) != 1 || !got[0].Timestamp.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Resample() = %+v, want one bucket at 04:00", got)
+	}
+}
+
+func TestResampleValidation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := hourly(start, [5]float64{1, 1, 1, 1, 1}, [5]float64{1, 1, 1, 1, 1})
+
+	if _, err := Resample(candles, exchange.Timeframe4h, exchange.Timeframe1h); err == nil {
+		t.Error("expected error resampling to a lower timeframe")
+	}
+	if _, err := Resample(candles, exchange.Timeframe15m, exchange.Timeframe1h); err != nil {
+		t.Errorf("unexpected error for 15m -> 1h: %v", err)
+	}
+
+	candles[0], candles[1] = candles[1], candles[0]
+	if _, err := Resample(candles, exchange.Timeframe1h, exchange.Timeframe4h); err == nil {
+		t.Error("expected error for unsorted candles")
+	}
+}
+
+func TestForwardFill(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := []engine.Candle{
+		{Timestamp: start, Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 10},
+		{Timestamp: start.Add(3 * time.Hour), Open: 101, High: 102, Low: 100, Close: 101, Volume: 10},
+	}
+
+	got, err := ForwardFill(candles, exchange.Timeframe1h)
+	if err != nil {
+		t.Fatalf("ForwardFill() error: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("ForwardFill() returned %d candles, want 4", len(got))
+	}
+	for _, c := range got[1:3] {
+		if c.Open != 100.5 || c.Close != 100.5 || c.Volume != 0 {
+			t.Errorf("filled candle = %+v, want flat at 100.5 with zero volume", c)
+		}
+	}
+}
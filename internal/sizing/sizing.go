@@ -0,0 +1,26 @@
+package sizing
+
+import "math"
+
+// RiskSizedQuantity returns the quantity for which a move from entryPrice to
+// stopPrice loses riskPct percent of equity (e.g. 1 for 1%). The result is
+// capped so the position notional never exceeds equity. It returns 0 when
+// any input is non-positive or the stop distance is zero, so callers can
+// fall back to fixed sizing.
+func RiskSizedQuantity(equity, entryPrice, stopPrice, riskPct float64) float64 {
+	if equity <= 0 || entryPrice <= 0 || stopPrice <= 0 || riskPct <= 0 {
+		return 0
+	}
+
+	distance := math.Abs(entryPrice - stopPrice)
+	if distance == 0 {
+		return 0
+	}
+
+	quantity := equity * riskPct / 100 / distance
+	if maxQty := equity / entryPrice; quantity > maxQty {
+		quantity = maxQty
+	}
+
+	return quantity
+}
@@ -0,0 +1,36 @@
+package sizing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRiskSizedQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		equity   float64
+		entry    float64
+		stop     float64
+		riskPct  float64
+		wantQty  float64
+		wantRisk float64
+	}{
+		{"long 1% risk", 10000, 100, 95, 1, 20, 100},
+		{"short stop above entry", 10000, 100, 104, 2, 50, 200},
+		{"capped at equity notional", 10000, 100, 99.9, 5, 100, 10},
+		{"zero stop distance", 10000, 100, 100, 1, 0, 0},
+		{"no stop", 10000, 100, 0, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qty := RiskSizedQuantity(tt.equity, tt.entry, tt.stop, tt.riskPct)
+			if math.Abs(qty-tt.wantQty) > 1e-9 {
+				t.Errorf("quantity = %.6f, want %.6f", qty, tt.wantQty)
+			}
+			if risk := math.Abs(tt.entry-tt.stop) * qty; math.Abs(risk-tt.wantRisk) > 1e-6 {
+				t.Errorf("risk amount = %.6f, want %.6f", risk, tt.wantRisk)
+			}
+		})
+	}
+}
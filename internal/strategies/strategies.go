@@ -5,17 +5,22 @@ import (
 	"candlecore/internal/exchange"
 	"candlecore/internal/indicators"
 	"fmt"
+	"math"
+	"time"
 )
 
 // SimpleMAStrategy is a moving average crossover strategy
 type SimpleMAStrategy struct {
-	fastPeriod int
-	slowPeriod int
+	symbol      string
+	fastPeriod  int
+	slowPeriod  int
+	stopLossPct float64 // stop distance below entry for buy signals, 0 disables
 }
 
-// NewSimpleMAStrategy creates a new MA crossover strategy
-func NewSimpleMAStrategy(fastPeriod, slowPeriod int) *SimpleMAStrategy {
+// NewSimpleMAStrategy creates a new MA crossover strategy for symbol
+func NewSimpleMAStrategy(symbol string, fastPeriod, slowPeriod int) *SimpleMAStrategy {
 	return &SimpleMAStrategy{
+		symbol:     symbol,
 		fastPeriod: fastPeriod,
 		slowPeriod: slowPeriod,
 	}
@@ -28,7 +33,8 @@ func (s *SimpleMAStrategy) Name() string {
 
 // Analyze analyzes candles using MA crossover
 func (s *SimpleMAStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
-	if len(candles) < s.slowPeriod {
+	// Crossover detection compares the last two slow MA values
+	if len(candles) < s.slowPeriod+1 {
 		return &bot.Decision{
 			Signal: bot.SignalHold,
 			Reasoning: "Insufficient data for analysis",
@@ -61,7 +67,7 @@ func (s *SimpleMAStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, er
 	lastCandle := candles[len(candles)-1]
 	decision := &bot.Decision{
 		Timestamp: lastCandle.Timestamp,
-		Symbol:    "BTCUSDT", // TODO: get from context
+		Symbol:    s.symbol,
 		Price:     lastCandle.Close,
 		Indicators: map[string]float64{
 			"fast_ma": lastFast,
@@ -75,6 +81,10 @@ func (s *SimpleMAStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, er
 		decision.Signal = bot.SignalBuy
 		decision.Confidence = 75
 		decision.Reasoning = fmt.Sprintf("MA crossover: Fast MA (%.2f) crossed above Slow MA (%.2f)", lastFast, lastSlow)
+		if s.stopLossPct > 0 {
+			// The bot sizes stopped entries by risk
+			decision.StopLoss = lastCandle.Close * (1 - s.stopLossPct/100)
+		}
 	} else if prevFast >= prevSlow && lastFast < lastSlow {
 		// Bearish crossover
 		decision.Signal = bot.SignalSell
@@ -89,6 +99,11 @@ func (s *SimpleMAStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, er
 	return decision, nil
 }
 
+// WarmupPeriod returns the candles needed to compare two slow MA values
+func (s *SimpleMAStrategy) WarmupPeriod() int {
+	return s.slowPeriod + 1
+}
+
 // Configure updates strategy parameters
 func (s *SimpleMAStrategy) Configure(params map[string]interface{}) error {
 	if fast, ok := params["fast_period"].(int); ok {
@@ -97,19 +112,27 @@ func (s *SimpleMAStrategy) Configure(params map[string]interface{}) error {
 	if slow, ok := params["slow_period"].(int); ok {
 		s.slowPeriod = slow
 	}
+	if stop, ok := params["stop_loss_pct"].(float64); ok {
+		if stop < 0 || stop >= 100 {
+			return fmt.Errorf("stop_loss_pct must be between 0 and 100, got %.2f", stop)
+		}
+		s.stopLossPct = stop
+	}
 	return nil
 }
 
 // RSIStrategy is an RSI-based strategy
 type RSIStrategy struct {
+	symbol    string
 	period    int
 	oversold  float64
 	overbought float64
 }
 
-// NewRSIStrategy creates a new RSI strategy
-func NewRSIStrategy(period int, oversold, overbought float64) *RSIStrategy {
+// NewRSIStrategy creates a new RSI strategy for symbol
+func NewRSIStrategy(symbol string, period int, oversold, overbought float64) *RSIStrategy {
 	return &RSIStrategy{
+		symbol:     symbol,
 		period:     period,
 		oversold:   oversold,
 		overbought: overbought,
@@ -147,7 +170,7 @@ func (s *RSIStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error)
 
 	decision := &bot.Decision{
 		Timestamp: lastCandle.Timestamp,
-		Symbol:    "BTCUSDT",
+		Symbol:    s.symbol,
 		Price:     lastCandle.Close,
 		Indicators: map[string]float64{
 			"rsi": lastRSI,
@@ -171,6 +194,11 @@ func (s *RSIStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error)
 	return decision, nil
 }
 
+// WarmupPeriod returns the candles needed for a first RSI value
+func (s *RSIStrategy) WarmupPeriod() int {
+	return s.period + 1
+}
+
 // Configure updates strategy parameters
 func (s *RSIStrategy) Configure(params map[string]interface{}) error {
 	if period, ok := params["period"].(int); ok {
@@ -184,3 +212,523 @@ func (s *RSIStrategy) Configure(params map[string]interface{}) error {
 	}
 	return nil
 }
+
+// RSIDivergenceStrategy trades divergences between price and RSI pivots:
+// a lower price low with a higher RSI low is bullish, and a higher price
+// high with a lower RSI high is bearish
+type RSIDivergenceStrategy struct {
+	symbol     string
+	period     int
+	lookback   int
+	pivotWidth int
+}
+
+// NewRSIDivergenceStrategy creates an RSI divergence strategy for symbol that compares
+// the last two pivots within a 30-candle lookback. A pivot needs two lower
+// highs or higher lows on each side, so signals arrive two candles after it.
+func NewRSIDivergenceStrategy(symbol string, period int) *RSIDivergenceStrategy {
+	return &RSIDivergenceStrategy{
+		symbol:     symbol,
+		period:     period,
+		lookback:   30,
+		pivotWidth: 2,
+	}
+}
+
+// Name returns the strategy name
+func (s *RSIDivergenceStrategy) Name() string {
+	return fmt.Sprintf("RSI Divergence (%d)", s.period)
+}
+
+// Analyze looks for a divergence whose latest pivot was just confirmed
+func (s *RSIDivergenceStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	if len(candles) < s.period+2*s.pivotWidth+2 {
+		return &bot.Decision{
+			Signal:    bot.SignalHold,
+			Reasoning: "Insufficient data",
+		}, nil
+	}
+
+	closes := make([]float64, len(candles))
+	for i, c := range candles {
+		closes[i] = c.Close
+	}
+
+	rsi, err := indicators.RSI(closes, s.period)
+	if err != nil {
+		return nil, err
+	}
+
+	// Align prices with RSI values (rsi[i] belongs to closes[i+period])
+	prices := closes[s.period:]
+	if len(prices) > s.lookback {
+		offset := len(prices) - s.lookback
+		prices = prices[offset:]
+		rsi = rsi[offset:]
+	}
+
+	lastCandle := candles[len(candles)-1]
+	decision := &bot.Decision{
+		Timestamp:  lastCandle.Timestamp,
+		Symbol:     s.symbol,
+		Price:      lastCandle.Close,
+		Signal:     bot.SignalHold,
+		Confidence: 50,
+		Indicators: map[string]float64{
+			"rsi": rsi[len(rsi)-1],
+		},
+	}
+
+	// Only the pivot confirmed on this candle can produce a new signal
+	fresh := len(prices) - 1 - s.pivotWidth
+
+	lows := s.pivots(prices, false)
+	if n := len(lows); n >= 2 && lows[n-1] == fresh {
+		prev, last := lows[n-2], lows[n-1]
+		if prices[last] < prices[prev] && rsi[last] > rsi[prev] {
+			s.describe(decision, prices, rsi, prev, last)
+			decision.Signal = bot.SignalBuy
+			decision.Reasoning = fmt.Sprintf("Bullish RSI divergence: price low %.2f -> %.2f, RSI low %.2f -> %.2f",
+				prices[prev], prices[last], rsi[prev], rsi[last])
+			return decision, nil
+		}
+	}
+
+	highs := s.pivots(prices, true)
+	if n := len(highs); n >= 2 && highs[n-1] == fresh {
+		prev, last := highs[n-2], highs[n-1]
+		if prices[last] > prices[prev] && rsi[last] < rsi[prev] {
+			s.describe(decision, prices, rsi, prev, last)
+			decision.Signal = bot.SignalSell
+			decision.Reasoning = fmt.Sprintf("Bearish RSI divergence: price high %.2f -> %.2f, RSI high %.2f -> %.2f",
+				prices[prev], prices[last], rsi[prev], rsi[last])
+			return decision, nil
+		}
+	}
+
+	decision.Reasoning = fmt.Sprintf("No divergence. RSI: %.2f", rsi[len(rsi)-1])
+	return decision, nil
+}
+
+// pivots returns indexes whose price is the strict extreme of the window
+// pivotWidth candles either side
+func (s *RSIDivergenceStrategy) pivots(prices []float64, highs bool) []int {
+	var result []int
+	for i := s.pivotWidth; i < len(prices)-s.pivotWidth; i++ {
+		pivot := true
+		for j := i - s.pivotWidth; j <= i+s.pivotWidth && pivot; j++ {
+			if j == i {
+				continue
+			}
+			if highs && prices[j] >= prices[i] || !highs && prices[j] <= prices[i] {
+				pivot = false
+			}
+		}
+		if pivot {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// describe records the pivot values and scales confidence with the size of
+// the divergence in RSI points and price percent
+func (s *RSIDivergenceStrategy) describe(decision *bot.Decision, prices, rsi []float64, prev, last int) {
+	decision.Indicators["pivot1_price"] = prices[prev]
+	decision.Indicators["pivot1_rsi"] = rsi[prev]
+	decision.Indicators["pivot2_price"] = prices[last]
+	decision.Indicators["pivot2_rsi"] = rsi[last]
+
+	rsiDelta := math.Abs(rsi[last] - rsi[prev])
+	priceDeltaPct := math.Abs(prices[last]-prices[prev]) / prices[prev] * 100
+	decision.Confidence = 50 + math.Min(45, (rsiDelta+priceDeltaPct)*3)
+}
+
+// WarmupPeriod returns the candles needed for RSI plus two confirmed pivots
+func (s *RSIDivergenceStrategy) WarmupPeriod() int {
+	return s.period + 2*s.pivotWidth + 2
+}
+
+// Configure updates strategy parameters
+func (s *RSIDivergenceStrategy) Configure(params map[string]interface{}) error {
+	if period, ok := params["period"].(int); ok {
+		s.period = period
+	}
+	if lookback, ok := params["lookback"].(int); ok {
+		s.lookback = lookback
+	}
+	if width, ok := params["pivot_width"].(int); ok {
+		s.pivotWidth = width
+	}
+	return nil
+}
+
+// VWAPReversionStrategy buys when price stretches a number of volume-weighted
+// standard deviations below VWAP and sells once it reverts above VWAP.
+// VWAP is anchored at the start of a rolling lookback window.
+type VWAPReversionStrategy struct {
+	symbol      string
+	lookback    int
+	entryStdDev float64
+}
+
+// NewVWAPReversionStrategy creates a VWAP reversion strategy for symbol over
+// lookback candles that enters entryStdDev deviations below VWAP
+func NewVWAPReversionStrategy(symbol string, lookback int, entryStdDev float64) *VWAPReversionStrategy {
+	return &VWAPReversionStrategy{
+		symbol:      symbol,
+		lookback:    lookback,
+		entryStdDev: entryStdDev,
+	}
+}
+
+// Name returns the strategy name
+func (s *VWAPReversionStrategy) Name() string {
+	return fmt.Sprintf("VWAP Reversion (%d, %.1f sd)", s.lookback, s.entryStdDev)
+}
+
+// Analyze compares the last close with the window VWAP and its deviation band
+func (s *VWAPReversionStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	if len(candles) < s.lookback {
+		return &bot.Decision{
+			Signal:    bot.SignalHold,
+			Reasoning: "Insufficient data",
+		}, nil
+	}
+
+	window := candles[len(candles)-s.lookback:]
+	highs := make([]float64, len(window))
+	lows := make([]float64, len(window))
+	closes := make([]float64, len(window))
+	volumes := make([]float64, len(window))
+	for i, c := range window {
+		highs[i], lows[i], closes[i], volumes[i] = c.High, c.Low, c.Close, c.Volume
+	}
+
+	vwapSeries, err := indicators.VWAP(highs, lows, closes, volumes)
+	if err != nil {
+		return nil, err
+	}
+	vwap := vwapSeries[len(vwapSeries)-1]
+
+	// Volume-weighted deviation of typical prices around VWAP
+	weighted, totalVolume := 0.0, 0.0
+	for i := range window {
+		typical := (highs[i] + lows[i] + closes[i]) / 3
+		weighted += volumes[i] * (typical - vwap) * (typical - vwap)
+		totalVolume += volumes[i]
+	}
+
+	lastCandle := candles[len(candles)-1]
+	decision := &bot.Decision{
+		Timestamp:  lastCandle.Timestamp,
+		Symbol:     s.symbol,
+		Price:      lastCandle.Close,
+		Signal:     bot.SignalHold,
+		Confidence: 50,
+		Indicators: map[string]float64{
+			"vwap": vwap,
+		},
+	}
+
+	if totalVolume == 0 {
+		decision.Reasoning = "No volume in window"
+		return decision, nil
+	}
+
+	stdDev := math.Sqrt(weighted / totalVolume)
+	lowerBand := vwap - s.entryStdDev*stdDev
+	decision.Indicators["vwap_std_dev"] = stdDev
+	decision.Indicators["lower_band"] = lowerBand
+
+	switch {
+	case stdDev > 0 && lastCandle.Close < lowerBand:
+		deviations := (vwap - lastCandle.Close) / stdDev
+		decision.Signal = bot.SignalBuy
+		decision.Confidence = 60 + math.Min(35, (deviations-s.entryStdDev)*20)
+		decision.Reasoning = fmt.Sprintf("Price %.2f is %.2f std devs below VWAP %.2f", lastCandle.Close, deviations, vwap)
+	case lastCandle.Close > vwap:
+		decision.Signal = bot.SignalSell
+		decision.Confidence = 60
+		decision.Reasoning = fmt.Sprintf("Price %.2f reverted above VWAP %.2f", lastCandle.Close, vwap)
+	default:
+		decision.Reasoning = fmt.Sprintf("Price %.2f within band. VWAP: %.2f, lower band: %.2f", lastCandle.Close, vwap, lowerBand)
+	}
+
+	return decision, nil
+}
+
+// WarmupPeriod returns the candles needed to fill the lookback window
+func (s *VWAPReversionStrategy) WarmupPeriod() int {
+	return s.lookback
+}
+
+// Configure updates strategy parameters
+func (s *VWAPReversionStrategy) Configure(params map[string]interface{}) error {
+	if lookback, ok := params["lookback"].(int); ok {
+		if lookback < 2 {
+			return fmt.Errorf("lookback must be at least 2, got %d", lookback)
+		}
+		s.lookback = lookback
+	}
+	if stdDev, ok := params["entry_std_dev"].(float64); ok {
+		if stdDev <= 0 {
+			return fmt.Errorf("entry_std_dev must be positive, got %.2f", stdDev)
+		}
+		s.entryStdDev = stdDev
+	}
+	return nil
+}
+
+// DonchianBreakoutStrategy is a turtle-style breakout system. It buys when
+// the close breaks above the highest high of the previous entryPeriod
+// candles and sells when it breaks below the lowest low of the previous
+// exitPeriod candles.
+type DonchianBreakoutStrategy struct {
+	symbol      string
+	entryPeriod int
+	exitPeriod  int
+}
+
+// NewDonchianBreakoutStrategy creates a Donchian breakout strategy for
+// symbol with entryPeriod and exitPeriod channel lengths
+func NewDonchianBreakoutStrategy(symbol string, entryPeriod, exitPeriod int) *DonchianBreakoutStrategy {
+	return &DonchianBreakoutStrategy{
+		symbol:      symbol,
+		entryPeriod: entryPeriod,
+		exitPeriod:  exitPeriod,
+	}
+}
+
+// Name returns the strategy name
+func (s *DonchianBreakoutStrategy) Name() string {
+	return fmt.Sprintf("Donchian Breakout (%d/%d)", s.entryPeriod, s.exitPeriod)
+}
+
+// Analyze compares the last close with the channels of the candles before it
+func (s *DonchianBreakoutStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	longest := s.entryPeriod
+	if s.exitPeriod > longest {
+		longest = s.exitPeriod
+	}
+	if len(candles) < longest+1 {
+		return &bot.Decision{
+			Signal:    bot.SignalHold,
+			Reasoning: "Insufficient data",
+		}, nil
+	}
+
+	// Channels end on the previous candle so a breakout is measured
+	// against levels the last candle did not set itself
+	previous := candles[:len(candles)-1]
+	highs := make([]float64, len(previous))
+	lows := make([]float64, len(previous))
+	for i, c := range previous {
+		highs[i], lows[i] = c.High, c.Low
+	}
+
+	entryUpper, entryLower, err := indicators.DonchianChannels(highs, lows, s.entryPeriod)
+	if err != nil {
+		return nil, err
+	}
+	exitUpper, exitLower, err := indicators.DonchianChannels(highs, lows, s.exitPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	upper := entryUpper[len(entryUpper)-1]
+	lower := exitLower[len(exitLower)-1]
+	lastCandle := candles[len(candles)-1]
+
+	decision := &bot.Decision{
+		Timestamp: lastCandle.Timestamp,
+		Symbol:    s.symbol,
+		Price:     lastCandle.Close,
+		Indicators: map[string]float64{
+			"entry_upper": upper,
+			"entry_lower": entryLower[len(entryLower)-1],
+			"exit_upper":  exitUpper[len(exitUpper)-1],
+			"exit_lower":  lower,
+		},
+	}
+
+	switch {
+	case lastCandle.Close > upper:
+		decision.Signal = bot.SignalBuy
+		decision.Confidence = 75
+		decision.Reasoning = fmt.Sprintf("Close %.2f broke above the %d-period high %.2f", lastCandle.Close, s.entryPeriod, upper)
+	case lastCandle.Close < lower:
+		decision.Signal = bot.SignalSell
+		decision.Confidence = 75
+		decision.Reasoning = fmt.Sprintf("Close %.2f broke below the %d-period low %.2f", lastCandle.Close, s.exitPeriod, lower)
+	default:
+		decision.Signal = bot.SignalHold
+		decision.Confidence = 50
+		decision.Reasoning = fmt.Sprintf("Close %.2f inside channels. High: %.2f, low: %.2f", lastCandle.Close, upper, lower)
+	}
+
+	return decision, nil
+}
+
+// WarmupPeriod returns the candles needed for both channels plus the
+// candle tested against them
+func (s *DonchianBreakoutStrategy) WarmupPeriod() int {
+	if s.exitPeriod > s.entryPeriod {
+		return s.exitPeriod + 1
+	}
+	return s.entryPeriod + 1
+}
+
+// Configure updates strategy parameters
+func (s *DonchianBreakoutStrategy) Configure(params map[string]interface{}) error {
+	if period, ok := params["entry_period"].(int); ok {
+		if period < 1 {
+			return fmt.Errorf("entry_period must be positive, got %d", period)
+		}
+		s.entryPeriod = period
+	}
+	if period, ok := params["exit_period"].(int); ok {
+		if period < 1 {
+			return fmt.Errorf("exit_period must be positive, got %d", period)
+		}
+		s.exitPeriod = period
+	}
+	return nil
+}
+
+// defaultHTFTrendPeriod is the higher timeframe SMA length used as trend filter
+const defaultHTFTrendPeriod = 20
+
+// MultiTimeframeStrategy times entries with a base strategy while a higher
+// timeframe sets direction: base buy signals only pass when the last closed
+// higher timeframe candle is above its SMA. The bot trades long only, so
+// sells close positions and always pass through.
+type MultiTimeframeStrategy struct {
+	base        bot.Strategy
+	provider    exchange.DataProvider
+	htf         exchange.Timeframe
+	trendPeriod int
+
+	// Trend of the higher timeframe bar the last analysis fell in
+	cached *htfTrend
+}
+
+// htfTrend is the trend filter for one symbol and higher timeframe bar
+type htfTrend struct {
+	symbol string
+	bar    time.Time
+	close  float64
+	sma    float64
+	ready  bool
+}
+
+// NewMultiTimeframeStrategy wraps base with a trend filter computed from
+// htf candles served by provider for the symbol of each base decision
+func NewMultiTimeframeStrategy(htf exchange.Timeframe, provider exchange.DataProvider, base bot.Strategy) *MultiTimeframeStrategy {
+	return &MultiTimeframeStrategy{
+		base:        base,
+		provider:    provider,
+		htf:         htf,
+		trendPeriod: defaultHTFTrendPeriod,
+	}
+}
+
+// Name returns the strategy name
+func (s *MultiTimeframeStrategy) Name() string {
+	return fmt.Sprintf("%s [%s trend]", s.base.Name(), s.htf)
+}
+
+// Analyze runs the base strategy and vetoes buys unless the higher
+// timeframe trend is up
+func (s *MultiTimeframeStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	decision, err := s.base.Analyze(candles)
+	if err != nil || decision.Signal != bot.SignalBuy {
+		return decision, err
+	}
+
+	trend, err := s.trend(decision.Symbol, candles)
+	if err != nil {
+		return nil, err
+	}
+
+	if decision.Indicators == nil {
+		decision.Indicators = make(map[string]float64)
+	}
+	decision.Indicators["htf_close"] = trend.close
+	decision.Indicators["htf_sma"] = trend.sma
+
+	switch {
+	case !trend.ready:
+		decision.Signal = bot.SignalHold
+		decision.Reasoning = fmt.Sprintf("Buy vetoed: insufficient %s data for the trend filter (%s)", s.htf, decision.Reasoning)
+	case trend.close <= trend.sma:
+		decision.Signal = bot.SignalHold
+		decision.Reasoning = fmt.Sprintf("Buy vetoed: %s close %.2f is not above its %d-period SMA %.2f (%s)",
+			s.htf, trend.close, s.trendPeriod, trend.sma, decision.Reasoning)
+	}
+
+	return decision, nil
+}
+
+// trend returns the higher timeframe trend as of the close of the last
+// candle, reusing the previous result while it falls in the same higher
+// timeframe bar. Only higher timeframe candles closed by then are used, so
+// backtests do not see into the future.
+func (s *MultiTimeframeStrategy) trend(symbol string, candles []exchange.Candle) (*htfTrend, error) {
+	last := candles[len(candles)-1]
+	step := s.htf.ToDuration()
+	if len(candles) > 1 {
+		step = last.Timestamp.Sub(candles[len(candles)-2].Timestamp)
+	}
+	asOf := last.Timestamp.Add(step)
+	bar := asOf.Truncate(s.htf.ToDuration())
+
+	if s.cached != nil && s.cached.symbol == symbol && s.cached.bar.Equal(bar) {
+		return s.cached, nil
+	}
+
+	htfCandles, err := s.provider.GetCandles(symbol, s.htf, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s candles: %w", s.htf, err)
+	}
+
+	var closes []float64
+	for _, c := range htfCandles {
+		if c.Timestamp.Add(s.htf.ToDuration()).After(asOf) {
+			break
+		}
+		closes = append(closes, c.Close)
+	}
+
+	trend := &htfTrend{symbol: symbol, bar: bar}
+	if len(closes) >= s.trendPeriod {
+		sma, err := indicators.SMA(closes, s.trendPeriod)
+		if err != nil {
+			return nil, err
+		}
+		trend.close = closes[len(closes)-1]
+		trend.sma = sma[len(sma)-1]
+		trend.ready = true
+	}
+
+	s.cached = trend
+	return trend, nil
+}
+
+// WarmupPeriod returns the base strategy's warm-up period
+func (s *MultiTimeframeStrategy) WarmupPeriod() int {
+	return bot.WarmupPeriod(s.base)
+}
+
+// Configure sets htf_trend_period and passes all parameters to the base
+// strategy
+func (s *MultiTimeframeStrategy) Configure(params map[string]interface{}) error {
+	if period, ok := params["htf_trend_period"].(int); ok {
+		if period < 1 {
+			return fmt.Errorf("htf_trend_period must be positive, got %d", period)
+		}
+		s.trendPeriod = period
+		s.cached = nil
+	}
+	return s.base.Configure(params)
+}
@@ -0,0 +1,319 @@
+package strategies
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"candlecore/internal/bot"
+	"candlecore/internal/exchange"
+)
+
+// candlesFromCloses builds hourly candles from close prices
+func candlesFromCloses(closes ...float64) []exchange.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]exchange.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = exchange.Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      c,
+			High:      c,
+			Low:       c,
+			Close:     c,
+			Volume:    1000,
+		}
+	}
+	return candles
+}
+
+func TestRSIDivergenceBullish(t *testing.T) {
+	// A sharp drop to 90 bottoms RSI; after a bounce a slow drift makes a
+	// lower price low at 89.5 with a higher RSI low, confirmed two candles later
+	closes := []float64{
+		100, 101, 102, 103, 104, 105, 106,
+		100, 94, 90,
+		93, 96, 98,
+		97, 95, 93, 91, 89.5,
+		90.5, 91.5,
+	}
+	s := NewRSIDivergenceStrategy("BTCUSDT", 5)
+
+	decision, err := s.Analyze(candlesFromCloses(closes...))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+
+	if decision.Signal != bot.SignalBuy {
+		t.Fatalf("Signal = %s, want buy (%s)", decision.Signal, decision.Reasoning)
+	}
+	if decision.Indicators["pivot1_price"] != 90 || decision.Indicators["pivot2_price"] != 89.5 {
+		t.Errorf("pivot prices = %.2f, %.2f, want 90, 89.5",
+			decision.Indicators["pivot1_price"], decision.Indicators["pivot2_price"])
+	}
+	if decision.Indicators["pivot2_rsi"] <= decision.Indicators["pivot1_rsi"] {
+		t.Errorf("pivot RSI %.2f -> %.2f is not a higher low",
+			decision.Indicators["pivot1_rsi"], decision.Indicators["pivot2_rsi"])
+	}
+	if decision.Confidence <= 50 || decision.Confidence > 95 {
+		t.Errorf("Confidence = %.2f, want within (50, 95]", decision.Confidence)
+	}
+
+	// One candle later the pivot is no longer fresh
+	decision, err = s.Analyze(candlesFromCloses(append(closes, 92)...))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold {
+		t.Errorf("Signal on stale pivot = %s, want hold", decision.Signal)
+	}
+}
+
+func TestRSIDivergenceInsufficientData(t *testing.T) {
+	decision, err := NewRSIDivergenceStrategy("BTCUSDT", 14).Analyze(candlesFromCloses(1, 2, 3))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold {
+		t.Errorf("Signal = %s, want hold", decision.Signal)
+	}
+}
+
+func TestSimpleMAStopLoss(t *testing.T) {
+	// Fast MA crosses above the slow MA on the last candle
+	candles := candlesFromCloses(100, 99, 98, 97, 96, 95, 94, 93, 92, 110)
+	s := NewSimpleMAStrategy("BTCUSDT", 2, 5)
+
+	decision, err := s.Analyze(candles)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalBuy {
+		t.Fatalf("Signal = %s, want buy (%s)", decision.Signal, decision.Reasoning)
+	}
+	if decision.StopLoss != 0 {
+		t.Errorf("StopLoss = %.2f, want 0 when not configured", decision.StopLoss)
+	}
+
+	if err := s.Configure(map[string]interface{}{"stop_loss_pct": 4.0}); err != nil {
+		t.Fatalf("Configure() error: %v", err)
+	}
+	decision, err = s.Analyze(candles)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if math.Abs(decision.StopLoss-105.6) > 1e-9 {
+		t.Errorf("StopLoss = %.4f, want 105.6", decision.StopLoss)
+	}
+
+	if err := s.Configure(map[string]interface{}{"stop_loss_pct": 100.0}); err == nil {
+		t.Error("Configure() expected error for a 100% stop")
+	}
+}
+
+func TestVWAPReversionEntry(t *testing.T) {
+	// A stable range around 100 followed by a sharp flush to 94
+	closes := []float64{100, 101, 99, 100, 101, 99, 100, 101, 99, 100, 94}
+	candles := candlesFromCloses(closes...)
+	s := NewVWAPReversionStrategy("BTCUSDT", len(closes), 2)
+
+	decision, err := s.Analyze(candles)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalBuy {
+		t.Fatalf("Signal = %s, want buy (%s)", decision.Signal, decision.Reasoning)
+	}
+
+	// Equal volumes make VWAP the mean close
+	wantVWAP := 0.0
+	for _, c := range closes {
+		wantVWAP += c
+	}
+	wantVWAP /= float64(len(closes))
+	if math.Abs(decision.Indicators["vwap"]-wantVWAP) > 1e-9 {
+		t.Errorf("vwap = %.4f, want %.4f", decision.Indicators["vwap"], wantVWAP)
+	}
+	if decision.Indicators["lower_band"] <= 94 {
+		t.Errorf("lower_band = %.4f, want above the entry close", decision.Indicators["lower_band"])
+	}
+
+	// A bounce back above VWAP signals the exit
+	candles = candlesFromCloses(append(closes[1:], 101)...)
+	decision, err = s.Analyze(candles)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalSell {
+		t.Errorf("Signal = %s, want sell after reverting (%s)", decision.Signal, decision.Reasoning)
+	}
+}
+
+func TestVWAPReversionHoldsInsideBand(t *testing.T) {
+	candles := candlesFromCloses(100, 101, 99, 100, 101, 99, 100, 101, 99, 99.5)
+	s := NewVWAPReversionStrategy("BTCUSDT", 10, 2)
+
+	decision, err := s.Analyze(candles)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold {
+		t.Errorf("Signal = %s, want hold (%s)", decision.Signal, decision.Reasoning)
+	}
+}
+
+func TestDonchianBreakout(t *testing.T) {
+	s := NewDonchianBreakoutStrategy("BTCUSDT", 5, 3)
+
+	// Range between 98 and 102, then a fresh high at 103
+	closes := []float64{100, 102, 98, 101, 99, 100, 103}
+	decision, err := s.Analyze(candlesFromCloses(closes...))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalBuy {
+		t.Fatalf("Signal = %s, want buy (%s)", decision.Signal, decision.Reasoning)
+	}
+	if decision.Indicators["entry_upper"] != 102 {
+		t.Errorf("entry_upper = %.2f, want 102", decision.Indicators["entry_upper"])
+	}
+
+	// The trend continues without breaking the 3-period low
+	closes = append(closes, 105, 107, 106)
+	decision, err = s.Analyze(candlesFromCloses(closes...))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold {
+		t.Fatalf("Signal = %s, want hold during the trend (%s)", decision.Signal, decision.Reasoning)
+	}
+
+	// A close below the lows of 105, 107 and 106 exits
+	closes = append(closes, 104)
+	decision, err = s.Analyze(candlesFromCloses(closes...))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalSell {
+		t.Fatalf("Signal = %s, want sell (%s)", decision.Signal, decision.Reasoning)
+	}
+	if decision.Indicators["exit_lower"] != 105 {
+		t.Errorf("exit_lower = %.2f, want 105", decision.Indicators["exit_lower"])
+	}
+}
+
+func TestDonchianBreakoutWarmUp(t *testing.T) {
+	s := NewDonchianBreakoutStrategy("BTCUSDT", 5, 3)
+	decision, err := s.Analyze(candlesFromCloses(100, 101, 102, 103, 104))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold {
+		t.Errorf("Signal = %s, want hold before entryPeriod+1 candles", decision.Signal)
+	}
+
+	if err := s.Configure(map[string]interface{}{"exit_period": 0}); err == nil {
+		t.Error("Configure() expected error for a zero exit period")
+	}
+}
+
+// fixedStrategy always returns the same signal for symbol
+type fixedStrategy struct {
+	signal bot.Signal
+}
+
+func (s *fixedStrategy) Name() string { return "Fixed" }
+
+func (s *fixedStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	last := candles[len(candles)-1]
+	return &bot.Decision{Timestamp: last.Timestamp, Signal: s.signal, Symbol: "bitcoin", Price: last.Close, Reasoning: "fixed"}, nil
+}
+
+func (s *fixedStrategy) Configure(params map[string]interface{}) error { return nil }
+
+// countingProvider counts candle fetches
+type countingProvider struct {
+	exchange.DataProvider
+	fetches int
+}
+
+func (p *countingProvider) GetCandles(symbol string, timeframe exchange.Timeframe, limit int) ([]exchange.Candle, error) {
+	p.fetches++
+	return p.DataProvider.GetCandles(symbol, timeframe, limit)
+}
+
+// htfCandles builds 30 closed 4h candles ending at 2024-01-01 00:00 that
+// step by delta, followed by a still open candle that jumps the other way
+func htfCandles(delta float64) []exchange.Candle {
+	start := time.Date(2023, 12, 27, 0, 0, 0, 0, time.UTC)
+	candles := make([]exchange.Candle, 31)
+	price := 100.0
+	for i := range candles {
+		price += delta
+		if i == 30 {
+			price -= 50 * delta
+		}
+		candles[i] = exchange.Candle{
+			Timestamp: start.Add(time.Duration(i) * 4 * time.Hour),
+			Open:      price, High: price, Low: price, Close: price,
+		}
+	}
+	return candles
+}
+
+func TestMultiTimeframeVetoesCounterTrendBuy(t *testing.T) {
+	// Hourly candles at 00:00-02:00 see only the 4h candles closed by 03:00
+	ltf := candlesFromCloses(100, 101, 102)
+
+	tests := []struct {
+		name   string
+		delta  float64
+		signal bot.Signal
+		want   bot.Signal
+	}{
+		{"down trend vetoes buy", -1, bot.SignalBuy, bot.SignalHold},
+		{"up trend passes buy", 1, bot.SignalBuy, bot.SignalBuy},
+		{"down trend passes sell", -1, bot.SignalSell, bot.SignalSell},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &countingProvider{DataProvider: exchange.NewMemoryProvider(map[string][]exchange.Candle{
+				"bitcoin_4h": htfCandles(tt.delta),
+			})}
+			s := NewMultiTimeframeStrategy(exchange.Timeframe4h, provider, &fixedStrategy{signal: tt.signal})
+
+			decision, err := s.Analyze(ltf)
+			if err != nil {
+				t.Fatalf("Analyze() error: %v", err)
+			}
+			if decision.Signal != tt.want {
+				t.Errorf("Signal = %s, want %s (%s)", decision.Signal, tt.want, decision.Reasoning)
+			}
+		})
+	}
+}
+
+func TestMultiTimeframeCachesPerBar(t *testing.T) {
+	provider := &countingProvider{DataProvider: exchange.NewMemoryProvider(map[string][]exchange.Candle{
+		"bitcoin_4h": htfCandles(1),
+	})}
+	s := NewMultiTimeframeStrategy(exchange.Timeframe4h, provider, &fixedStrategy{signal: bot.SignalBuy})
+
+	ltf := candlesFromCloses(100, 101, 102, 103)
+	for _, n := range []int{2, 3} {
+		if _, err := s.Analyze(ltf[:n]); err != nil {
+			t.Fatalf("Analyze() error: %v", err)
+		}
+	}
+	if provider.fetches != 1 {
+		t.Errorf("fetches = %d, want 1 within one 4h bar", provider.fetches)
+	}
+
+	// The candle at 03:00 closes at 04:00, in the next 4h bar
+	if _, err := s.Analyze(ltf); err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if provider.fetches != 2 {
+		t.Errorf("fetches = %d, want 2 after the 4h bar rolls over", provider.fetches)
+	}
+}
@@ -0,0 +1,265 @@
+package strategy
+
+import (
+	"fmt"
+
+	"candlecore/internal/bot"
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+)
+
+const (
+	// botHistorySize matches the candle window the bot passes to Analyze
+	botHistorySize = 200
+
+	// botDefaultAllocation is the fraction of balance the bot invests when a
+	// decision carries no quantity
+	botDefaultAllocation = 0.1
+)
+
+// FromBotStrategy runs a bot strategy in the backtest engine.
+//
+// Each candle is appended to a per-symbol window of up to 200 candles that
+// is passed to Analyze, and the decision maps to an engine signal:
+//
+//   - buy becomes SignalActionBuy. Decision.Quantity is used when set,
+//     otherwise 10% of the account balance is invested, as the bot does.
+//   - sell becomes SignalActionSell for Decision.Quantity, or the whole open
+//     position when no quantity is set. Sells while flat become holds.
+//   - hold, unknown signals and Analyze errors become SignalActionHold.
+//
+// Signals use the candle's symbol, falling back to Decision.Symbol for
+// single-symbol runs. Reasoning carries over as Reason and StopLoss is
+// passed through.
+func FromBotStrategy(s bot.Strategy) engine.Strategy {
+	return &botAdapter{
+		strategy: s,
+		candles:  make(map[string][]exchange.Candle),
+	}
+}
+
+// botAdapter implements engine.Strategy on top of a bot.Strategy
+type botAdapter struct {
+	strategy bot.Strategy
+	candles  map[string][]exchange.Candle
+}
+
+// Name returns the wrapped strategy's name
+func (a *botAdapter) Name() string {
+	return a.strategy.Name()
+}
+
+// OnCandle buffers the candle and translates the strategy's decision
+func (a *botAdapter) OnCandle(candle engine.Candle, account *engine.Account) engine.Signal {
+	symbol := candle.Symbol
+	if symbol == "" {
+		symbol = defaultSymbol
+	}
+
+	candles := append(a.candles[symbol], exchange.Candle{
+		Timestamp: candle.Timestamp,
+		Open:      candle.Open,
+		High:      candle.High,
+		Low:       candle.Low,
+		Close:     candle.Close,
+		Volume:    candle.Volume,
+	})
+	if len(candles) > botHistorySize {
+		candles = candles[len(candles)-botHistorySize:]
+	}
+	a.candles[symbol] = candles
+
+	hold := engine.Signal{Action: engine.SignalActionHold, Symbol: symbol}
+
+	decision, err := a.strategy.Analyze(candles)
+	if err != nil {
+		hold.Reason = err.Error()
+		return hold
+	}
+	if decision == nil {
+		return hold
+	}
+	if candle.Symbol == "" && decision.Symbol != "" {
+		symbol = decision.Symbol
+		hold.Symbol = symbol
+	}
+
+	switch decision.Signal {
+	case bot.SignalBuy:
+		quantity := decision.Quantity
+		if quantity <= 0 && account != nil && candle.Close > 0 {
+			quantity = account.Balance * botDefaultAllocation / candle.Close
+		}
+		return engine.Signal{
+			Action:   engine.SignalActionBuy,
+			Symbol:   symbol,
+			Quantity: quantity,
+			Reason:   decision.Reasoning,
+			StopLoss: decision.StopLoss,
+		}
+	case bot.SignalSell:
+		position := openPosition(account, symbol)
+		if position == nil {
+			hold.Reason = decision.Reasoning
+			return hold
+		}
+		quantity := decision.Quantity
+		if quantity <= 0 || quantity > position.Quantity {
+			quantity = position.Quantity
+		}
+		return engine.Signal{
+			Action:   engine.SignalActionSell,
+			Symbol:   symbol,
+			Quantity: quantity,
+			Reason:   decision.Reasoning,
+		}
+	}
+
+	hold.Reason = decision.Reasoning
+	return hold
+}
+
+// OnTrade is a no-op; bot strategies only see candles
+func (a *botAdapter) OnTrade(trade *engine.Trade) {}
+
+// ToBotStrategy runs an engine strategy in the live bot.
+//
+// Analyze feeds OnCandle every candle newer than the last one it has seen,
+// so the engine strategy keeps its usual one-candle-at-a-time view, and
+// returns the last signal as a decision:
+//
+//   - SignalActionBuy becomes SignalBuy, with Quantity and StopLoss kept.
+//   - SignalActionSell becomes SignalSell, with Quantity kept.
+//   - SignalActionHold becomes SignalHold.
+//
+// Reason becomes Reasoning. The engine strategy sees a shadow account whose
+// position follows its own buy and sell signals, since the bot sizes and
+// tracks the real position itself. Configure is delegated when the engine
+// strategy has a Configure method and otherwise only accepts empty params.
+func ToBotStrategy(s engine.Strategy) bot.Strategy {
+	return &engineAdapter{
+		strategy: s,
+		account:  &engine.Account{},
+	}
+}
+
+// configurable is implemented by engine strategies that accept bot params
+type configurable interface {
+	Configure(params map[string]interface{}) error
+}
+
+// engineAdapter implements bot.Strategy on top of an engine.Strategy
+type engineAdapter struct {
+	strategy engine.Strategy
+	account  *engine.Account
+	lastSeen exchange.Candle
+	seen     bool
+}
+
+// Name returns the wrapped strategy's name
+func (a *engineAdapter) Name() string {
+	return a.strategy.Name()
+}
+
+// Analyze feeds unseen candles to the engine strategy and translates the
+// signal for the latest one
+func (a *engineAdapter) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candles to analyze")
+	}
+
+	start := 0
+	if a.seen {
+		start = len(candles)
+		for i, c := range candles {
+			if c.Timestamp.After(a.lastSeen.Timestamp) {
+				start = i
+				break
+			}
+		}
+	}
+
+	last := candles[len(candles)-1]
+	signal := engine.Signal{Action: engine.SignalActionHold, Reason: "no new candles"}
+	for _, c := range candles[start:] {
+		signal = a.strategy.OnCandle(engine.Candle{
+			Timestamp: c.Timestamp,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}, a.account)
+		a.track(signal)
+	}
+	a.lastSeen = last
+	a.seen = true
+
+	decision := &bot.Decision{
+		Timestamp:  last.Timestamp,
+		Signal:     bot.SignalHold,
+		Symbol:     signal.Symbol,
+		Price:      last.Close,
+		Reasoning:  signal.Reason,
+		Indicators: make(map[string]float64),
+	}
+
+	switch signal.Action {
+	case engine.SignalActionBuy:
+		decision.Signal = bot.SignalBuy
+		decision.Quantity = signal.Quantity
+		decision.StopLoss = signal.StopLoss
+	case engine.SignalActionSell:
+		decision.Signal = bot.SignalSell
+		decision.Quantity = signal.Quantity
+	}
+
+	return decision, nil
+}
+
+// Configure delegates to the engine strategy when it supports parameters
+func (a *engineAdapter) Configure(params map[string]interface{}) error {
+	if c, ok := a.strategy.(configurable); ok {
+		return c.Configure(params)
+	}
+	if len(params) > 0 {
+		return fmt.Errorf("strategy %s has no configurable parameters", a.strategy.Name())
+	}
+	return nil
+}
+
+// track applies a signal to the shadow account
+func (a *engineAdapter) track(signal engine.Signal) {
+	position := openPosition(a.account, signal.Symbol)
+
+	switch signal.Action {
+	case engine.SignalActionBuy:
+		if signal.Quantity <= 0 {
+			return
+		}
+		if position == nil {
+			a.account.Positions = append(a.account.Positions, &engine.Position{
+				Symbol:   signal.Symbol,
+				Quantity: signal.Quantity,
+			})
+			return
+		}
+		position.Quantity += signal.Quantity
+	case engine.SignalActionSell:
+		if position == nil {
+			return
+		}
+		if signal.Quantity > 0 && signal.Quantity < position.Quantity {
+			position.Quantity -= signal.Quantity
+			return
+		}
+
+		open := a.account.Positions[:0]
+		for _, p := range a.account.Positions {
+			if p != position {
+				open = append(open, p)
+			}
+		}
+		a.account.Positions = open
+	}
+}
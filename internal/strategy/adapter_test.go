@@ -0,0 +1,212 @@
+package strategy
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"candlecore/internal/bot"
+	"candlecore/internal/engine"
+	"candlecore/internal/exchange"
+)
+
+// scriptedBotStrategy returns one scripted decision per Analyze call
+type scriptedBotStrategy struct {
+	decisions []*bot.Decision
+	lengths   []int
+}
+
+func (s *scriptedBotStrategy) Name() string { return "scripted" }
+
+func (s *scriptedBotStrategy) Configure(params map[string]interface{}) error { return nil }
+
+func (s *scriptedBotStrategy) Analyze(candles []exchange.Candle) (*bot.Decision, error) {
+	s.lengths = append(s.lengths, len(candles))
+	d := s.decisions[0]
+	s.decisions = s.decisions[1:]
+	if d == nil {
+		return nil, errors.New("not enough data")
+	}
+	return d, nil
+}
+
+// scriptedEngineStrategy returns one scripted action per OnCandle call
+type scriptedEngineStrategy struct {
+	actions  []engine.SignalAction
+	seen     []float64
+	accounts []int
+}
+
+func (s *scriptedEngineStrategy) Name() string { return "scripted" }
+
+func (s *scriptedEngineStrategy) OnTrade(trade *engine.Trade) {}
+
+func (s *scriptedEngineStrategy) OnCandle(candle engine.Candle, account *engine.Account) engine.Signal {
+	s.seen = append(s.seen, candle.Close)
+	s.accounts = append(s.accounts, len(account.Positions))
+	action := s.actions[0]
+	s.actions = s.actions[1:]
+	signal := engine.Signal{Action: action, Symbol: defaultSymbol, Reason: string(action)}
+	switch action {
+	case engine.SignalActionBuy:
+		signal.Quantity = 2
+		signal.StopLoss = candle.Close * 0.95
+	case engine.SignalActionSell:
+		if p := openPosition(account, defaultSymbol); p != nil {
+			signal.Quantity = p.Quantity
+		}
+	}
+	return signal
+}
+
+// exchangeCandles builds hourly candles, the first one first hours into 2024
+func exchangeCandles(first int, closes ...float64) []exchange.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]exchange.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = exchange.Candle{Timestamp: start.Add(time.Duration(first+i) * time.Hour), Close: c}
+	}
+	return candles
+}
+
+func TestFromBotStrategyMapsDecisions(t *testing.T) {
+	inner := &scriptedBotStrategy{decisions: []*bot.Decision{
+		nil,
+		{Signal: bot.SignalSell, Reasoning: "sell while flat"},
+		{Signal: bot.SignalBuy, Reasoning: "cross up", StopLoss: 95},
+		{Signal: bot.SignalHold, Reasoning: "wait"},
+		{Signal: bot.SignalSell, Reasoning: "cross down"},
+	}}
+	s := FromBotStrategy(inner)
+	account := &engine.Account{Balance: 10000}
+
+	want := []engine.SignalAction{
+		engine.SignalActionHold,
+		engine.SignalActionHold,
+		engine.SignalActionBuy,
+		engine.SignalActionHold,
+		engine.SignalActionSell,
+	}
+	for i, action := range want {
+		signal := s.OnCandle(candleAt(i, 100), account)
+		if signal.Action != action {
+			t.Fatalf("candle %d: action = %s, want %s", i, signal.Action, action)
+		}
+
+		switch signal.Action {
+		case engine.SignalActionBuy:
+			if signal.Quantity != 10 {
+				t.Errorf("buy quantity = %.2f, want 10%% of balance (10)", signal.Quantity)
+			}
+			if signal.StopLoss != 95 || signal.Reason != "cross up" {
+				t.Errorf("buy signal = %+v, want stop and reason carried over", signal)
+			}
+			account.Positions = []*engine.Position{{Symbol: signal.Symbol, Quantity: signal.Quantity}}
+		case engine.SignalActionSell:
+			if signal.Quantity != 10 {
+				t.Errorf("sell quantity = %.2f, want the whole position", signal.Quantity)
+			}
+		}
+	}
+
+	if s.Name() != "scripted" {
+		t.Errorf("Name() = %q, want scripted", s.Name())
+	}
+	for i, n := range inner.lengths {
+		if n != i+1 {
+			t.Errorf("Analyze call %d saw %d candles, want %d", i, n, i+1)
+		}
+	}
+}
+
+func TestToBotStrategyMapsSignals(t *testing.T) {
+	inner := &scriptedEngineStrategy{actions: []engine.SignalAction{
+		engine.SignalActionHold,
+		engine.SignalActionHold,
+		engine.SignalActionBuy,
+		engine.SignalActionHold,
+		engine.SignalActionSell,
+	}}
+	s := ToBotStrategy(inner)
+
+	// The first call feeds the whole window, later calls only new candles
+	decision, err := s.Analyze(exchangeCandles(0, 100, 101, 102))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalBuy || decision.Quantity != 2 || math.Abs(decision.StopLoss-96.9) > 1e-9 {
+		t.Fatalf("decision = %+v, want buy 2 with stop", decision)
+	}
+	if decision.Price != 102 || decision.Reasoning != "buy" {
+		t.Errorf("decision price/reason = %.2f/%q, want 102/buy", decision.Price, decision.Reasoning)
+	}
+
+	decision, err = s.Analyze(exchangeCandles(0, 100, 101, 102, 103))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold {
+		t.Fatalf("decision = %s, want hold", decision.Signal)
+	}
+
+	decision, err = s.Analyze(exchangeCandles(1, 101, 102, 103, 104))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalSell || decision.Quantity != 2 {
+		t.Fatalf("decision = %+v, want sell of the shadow position", decision)
+	}
+
+	wantSeen := []float64{100, 101, 102, 103, 104}
+	if len(inner.seen) != len(wantSeen) {
+		t.Fatalf("OnCandle saw %v, want %v", inner.seen, wantSeen)
+	}
+	for i := range wantSeen {
+		if inner.seen[i] != wantSeen[i] {
+			t.Fatalf("OnCandle saw %v, want %v", inner.seen, wantSeen)
+		}
+	}
+	if inner.accounts[3] != 1 {
+		t.Errorf("shadow positions after buy = %d, want 1", inner.accounts[3])
+	}
+
+	// Repeating the same window feeds nothing and holds
+	decision, err = s.Analyze(exchangeCandles(1, 101, 102, 103, 104))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if decision.Signal != bot.SignalHold || len(inner.seen) != 5 {
+		t.Errorf("repeat window: decision %s after %d candles, want hold after 5", decision.Signal, len(inner.seen))
+	}
+}
+
+func TestToBotStrategyConfigure(t *testing.T) {
+	s := ToBotStrategy(NewMACDStrategy("", 12, 26, 9, 1000))
+	if err := s.Configure(nil); err != nil {
+		t.Errorf("Configure(nil) error: %v", err)
+	}
+	if err := s.Configure(map[string]interface{}{"fast": 5}); err == nil {
+		t.Error("Configure() expected error for a strategy without parameters")
+	}
+}
+
+func TestAdapterRoundTrip(t *testing.T) {
+	s := FromBotStrategy(ToBotStrategy(NewMACDStrategy("", 3, 6, 3, 1000)))
+	direct := NewMACDStrategy("", 3, 6, 3, 1000)
+
+	account := &engine.Account{Balance: 10000}
+	for i, c := range macdCloses {
+		got := s.OnCandle(candleAt(i, c), account)
+		want := direct.OnCandle(candleAt(i, c), account)
+		if got.Action != want.Action {
+			t.Fatalf("candle %d: adapted %s, direct %s", i, got.Action, want.Action)
+		}
+		switch got.Action {
+		case engine.SignalActionBuy:
+			account.Positions = []*engine.Position{{Symbol: got.Symbol, Quantity: got.Quantity}}
+		case engine.SignalActionSell:
+			account.Positions = nil
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package strategy
+
+import (
+	"candlecore/internal/config"
+	"candlecore/internal/engine"
+	"candlecore/internal/strategies"
+)
+
+// FromConfig builds the engine strategy selected by strategy.name for
+// symbol. ma and rsi run the bot strategies through FromBotStrategy; macd
+// is native and invests position_size per entry. The config is validated
+// first, so a bad block is reported instead of silently trading defaults.
+func FromConfig(cfg config.StrategyConfig, symbol string) (engine.Strategy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	kind, err := cfg.Kind()
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case config.StrategyRSI:
+		return FromBotStrategy(strategies.NewRSIStrategy(symbol, cfg.RSI.Period, cfg.RSI.Oversold, cfg.RSI.Overbought)), nil
+	case config.StrategyMACD:
+		return NewMACDStrategy(symbol, cfg.MACD.FastPeriod, cfg.MACD.SlowPeriod, cfg.MACD.SignalPeriod, cfg.PositionSize), nil
+	default:
+		return FromBotStrategy(strategies.NewSimpleMAStrategy(symbol, cfg.FastPeriod, cfg.SlowPeriod)), nil
+	}
+}
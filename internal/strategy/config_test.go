@@ -0,0 +1,37 @@
+package strategy
+
+import (
+	"testing"
+
+	"candlecore/internal/config"
+)
+
+func TestFromConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(s *config.StrategyConfig)
+		wantName string
+		wantErr  bool
+	}{
+		{"legacy ma", func(s *config.StrategyConfig) { s.Name = "simple_ma"; s.FastPeriod = 5; s.SlowPeriod = 20 }, "MA Crossover (5/20)", false},
+		{"rsi", func(s *config.StrategyConfig) { s.Name = "rsi"; s.RSI.Period = 7 }, "RSI (7)", false},
+		{"macd", func(s *config.StrategyConfig) { s.Name = "macd" }, "MACD (12/26/9)", false},
+		{"invalid rsi levels", func(s *config.StrategyConfig) { s.Name = "rsi"; s.RSI.Oversold = 80 }, "", true},
+		{"unknown", func(s *config.StrategyConfig) { s.Name = "bollinger" }, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default().Strategy
+			tt.mutate(&cfg)
+
+			s, err := FromConfig(cfg, "bitcoin")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", s.Name(), tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package strategy
+
+import (
+	"fmt"
+
+	"candlecore/internal/engine"
+	"candlecore/internal/indicators"
+)
+
+// defaultSymbol is traded when neither the candle nor the strategy names a
+// symbol
+const defaultSymbol = "BTC/USD"
+
+// MACDStrategy buys when the MACD line crosses above its signal line and
+// sells the whole position when it crosses back below. Prices are kept in a
+// rolling buffer per symbol, so it also works with Engine.RunMulti.
+type MACDStrategy struct {
+	symbol       string // traded when candles carry no symbol
+	fastPeriod   int
+	slowPeriod   int
+	signalPeriod int
+	positionSize float64 // quote currency invested per entry
+	bufferSize   int
+	prices       map[string][]float64
+}
+
+// NewMACDStrategy creates a MACD crossover strategy. symbol is traded on
+// candles without their own symbol; empty uses BTC/USD. positionSize is the
+// amount of quote currency to invest on each buy.
+func NewMACDStrategy(symbol string, fast, slow, signal int, positionSize float64) *MACDStrategy {
+	if symbol == "" {
+		symbol = defaultSymbol
+	}
+	return &MACDStrategy{
+		symbol:       symbol,
+		fastPeriod:   fast,
+		slowPeriod:   slow,
+		signalPeriod: signal,
+		positionSize: positionSize,
+		// Several slow periods of history keep the EMAs close to their
+		// full-history values
+		bufferSize: 4 * (slow + signal),
+		prices:     make(map[string][]float64),
+	}
+}
+
+// Name returns the strategy name
+func (s *MACDStrategy) Name() string {
+	return fmt.Sprintf("MACD (%d/%d/%d)", s.fastPeriod, s.slowPeriod, s.signalPeriod)
+}
+
+// OnCandle buffers the close and signals on a MACD/signal line crossover.
+// Until slow+signal closes are buffered it holds.
+func (s *MACDStrategy) OnCandle(candle engine.Candle, account *engine.Account) engine.Signal {
+	symbol := candle.Symbol
+	if symbol == "" {
+		symbol = s.symbol
+	}
+
+	prices := append(s.prices[symbol], candle.Close)
+	if len(prices) > s.bufferSize {
+		prices = prices[len(prices)-s.bufferSize:]
+	}
+	s.prices[symbol] = prices
+
+	hold := engine.Signal{Action: engine.SignalActionHold, Symbol: symbol}
+	if len(prices) < s.slowPeriod+s.signalPeriod {
+		hold.Reason = "warming up"
+		return hold
+	}
+
+	macd, err := indicators.MACD(prices, s.fastPeriod, s.slowPeriod, s.signalPeriod)
+	if err != nil {
+		hold.Reason = err.Error()
+		return hold
+	}
+
+	n := len(macd.Histogram)
+	prev, last := macd.Histogram[n-2], macd.Histogram[n-1]
+	position := openPosition(account, symbol)
+
+	switch {
+	case prev <= 0 && last > 0 && position == nil:
+		return engine.Signal{
+			Action:   engine.SignalActionBuy,
+			Symbol:   symbol,
+			Quantity: s.positionSize / candle.Close,
+			Reason: fmt.Sprintf("MACD %.4f crossed above signal %.4f",
+				macd.MACD[n-1], macd.Signal[n-1]),
+		}
+	case prev >= 0 && last < 0 && position != nil:
+		return engine.Signal{
+			Action:   engine.SignalActionSell,
+			Symbol:   symbol,
+			Quantity: position.Quantity,
+			Reason: fmt.Sprintf("MACD %.4f crossed below signal %.4f",
+				macd.MACD[n-1], macd.Signal[n-1]),
+		}
+	}
+
+	return hold
+}
+
+// OnTrade is a no-op; the strategy reads positions from the account
+func (s *MACDStrategy) OnTrade(trade *engine.Trade) {}
+
+// openPosition returns the account's open position in symbol, if any
+func openPosition(account *engine.Account, symbol string) *engine.Position {
+	if account == nil {
+		return nil
+	}
+	for _, p := range account.Positions {
+		if p.Symbol == symbol && p.Quantity > 0 {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"candlecore/internal/broker"
+	"candlecore/internal/engine"
+	"candlecore/internal/logger"
+	"candlecore/internal/strategies"
+)
+
+// decline then rally then decline, long enough for a 3/6/3 MACD
+var macdCloses = []float64{
+	110, 109, 108, 107, 106, 105, 104, 103, 102, 101,
+	102, 104, 107, 111, 116, 121, 125, 128, 130, 131,
+	130, 127, 123, 118, 112, 106, 100, 95, 91, 88,
+}
+
+func candleAt(i int, close float64) engine.Candle {
+	return engine.Candle{
+		Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour),
+		Open:      close,
+		High:      close,
+		Low:       close,
+		Close:     close,
+		Volume:    1,
+	}
+}
+
+func TestMACDStrategyCrossovers(t *testing.T) {
+	s := NewMACDStrategy("", 3, 6, 3, 1000)
+	account := &engine.Account{}
+
+	buyAt, sellAt := -1, -1
+	for i, c := range macdCloses {
+		signal := s.OnCandle(candleAt(i, c), account)
+
+		if i < 8 && signal.Action != engine.SignalActionHold {
+			t.Fatalf("candle %d: %s during warm-up", i, signal.Action)
+		}
+
+		switch signal.Action {
+		case engine.SignalActionBuy:
+			if buyAt >= 0 {
+				t.Fatalf("second buy at candle %d while long", i)
+			}
+			buyAt = i
+			if math.Abs(signal.Quantity-1000/c) > 1e-9 {
+				t.Errorf("buy quantity = %.6f, want %.6f", signal.Quantity, 1000/c)
+			}
+			if signal.Symbol != defaultSymbol {
+				t.Errorf("symbol = %q, want %q", signal.Symbol, defaultSymbol)
+			}
+			account.Positions = []*engine.Position{{Symbol: signal.Symbol, Quantity: signal.Quantity}}
+		case engine.SignalActionSell:
+			sellAt = i
+			if signal.Quantity != account.Positions[0].Quantity {
+				t.Errorf("sell quantity = %.6f, want the full position", signal.Quantity)
+			}
+			account.Positions = nil
+		}
+	}
+
+	// The rally starts at candle 10 and loses momentum before the top at 19
+	if buyAt < 10 || buyAt > 13 {
+		t.Errorf("buy at candle %d, want shortly after the rally starts", buyAt)
+	}
+	if sellAt < 17 || sellAt > 22 {
+		t.Errorf("sell at candle %d, want around the top", sellAt)
+	}
+}
+
+func TestMACDStrategyKeepsSymbolsApart(t *testing.T) {
+	s := NewMACDStrategy("", 3, 6, 3, 1000)
+
+	for i := 0; i < 8; i++ {
+		candle := candleAt(i, 100)
+		candle.Symbol = "ETH/USD"
+		s.OnCandle(candle, &engine.Account{})
+	}
+
+	candle := candleAt(8, 100)
+	candle.Symbol = "BTC/USD"
+	if signal := s.OnCandle(candle, &engine.Account{}); signal.Reason != "warming up" {
+		t.Errorf("BTC/USD reason = %q, want warming up on its first candle", signal.Reason)
+	}
+}
+
+// nopStore discards engine state
+type nopStore struct{}
+
+func (nopStore) SaveState(broker engine.Broker) error { return nil }
+func (nopStore) LoadState(broker engine.Broker) error { return nil }
+
+func TestConfiguredSymbolEndToEnd(t *testing.T) {
+	candles := make([]engine.Candle, len(macdCloses))
+	for i, c := range macdCloses {
+		candles[i] = candleAt(i, c)
+	}
+
+	tests := []struct {
+		name     string
+		strategy engine.Strategy
+	}{
+		{"macd", NewMACDStrategy("ETHUSDT", 3, 6, 3, 1000)},
+		{"bot strategy", FromBotStrategy(strategies.NewSimpleMAStrategy("ETHUSDT", 2, 5))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := broker.NewNoopBroker(10000)
+			e := engine.New(b, tt.strategy, nopStore{}, logger.New("error"))
+			e.SetSymbol("ETHUSDT")
+
+			if err := e.Run(context.Background(), candles); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+
+			// The engine only forwards a sell once the broker holds a
+			// position under the signal's symbol
+			sold := false
+			for i, o := range b.GetPlacedOrders() {
+				if o.Symbol != "ETHUSDT" {
+					t.Errorf("order %d symbol = %q, want ETHUSDT", i, o.Symbol)
+				}
+				if o.Side == engine.OrderSideSell {
+					sold = true
+				}
+			}
+			if !sold {
+				t.Fatal("expected a sell of the ETHUSDT position")
+			}
+		})
+	}
+}
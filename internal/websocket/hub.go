@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"candlecore/internal/bot"
+	"context"
 	"candlecore/internal/exchange"
 	"encoding/json"
 	"log"
@@ -48,30 +49,112 @@ type PnLData struct {
 	UnrealizedPnL float64 `json:"unrealized_pnl,omitempty"`
 }
 
+const (
+	// DefaultHistorySize is the number of recent events replayed to new clients
+	DefaultHistorySize = 500
+
+	// clientSendBuffer is the live event buffer per client, on top of history
+	clientSendBuffer = 256
+)
+
 // Hub manages WebSocket connections and broadcasts
 type Hub struct {
 	clients    map[*Client]bool
 	broadcast  chan Event
-	Register   chan *Client
+	register   chan *Client
 	unregister chan *Client
 	mu         sync.RWMutex
+	history    *eventRing
+	done       chan struct{}
+	stopOnce   sync.Once
 }
 
-// NewHub creates a new WebSocket hub
+// NewHub creates a new WebSocket hub that keeps DefaultHistorySize events
 func NewHub() *Hub {
+	return NewHubWithHistory(DefaultHistorySize)
+}
+
+// NewHubWithHistory creates a hub that replays up to size recent events to
+// newly registered clients. A size of zero disables history.
+func NewHubWithHistory(size int) *Hub {
+	if size < 0 {
+		size = 0
+	}
 	return &Hub{
 		clients:    make(map[*Client]bool),
 		broadcast:  make(chan Event, 256),
-		Register:   make(chan *Client),
+		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		history:    newEventRing(size),
+		done:       make(chan struct{}),
+	}
+}
+
+// Register adds a client so it receives history and live events. It
+// returns false without registering when the hub is stopped or ctx is done
+// first; the client's event channel is then never closed by the hub.
+func (h *Hub) Register(ctx context.Context, client *Client) bool {
+	select {
+	case h.register <- client:
+		return true
+	case <-h.done:
+		return false
+	case <-ctx.Done():
+		return false
 	}
 }
 
-// Run starts the hub
+// Unregister removes a client and closes its event channel
+func (h *Hub) Unregister(client *Client) {
+	select {
+	case h.unregister <- client:
+	case <-h.done:
+	}
+}
+
+// Stop ends Run and closes every client's event channel, which closes
+// WebSocket connections and ends event streams. Broadcasts after Stop are
+// dropped. Stop is safe to call more than once.
+func (h *Hub) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// publish hands an event to Run, dropping it once the hub is stopped
+func (h *Hub) publish(event Event) {
+	select {
+	case h.broadcast <- event:
+	case <-h.done:
+	}
+}
+
+// History returns the buffered events, oldest first
+func (h *Hub) History() []Event {
+	return h.history.events()
+}
+
+// Run starts the hub and returns once Stop is called
 func (h *Hub) Run() {
 	for {
 		select {
-		case client := <-h.Register:
+		case <-h.done:
+			h.mu.Lock()
+			for client := range h.clients {
+				close(client.send)
+				delete(h.clients, client)
+			}
+			h.mu.Unlock()
+			return
+
+		case client := <-h.register:
+			// Replay history before the client joins so live events follow it
+			for _, event := range h.history.events() {
+				select {
+				case client.send <- event:
+				default:
+				}
+			}
 			h.mu.Lock()
 			h.clients[client] = true
 			h.mu.Unlock()
@@ -87,8 +170,12 @@ func (h *Hub) Run() {
 			log.Printf("Client disconnected. Total clients: %d", len(h.clients))
 
 		case event := <-h.broadcast:
+			h.history.add(event)
 			h.mu.RLock()
 			for client := range h.clients {
+				if !client.subscribed(event.Type) {
+					continue
+				}
 				select {
 				case client.send <- event:
 				default:
@@ -101,9 +188,56 @@ func (h *Hub) Run() {
 	}
 }
 
+// eventRing is a bounded, thread-safe buffer of the most recent events
+type eventRing struct {
+	mu    sync.RWMutex
+	buf   []Event
+	start int
+	count int
+}
+
+// newEventRing creates a ring holding up to size events
+func newEventRing(size int) *eventRing {
+	return &eventRing{buf: make([]Event, size)}
+}
+
+// size returns the ring capacity
+func (r *eventRing) size() int {
+	return len(r.buf)
+}
+
+// add appends an event, overwriting the oldest once full
+func (r *eventRing) add(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.buf) == 0 {
+		return
+	}
+	if r.count < len(r.buf) {
+		r.buf[(r.start+r.count)%len(r.buf)] = event
+		r.count++
+		return
+	}
+	r.buf[r.start] = event
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// events returns a copy of the buffered events, oldest first
+func (r *eventRing) events() []Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Event, r.count)
+	for i := 0; i < r.count; i++ {
+		result[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return result
+}
+
 // BroadcastCandle broadcasts a candle update
 func (h *Hub) BroadcastCandle(candle exchange.Candle, symbol, timeframe string) {
-	h.broadcast <- Event{
+	h.publish(Event{
 		Type:      EventTypeCandle,
 		Timestamp: time.Now(),
 		Data: CandleData{
@@ -116,43 +250,43 @@ func (h *Hub) BroadcastCandle(candle exchange.Candle, symbol, timeframe string)
 			Close:     candle.Close,
 			Volume:    candle.Volume,
 		},
-	}
+	})
 }
 
 // BroadcastDecision broadcasts a bot decision
 func (h *Hub) BroadcastDecision(decision *bot.Decision) {
-	h.broadcast <- Event{
+	h.publish(Event{
 		Type:      EventTypeDecision,
 		Timestamp: time.Now(),
 		Data:      decision,
-	}
+	})
 }
 
 // BroadcastPosition broadcasts position update
 func (h *Hub) BroadcastPosition(position *bot.Position) {
-	h.broadcast <- Event{
+	h.publish(Event{
 		Type:      EventTypePosition,
 		Timestamp: time.Now(),
 		Data:      position,
-	}
+	})
 }
 
 // BroadcastPnL broadcasts PnL update
 func (h *Hub) BroadcastPnL(pnl PnLData) {
-	h.broadcast <- Event{
+	h.publish(Event{
 		Type:      EventTypePnL,
 		Timestamp: time.Now(),
 		Data:      pnl,
-	}
+	})
 }
 
 // BroadcastStatus broadcasts bot status
 func (h *Hub) BroadcastStatus(status string) {
-	h.broadcast <- Event{
+	h.publish(Event{
 		Type:      EventTypeStatus,
 		Timestamp: time.Now(),
 		Data:      map[string]string{"status": status},
-	}
+	})
 }
 
 // Client represents a WebSocket client
@@ -160,21 +294,79 @@ type Client struct {
 	hub  *Hub
 	conn *websocket.Conn
 	send chan Event
+
+	// types is the set of subscribed event types; nil means all types
+	types   map[EventType]bool
+	typesMu sync.RWMutex
 }
 
-// NewClient creates a new WebSocket client
+// clientMessage is a control message sent by a client, e.g.
+// {"action":"subscribe","types":["pnl","decision"]}
+type clientMessage struct {
+	Action string      `json:"action"`
+	Types  []EventType `json:"types"`
+}
+
+// NewClient creates a new WebSocket client. A nil conn creates a
+// pseudo-client whose events are read from Events instead of the pumps.
 func NewClient(hub *Hub, conn *websocket.Conn) *Client {
 	return &Client{
 		hub:  hub,
 		conn: conn,
-		send: make(chan Event, 256),
+		send: make(chan Event, clientSendBuffer+hub.history.size()),
+	}
+}
+
+// Events returns the channel of events delivered to the client. It is
+// closed when the client is unregistered or dropped as too slow.
+func (c *Client) Events() <-chan Event {
+	return c.send
+}
+
+// Subscribe limits the events delivered to the client to the given types.
+// An empty list restores delivery of all types.
+func (c *Client) Subscribe(types []EventType) {
+	c.typesMu.Lock()
+	defer c.typesMu.Unlock()
+
+	if len(types) == 0 {
+		c.types = nil
+		return
+	}
+
+	c.types = make(map[EventType]bool, len(types))
+	for _, t := range types {
+		c.types[t] = true
+	}
+}
+
+// subscribed reports whether the client wants events of the given type
+func (c *Client) subscribed(eventType EventType) bool {
+	c.typesMu.RLock()
+	defer c.typesMu.RUnlock()
+	return c.types == nil || c.types[eventType]
+}
+
+// handleMessage applies a control message from the client
+func (c *Client) handleMessage(data []byte) {
+	var msg clientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Ignoring malformed client message: %v", err)
+		return
+	}
+
+	switch msg.Action {
+	case "subscribe":
+		c.Subscribe(msg.Types)
+	default:
+		log.Printf("Ignoring unknown client action: %s", msg.Action)
 	}
 }
 
-// ReadPump handles incoming messages (mostly pings)
+// ReadPump handles incoming subscription messages and pings
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.Unregister(c)
 		c.conn.Close()
 	}()
 
@@ -185,10 +377,11 @@ func (c *Client) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			break
 		}
+		c.handleMessage(data)
 	}
 }
 
@@ -0,0 +1,216 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"candlecore/internal/bot"
+	"candlecore/internal/exchange"
+
+	"github.com/gorilla/websocket"
+)
+
+// testServer serves the hub over a real WebSocket endpoint and reports each
+// server-side client as it registers
+func testServer(t *testing.T, hub *Hub) (string, <-chan *Client) {
+	t.Helper()
+
+	clients := make(chan *Client, 8)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := NewClient(hub, conn)
+		if !hub.Register(r.Context(), client) {
+			conn.Close()
+			return
+		}
+		go client.WritePump()
+		go client.ReadPump()
+		clients <- client
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), clients
+}
+
+// dial connects a client and optionally subscribes it to event types
+func dial(t *testing.T, url string, clients <-chan *Client, types ...EventType) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := <-clients
+
+	if len(types) == 0 {
+		return conn
+	}
+	if err := conn.WriteJSON(clientMessage{Action: "subscribe", Types: types}); err != nil {
+		t.Fatalf("send subscribe: %v", err)
+	}
+
+	// Wait for the server to apply the subscription
+	deadline := time.Now().Add(2 * time.Second)
+	for client.subscribed(EventTypeStatus) {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription was not applied")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return conn
+}
+
+// expectTypes reads events and asserts their types in order
+func expectTypes(t *testing.T, conn *websocket.Conn, want ...EventType) {
+	t.Helper()
+
+	for i, w := range want {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var event Event
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("read event %d: %v", i, err)
+		}
+		if event.Type != w {
+			t.Fatalf("event %d type = %s, want %s", i, event.Type, w)
+		}
+	}
+}
+
+func TestHubSubscriptions(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	url, clients := testServer(t, hub)
+
+	pnlOnly := dial(t, url, clients, EventTypePnL)
+	decisions := dial(t, url, clients, EventTypeDecision, EventTypePnL)
+	everything := dial(t, url, clients)
+
+	hub.BroadcastCandle(exchange.Candle{Close: 100}, "bitcoin", "1h")
+	hub.BroadcastDecision(&bot.Decision{Signal: bot.SignalHold})
+	hub.BroadcastPnL(PnLData{Balance: 10000})
+
+	expectTypes(t, pnlOnly, EventTypePnL)
+	expectTypes(t, decisions, EventTypeDecision, EventTypePnL)
+	expectTypes(t, everything, EventTypeCandle, EventTypeDecision, EventTypePnL)
+}
+
+func TestClientSubscribeResetsToAll(t *testing.T) {
+	client := &Client{}
+
+	client.Subscribe([]EventType{EventTypePnL})
+	if client.subscribed(EventTypeCandle) {
+		t.Error("client subscribed to pnl should not receive candles")
+	}
+
+	client.handleMessage([]byte(`{"action":"subscribe","types":[]}`))
+	if !client.subscribed(EventTypeCandle) {
+		t.Error("empty subscription should restore all event types")
+	}
+}
+
+// waitForHistory blocks until the hub has buffered n events
+func waitForHistory(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(hub.History()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("history = %d events, want %d", len(hub.History()), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHubReplaysHistory(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	url, clients := testServer(t, hub)
+
+	for i := 0; i < 5; i++ {
+		hub.BroadcastCandle(exchange.Candle{Close: float64(100 + i)}, "bitcoin", "1h")
+	}
+	waitForHistory(t, hub, 5)
+
+	conn := dial(t, url, clients)
+	hub.BroadcastStatus("started")
+
+	for i := 0; i < 5; i++ {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var event struct {
+			Type EventType  `json:"type"`
+			Data CandleData `json:"data"`
+		}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("read event %d: %v", i, err)
+		}
+		if event.Type != EventTypeCandle || event.Data.Close != float64(100+i) {
+			t.Fatalf("event %d = %s close %.0f, want candle close %d", i, event.Type, event.Data.Close, 100+i)
+		}
+	}
+	expectTypes(t, conn, EventTypeStatus)
+}
+
+func TestHubHistoryIsBounded(t *testing.T) {
+	hub := NewHubWithHistory(3)
+	go hub.Run()
+
+	for i := 0; i < 5; i++ {
+		hub.BroadcastCandle(exchange.Candle{Close: float64(i)}, "bitcoin", "1h")
+	}
+	hub.BroadcastStatus("done")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		history := hub.History()
+		if len(history) == 3 && history[2].Type == EventTypeStatus {
+			for i, want := range []float64{3, 4} {
+				if got := history[i].Data.(CandleData).Close; got != want {
+					t.Errorf("history[%d] close = %.0f, want %.0f", i, got, want)
+				}
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("history = %+v, want last 3 events", history)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHubStopClosesClients(t *testing.T) {
+	hub := NewHub()
+	stopped := make(chan struct{})
+	go func() {
+		hub.Run()
+		close(stopped)
+	}()
+	url, clients := testServer(t, hub)
+	conn := dial(t, url, clients)
+
+	hub.Stop()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after Stop")
+	}
+
+	// The connection receives a close frame rather than hanging
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNoStatusReceived) {
+		t.Errorf("read after Stop: err = %v, want close frame", err)
+	}
+
+	// Broadcasts and a second Stop after shutdown do not block
+	for i := 0; i < 300; i++ {
+		hub.BroadcastStatus("stopped")
+	}
+	hub.Stop()
+}